@@ -31,6 +31,8 @@ type ServerTransportConfig struct {
 type ServerConfig struct {
 	Name      string                `yaml:"name"`
 	Version   string                `yaml:"version"`
+	LogLevel  string                `yaml:"log_level,omitempty"`
+	LogFormat string                `yaml:"log_format,omitempty"`
 	Transport ServerTransportConfig `yaml:"transport,omitempty"`
 }
 
@@ -38,6 +40,16 @@ type ServerConfig struct {
 type AccessLogsConfig struct {
 	ExcludedHeaders []string `yaml:"excluded_headers"`
 	RedactedHeaders []string `yaml:"redacted_headers"`
+
+	// SampleRate logs only this fraction (0.0 to 1.0) of the requests, picked at random.
+	// Responses with status 400 or above are always logged. Unset logs every request
+	SampleRate *float64 `yaml:"sample_rate,omitempty"`
+}
+
+// ToolLogsConfig represents the ToolLogs middleware configuration
+type ToolLogsConfig struct {
+	RedactedArguments []string `yaml:"redacted_arguments"`
+	MaxArgumentLength int      `yaml:"max_argument_length,omitempty"`
 }
 
 // JWTValidationAllowCondition represents a condition for allowing a request after JWT validation
@@ -51,11 +63,15 @@ type JWTConfig struct {
 	JWKSUri         string                        `yaml:"jwks_uri,omitempty"`
 	CacheInterval   time.Duration                 `yaml:"cache_interval,omitempty"`
 	AllowConditions []JWTValidationAllowCondition `yaml:"allow_conditions,omitempty"`
+
+	// JWKSPassthrough serves the cached JWKS under '/.well-known/jwks.json'
+	JWKSPassthrough bool `yaml:"jwks_passthrough,omitempty"`
 }
 
 // MiddlewareConfig represents the middleware configuration section
 type MiddlewareConfig struct {
 	AccessLogs AccessLogsConfig `yaml:"access_logs"`
+	ToolLogs   ToolLogsConfig   `yaml:"tool_logs"`
 	JWT        JWTConfig        `yaml:"jwt,omitempty"`
 }
 
@@ -91,11 +107,81 @@ type OAuthProtectedResourceConfig struct {
 type ToolPolicyConfig struct {
 	Expression   string   `yaml:"expression"`
 	AllowedTools []string `yaml:"allowed_tools"`
+	DeniedTools  []string `yaml:"denied_tools,omitempty"`
+
+	// Priority sets the evaluation order: higher first. Policies with the same priority keep the file order
+	Priority int `yaml:"priority,omitempty"`
 }
 
 // PoliciesConfig represents the policies configuration section
 type PoliciesConfig struct {
 	Tools []ToolPolicyConfig `yaml:"tools"`
+
+	// AuditLogs logs every policy decision (allowed or denied), with the subject and the matched policy
+	AuditLogs bool `yaml:"audit_logs,omitempty"`
+}
+
+// ToolsConfig represents the tools configuration section
+type ToolsConfig struct {
+	// IdempotencyWindow is how long idempotency keys of write tools are remembered
+	IdempotencyWindow time.Duration `yaml:"idempotency_window,omitempty"`
+
+	// DefaultMaxResults is the 'max_results' used by read tools when the caller does not set it
+	DefaultMaxResults int `yaml:"default_max_results,omitempty"`
+
+	// MaxMaxResults caps the 'max_results' read tools accept. It can not go beyond the API limit (100)
+	MaxMaxResults int `yaml:"max_max_results,omitempty"`
+
+	// MaxNetworkUsers caps how many followers or followed users are fetched per account by
+	// network tools like find_mutuals. It can not go beyond 1000
+	MaxNetworkUsers int `yaml:"max_network_users,omitempty"`
+
+	// MaxActivityChecks caps how many followed accounts find_inactive_following checks, one request each.
+	// It can not go beyond 200
+	MaxActivityChecks int `yaml:"max_activity_checks,omitempty"`
+
+	// CacheTTL caches the responses of read tools for the given time, by tool name and arguments.
+	// Write tools drop the cached responses they make stale
+	CacheTTL map[string]time.Duration `yaml:"cache_ttl,omitempty"`
+
+	// DailyWriteBudget caps the tweets, retweets and likes done in a rolling 24h window, to stay below
+	// the account caps of X. The counts are kept next to the schedule file. Disabled when zero
+	DailyWriteBudget int `yaml:"daily_write_budget,omitempty"`
+
+	// Limits caps the size of tool arguments, checked before any API call
+	Limits ToolLimitsConfig `yaml:"limits,omitempty"`
+
+	// Enabled limits the registered tools to these ones, when set. Disabled tools are never registered.
+	// Both take tool names or categories in the form 'category:<name>'
+	Enabled  []string `yaml:"enabled,omitempty"`
+	Disabled []string `yaml:"disabled,omitempty"`
+}
+
+// ToolLimitsConfig represents the size limits of tool arguments. Unset values use the defaults
+type ToolLimitsConfig struct {
+	// MaxTextBytes caps every string argument (default: 65536)
+	MaxTextBytes int `yaml:"max_text_bytes,omitempty"`
+
+	// MaxThreadTweets caps the tweets of post_thread and scheduled threads (default: 25)
+	MaxThreadTweets int `yaml:"max_thread_tweets,omitempty"`
+
+	// MaxTopics caps the topics of search_topics and get_topics_heat, one search each (default: 10)
+	MaxTopics int `yaml:"max_topics,omitempty"`
+}
+
+// ScheduleConfig represents the scheduling queue configuration
+type ScheduleConfig struct {
+	// MinGap rejects queuing a tweet closer than this to another pending or reviewed one. Disabled when zero
+	MinGap time.Duration `yaml:"min_gap,omitempty"`
+
+	// RejectDuplicates rejects queuing content already in a pending or reviewed tweet, instead of only warning
+	RejectDuplicates bool `yaml:"reject_duplicates,omitempty"`
+
+	// WebhookURL receives a POST with a JSON event after every schedule_publish, successful or not.
+	// WebhookSecret, when set, signs the body with HMAC-SHA256 in the 'X-Twitter-MCP-Signature-256' header
+	WebhookURL     string        `yaml:"webhook_url,omitempty"`
+	WebhookSecret  string        `yaml:"webhook_secret,omitempty"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout,omitempty"`
 }
 
 // TwitterConfig represents the Twitter/X API configuration
@@ -108,6 +194,60 @@ type TwitterConfig struct {
 
 	// OAuth 2.0 Bearer Token (for v2 API - read operations)
 	BearerToken string `yaml:"bearer_token"`
+
+	// OAuth 2.0 user context (optional). When set, it is used for v2 user requests instead of OAuth 1.0a
+	OAuth2 TwitterOAuth2Config `yaml:"oauth2,omitempty"`
+
+	// Whether the account has Academic/Enterprise access to the full-archive search
+	FullArchiveAccess bool `yaml:"full_archive_access,omitempty"`
+
+	// SkipCredentialsCheck disables the GetMe call done on startup to verify the credentials
+	SkipCredentialsCheck bool `yaml:"skip_credentials_check,omitempty"`
+
+	// UserAgent sent on every request. Defaults to 'twitter-mcp/<server.version>'
+	UserAgent string `yaml:"user_agent,omitempty"`
+
+	// BaseURLV1 and BaseURLV2 point the v1.1 and v2 APIs to proxies, mirrors or sandboxes.
+	// Default to 'https://api.twitter.com/1.1' and 'https://api.twitter.com/2'
+	BaseURLV1 string `yaml:"base_url_v1,omitempty"`
+	BaseURLV2 string `yaml:"base_url_v2,omitempty"`
+
+	// Timeout bounds every request. Unset, only bearer token requests are bounded, to 30s
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// ProxyURL sends every request through an HTTP, HTTPS or SOCKS5 proxy, e.g. 'http://proxy.local:3128'
+	ProxyURL string `yaml:"proxy_url,omitempty"`
+
+	// TrendsCacheTTL caches the trends of every location for this long (default: 5m). Negative disables it
+	TrendsCacheTTL time.Duration `yaml:"trends_cache_ttl,omitempty"`
+
+	// Retry of requests answered with 429 or 5xx, with exponential backoff
+	Retry TwitterRetryConfig `yaml:"retry,omitempty"`
+}
+
+// TwitterRetryConfig represents how failed requests are retried. Reads and deletes are always retried,
+// writes only with RetryWrites
+type TwitterRetryConfig struct {
+	// MaxAttempts is how many times a request is tried, the first one included (default: 3). 1 disables retries
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// BaseDelay is the wait before the first retry, doubled on every following one (default: 500ms)
+	BaseDelay time.Duration `yaml:"base_delay,omitempty"`
+
+	// RetryWrites also retries posts, likes, follows and other writes, which may then be applied twice
+	RetryWrites bool `yaml:"retry_writes,omitempty"`
+}
+
+// TwitterOAuth2Config represents the OAuth 2.0 user context credentials.
+// Tokens are obtained beforehand with the Authorization Code with PKCE flow and the 'offline.access' scope
+type TwitterOAuth2Config struct {
+	ClientID     string `yaml:"client_id,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
+	AccessToken  string `yaml:"access_token,omitempty"`
+	RefreshToken string `yaml:"refresh_token,omitempty"`
+
+	// TokenFile stores refreshed tokens, as refresh tokens are rotated on every use
+	TokenFile string `yaml:"token_file,omitempty"`
 }
 
 // Configuration represents the complete configuration structure
@@ -115,8 +255,13 @@ type Configuration struct {
 	Server                   ServerConfig                 `yaml:"server,omitempty"`
 	Middleware               MiddlewareConfig             `yaml:"middleware,omitempty"`
 	Policies                 PoliciesConfig               `yaml:"policies,omitempty"`
+	Tools                    ToolsConfig                  `yaml:"tools,omitempty"`
 	OAuthAuthorizationServer OAuthAuthorizationServer     `yaml:"oauth_authorization_server,omitempty"`
 	OAuthProtectedResource   OAuthProtectedResourceConfig `yaml:"oauth_protected_resource,omitempty"`
 	Twitter                  TwitterConfig                `yaml:"twitter"`
 	ScheduleFile             string                       `yaml:"schedule_file,omitempty"`
+	Schedule                 ScheduleConfig               `yaml:"schedule,omitempty"`
+
+	// Includes lists more config files (or glob patterns) merged over this one, in order
+	Includes []string `yaml:"includes,omitempty"`
 }
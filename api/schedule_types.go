@@ -36,18 +36,29 @@ const (
 
 // ScheduledTweet represents a tweet or thread scheduled for publishing
 type ScheduledTweet struct {
-	ID          string               `yaml:"id"`
-	Type        ScheduledTweetType   `yaml:"type"`
-	Content     []string             `yaml:"content"`
-	ScheduledAt time.Time            `yaml:"scheduled_at"`
-	Reviewed    bool                 `yaml:"reviewed"`
-	Status      ScheduledTweetStatus `yaml:"status"`
-	CreatedAt   time.Time            `yaml:"created_at"`
-	PublishedAt *time.Time           `yaml:"published_at,omitempty"`
-	FailReason  string               `yaml:"fail_reason,omitempty"`
+	ID          string               `yaml:"id" json:"id"`
+	Type        ScheduledTweetType   `yaml:"type" json:"type"`
+	Content     []string             `yaml:"content" json:"content"`
+	ScheduledAt time.Time            `yaml:"scheduled_at" json:"scheduled_at"`
+	Reviewed    bool                 `yaml:"reviewed" json:"reviewed"`
+	Status      ScheduledTweetStatus `yaml:"status" json:"status"`
+	CreatedAt   time.Time            `yaml:"created_at" json:"created_at"`
+	PublishedAt *time.Time           `yaml:"published_at,omitempty" json:"published_at,omitempty"`
+	FailReason  string               `yaml:"fail_reason,omitempty" json:"fail_reason,omitempty"`
+}
+
+// ScheduleStats summarizes the scheduling queue
+type ScheduleStats struct {
+	Counts          map[ScheduledTweetStatus]int `json:"counts"`
+	NextDue         *ScheduledTweet              `json:"next_due,omitempty"`
+	LastPublishedAt *time.Time                   `json:"last_published_at,omitempty"`
+
+	// Blocked is true while the cooldown since the last published tweet has not passed yet
+	Blocked      bool       `json:"blocked"`
+	BlockedUntil *time.Time `json:"blocked_until,omitempty"`
 }
 
 // ScheduleStore represents the full persistence file
 type ScheduleStore struct {
-	ScheduledTweets []ScheduledTweet `yaml:"scheduled_tweets"`
+	ScheduledTweets []ScheduledTweet `yaml:"scheduled_tweets" json:"scheduled_tweets"`
 }
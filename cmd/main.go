@@ -16,12 +16,21 @@ package main
 
 import (
 	"log"
+	"maps"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
+	"twitter-mcp/internal/config"
 	"twitter-mcp/internal/globals"
 	"twitter-mcp/internal/handlers"
 	"twitter-mcp/internal/middlewares"
+	"twitter-mcp/internal/prompts"
+	"twitter-mcp/internal/resources"
 	"twitter-mcp/internal/schedule"
 	"twitter-mcp/internal/tools"
 	"twitter-mcp/internal/twitter"
@@ -37,21 +46,82 @@ func main() {
 		log.Fatalf("failed creating application context: %v", err.Error())
 	}
 
+	// Just validate and show the effective config when asked
+	if appCtx.PrintConfig {
+		if err := validateConfig(appCtx); err != nil {
+			log.Fatalf("invalid configuration: %v", err.Error())
+		}
+
+		configBytes, err := config.Marshal(config.Redact(*appCtx.Config))
+		if err != nil {
+			log.Fatalf("failed marshalling configuration: %v", err.Error())
+		}
+
+		os.Stdout.Write(configBytes)
+		return
+	}
+
 	// 1. Initialize Twitter client
-	twitterClient := twitter.NewClient(
-		appCtx.Config.Twitter.APIKey,
-		appCtx.Config.Twitter.APIKeySecret,
-		appCtx.Config.Twitter.AccessToken,
-		appCtx.Config.Twitter.AccessTokenSecret,
-		appCtx.Config.Twitter.BearerToken,
-	)
+	userAgent := appCtx.Config.Twitter.UserAgent
+	if userAgent == "" {
+		userAgent = twitter.DefaultUserAgent(appCtx.Config.Server.Version)
+	}
+
+	clientOptions := []twitter.Option{
+		twitter.WithCredentials(
+			appCtx.Config.Twitter.APIKey,
+			appCtx.Config.Twitter.APIKeySecret,
+			appCtx.Config.Twitter.AccessToken,
+			appCtx.Config.Twitter.AccessTokenSecret,
+		),
+		twitter.WithBearerToken(appCtx.Config.Twitter.BearerToken),
+		twitter.WithBaseURLs(appCtx.Config.Twitter.BaseURLV1, appCtx.Config.Twitter.BaseURLV2),
+		twitter.WithTimeout(appCtx.Config.Twitter.Timeout),
+		twitter.WithUserAgent(userAgent),
+		twitter.WithRetry(twitter.RetryPolicy{
+			MaxAttempts: appCtx.Config.Twitter.Retry.MaxAttempts,
+			BaseDelay:   appCtx.Config.Twitter.Retry.BaseDelay,
+			RetryWrites: appCtx.Config.Twitter.Retry.RetryWrites,
+		}),
+	}
+	if appCtx.Config.Twitter.ProxyURL != "" {
+		proxyURL, err := url.Parse(appCtx.Config.Twitter.ProxyURL)
+		if err != nil {
+			log.Fatalf("failed parsing twitter.proxy_url: %v", err.Error())
+		}
+		clientOptions = append(clientOptions, twitter.WithProxy(proxyURL))
+	}
+	if trendsCacheTTL := appCtx.Config.Twitter.TrendsCacheTTL; trendsCacheTTL != 0 {
+		clientOptions = append(clientOptions, twitter.WithTrendsCacheTTL(max(trendsCacheTTL, 0)))
+	}
+	twitterClient := twitter.NewClientWithOptions(clientOptions...)
+
+	if appCtx.Config.Twitter.OAuth2.ClientID != "" {
+		err = twitterClient.EnableOAuth2(twitter.OAuth2Credentials{
+			ClientID:     appCtx.Config.Twitter.OAuth2.ClientID,
+			ClientSecret: appCtx.Config.Twitter.OAuth2.ClientSecret,
+			AccessToken:  appCtx.Config.Twitter.OAuth2.AccessToken,
+			RefreshToken: appCtx.Config.Twitter.OAuth2.RefreshToken,
+			TokenFile:    appCtx.Config.Twitter.OAuth2.TokenFile,
+		})
+		if err != nil {
+			log.Fatalf("failed enabling OAuth 2.0 for Twitter client: %v", err.Error())
+		}
+	}
+
+	if !appCtx.Config.Twitter.SkipCredentialsCheck {
+		verifyTwitterCredentials(appCtx, twitterClient)
+	}
 
 	// 2. Initialize schedule store
 	scheduleFile := appCtx.Config.ScheduleFile
 	if scheduleFile == "" {
 		scheduleFile = "schedule.yaml"
 	}
-	scheduleStore, err := schedule.NewStore(scheduleFile)
+	scheduleStore, err := schedule.NewStore(scheduleFile, schedule.Options{
+		MinGap:           appCtx.Config.Schedule.MinGap,
+		RejectDuplicates: appCtx.Config.Schedule.RejectDuplicates,
+	})
 	if err != nil {
 		log.Fatalf("failed creating schedule store: %v", err.Error())
 	}
@@ -68,17 +138,26 @@ func main() {
 		appCtx.Logger.Info("failed starting JWT validation middleware", "error", err.Error())
 	}
 
-	toolPolicyMw, err := middlewares.NewToolPolicyMiddleware(middlewares.ToolPolicyMiddlewareDependencies{
+	toolLogsMw := middlewares.NewToolLogsMiddleware(middlewares.ToolLogsMiddlewareDependencies{
 		AppCtx: appCtx,
 	})
+
+	toolPolicyMw, err := middlewares.NewToolPolicyMiddleware(middlewares.ToolPolicyMiddlewareDependencies{
+		AppCtx:         appCtx,
+		ToolCategories: tools.ToolCategories,
+	})
 	if err != nil {
 		appCtx.Logger.Info("failed starting tool policy middleware", "error", err.Error())
 	}
 
-	// Collect tool middlewares
-	var toolMiddlewares []middlewares.ToolMiddleware
-	if toolPolicyMw != nil && len(appCtx.Config.Policies.Tools) > 0 {
+	// Collect tool middlewares.
+	// Tool logs go first, so denied calls are logged too.
+	// Policy middleware is always there, as policies can appear on config reloads (it allows all without them)
+	toolMiddlewares := []middlewares.ToolMiddleware{toolLogsMw}
+	if toolPolicyMw != nil {
 		toolMiddlewares = append(toolMiddlewares, toolPolicyMw)
+
+		go reloadPoliciesOnSighup(appCtx, toolPolicyMw)
 	}
 
 	// 3. Create a new MCP server
@@ -86,14 +165,17 @@ func main() {
 		appCtx.Config.Server.Name,
 		appCtx.Config.Server.Version,
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
 	)
 
 	// 4. Initialize handlers for later usage
 	hm := handlers.NewHandlersManager(handlers.HandlersManagerDependencies{
-		AppCtx: appCtx,
+		AppCtx:     appCtx,
+		JWKSSource: jwtValidationMw,
 	})
 
-	// 5. Add Twitter tools to your MCP server
+	// 5. Add Twitter tools, resources and prompts to your MCP server
 	tm := tools.NewToolsManager(tools.ToolsManagerDependencies{
 		AppCtx:        appCtx,
 		McpServer:     mcpServer,
@@ -103,6 +185,22 @@ func main() {
 	})
 	tm.AddTools()
 
+	logRuntimeSummary(appCtx, mcpServer, toolMiddlewares, scheduleFile)
+
+	rm := resources.NewResourcesManager(resources.ResourcesManagerDependencies{
+		AppCtx:        appCtx,
+		McpServer:     mcpServer,
+		TwitterClient: twitterClient,
+		ScheduleStore: scheduleStore,
+	})
+	rm.AddResources()
+
+	pm := prompts.NewPromptsManager(prompts.PromptsManagerDependencies{
+		AppCtx:    appCtx,
+		McpServer: mcpServer,
+	})
+	pm.AddPrompts()
+
 	// 6. Wrap MCP server in a transport (stdio, HTTP, SSE)
 	switch appCtx.Config.Server.Transport.Type {
 	case "http":
@@ -121,6 +219,11 @@ func main() {
 				accessLogsMw.Middleware(http.HandlerFunc(hm.HandleOauthAuthorizationServer)))
 		}
 
+		if appCtx.Config.Middleware.JWT.Enabled && appCtx.Config.Middleware.JWT.JWKSPassthrough {
+			mux.Handle("/.well-known/jwks.json",
+				accessLogsMw.Middleware(http.HandlerFunc(hm.HandleJWKS)))
+		}
+
 		if appCtx.Config.OAuthProtectedResource.Enabled {
 			mux.Handle("/.well-known/oauth-protected-resource"+appCtx.Config.OAuthProtectedResource.UrlSuffix,
 				accessLogsMw.Middleware(http.HandlerFunc(hm.HandleOauthProtectedResources)))
@@ -148,3 +251,108 @@ func main() {
 		}
 	}
 }
+
+// logRuntimeSummary logs the effective runtime configuration once tools are registered.
+// Only names and flags are logged, never credentials or secrets
+func logRuntimeSummary(appCtx *globals.ApplicationContext, mcpServer *server.MCPServer,
+	toolMiddlewares []middlewares.ToolMiddleware, scheduleFile string) {
+
+	toolNames := slices.Sorted(maps.Keys(mcpServer.ListTools()))
+
+	transport := appCtx.Config.Server.Transport.Type
+	if transport != "http" {
+		transport = "stdio"
+	}
+
+	// HTTP middlewares only run with the HTTP transport
+	var middlewareNames []string
+	if transport == "http" {
+		middlewareNames = append(middlewareNames, "access_logs")
+		if appCtx.Config.Middleware.JWT.Enabled {
+			middlewareNames = append(middlewareNames, "jwt_validation")
+		}
+	}
+	for _, toolMiddleware := range toolMiddlewares {
+		switch toolMiddleware.(type) {
+		case *middlewares.ToolLogsMiddleware:
+			middlewareNames = append(middlewareNames, "tool_logs")
+		case *middlewares.ToolPolicyMiddleware:
+			middlewareNames = append(middlewareNames, "tool_policy")
+		}
+	}
+
+	appCtx.Logger.Info("runtime configuration",
+		"transport", transport,
+		"tools_count", len(toolNames),
+		"tools", toolNames,
+		"middlewares", middlewareNames,
+		"jwt_enabled", appCtx.Config.Middleware.JWT.Enabled,
+		"policies_enabled", len(appCtx.Config.Policies.Tools) > 0,
+		"policies", len(appCtx.Config.Policies.Tools),
+		"schedule_backend", "file",
+		"schedule_file", scheduleFile,
+	)
+}
+
+// validateConfig checks the config and compiles its CEL expressions, without starting anything
+func validateConfig(appCtx *globals.ApplicationContext) error {
+	if err := config.Validate(*appCtx.Config); err != nil {
+		return err
+	}
+
+	_, err := middlewares.NewToolPolicyMiddleware(middlewares.ToolPolicyMiddlewareDependencies{
+		AppCtx:         appCtx,
+		ToolCategories: tools.ToolCategories,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Building the JWT middleware compiles its allow conditions
+	_, err = middlewares.NewJWTValidationMiddleware(middlewares.JWTValidationMiddlewareDependencies{
+		AppCtx: appCtx,
+	})
+	return err
+}
+
+// reloadPoliciesOnSighup reads the config file again on every SIGHUP and applies its tool policies.
+// Invalid configs are logged and ignored, keeping the running policies. Other settings need a restart
+func reloadPoliciesOnSighup(appCtx *globals.ApplicationContext, toolPolicyMw *middlewares.ToolPolicyMiddleware) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		appCtx.Logger.Info("SIGHUP received, reloading tool policies", "config", appCtx.ConfigPath)
+
+		newConfig, err := config.ReadFile(appCtx.ConfigPath)
+		if err != nil {
+			appCtx.Logger.Error("failed reading config on reload, keeping current policies", "error", err.Error())
+			continue
+		}
+
+		if err := toolPolicyMw.Reload(newConfig.Policies); err != nil {
+			appCtx.Logger.Error("invalid policies on reload, keeping current ones", "error", err.Error())
+			continue
+		}
+
+		appCtx.Logger.Info("tool policies reloaded", "policies", len(newConfig.Policies.Tools))
+	}
+}
+
+// verifyTwitterCredentials calls GetMe to check the credentials, which also caches the authenticated user.
+// Rejected credentials stop the server. Other failures, like rate limits or network errors, are only warned
+func verifyTwitterCredentials(appCtx *globals.ApplicationContext, twitterClient *twitter.Client) {
+	me, err := twitterClient.GetMe(appCtx.Context)
+	if err == nil {
+		appCtx.Logger.Info("Twitter credentials verified", "username", me.Username, "user_id", me.ID)
+		return
+	}
+
+	details := twitter.GetErrorDetails(err)
+	if details.StatusCode == http.StatusUnauthorized {
+		log.Fatalf("Twitter credentials rejected, check the 'twitter' config: %s", details.Message)
+	}
+
+	appCtx.Logger.Warn("could not verify Twitter credentials, tools may fail until it is solved",
+		"code", details.Code, "message", details.Message)
+}
@@ -15,12 +15,24 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
 	"twitter-mcp/api"
 
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// redactedValue replaces secrets in configs meant to be shown
+	redactedValue = "<redacted>"
+)
+
 // Marshal converts a Configuration to YAML bytes
 func Marshal(config api.Configuration) (bytes []byte, err error) {
 	bytes, err = yaml.Marshal(config)
@@ -33,18 +45,205 @@ func Unmarshal(bytes []byte) (config api.Configuration, err error) {
 	return config, err
 }
 
-// ReadFile reads and parses a configuration file
-func ReadFile(filepath string) (config api.Configuration, err error) {
-	var fileBytes []byte
-	fileBytes, err = os.ReadFile(filepath)
+// ReadFile reads and parses a configuration file, merging the files listed in its 'includes'.
+// Includes are merged in order over the base file, so later files override earlier ones
+func ReadFile(filePath string) (config api.Configuration, err error) {
+	err = decodeFile(filePath, &config)
 	if err != nil {
 		return config, err
 	}
 
+	includes, err := resolveIncludes(filePath, config.Includes)
+	if err != nil {
+		return config, err
+	}
+
+	baseIncludes := config.Includes
+	for _, include := range includes {
+		config.Includes = nil
+
+		err = decodeFile(include, &config)
+		if err != nil {
+			return config, fmt.Errorf("error reading included file '%s': %w", include, err)
+		}
+
+		if len(config.Includes) > 0 {
+			return config, fmt.Errorf("included file '%s' has includes too, which is not supported", include)
+		}
+	}
+	config.Includes = baseIncludes
+
+	return config, err
+}
+
+// decodeFile reads a YAML file, expands environment variables in it, and decodes it over the given config.
+// Fields absent from the file keep their current values
+func decodeFile(filePath string, config *api.Configuration) error {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
 	// Expand environment variables present in the config
 	fileExpandedEnv := os.ExpandEnv(string(fileBytes))
 
-	config, err = Unmarshal([]byte(fileExpandedEnv))
+	return yaml.Unmarshal([]byte(fileExpandedEnv), config)
+}
 
-	return config, err
+// resolveIncludes expands the glob patterns of 'includes' into file paths.
+// Relative patterns are relative to the directory of the base file. Matches of each pattern are sorted
+func resolveIncludes(basePath string, patterns []string) ([]string, error) {
+	var includes []string
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(basePath), pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern '%s': %w", pattern, err)
+		}
+
+		// Plain paths that don't exist are an error, empty globs are not
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, fmt.Errorf("included file '%s' does not exist", pattern)
+		}
+
+		sort.Strings(matches)
+		includes = append(includes, matches...)
+	}
+
+	return includes, nil
+}
+
+// Validate checks the configuration for mistakes that would only show up at runtime,
+// such as unknown transports or Twitter credentials left empty by unset environment variables
+func Validate(config api.Configuration) error {
+	var problems []string
+
+	switch config.Server.Transport.Type {
+	case "", "stdio":
+	case "http":
+		if config.Server.Transport.HTTP.Host == "" {
+			problems = append(problems, "server.transport.http.host is required for the http transport")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("server.transport.type '%s' is unknown: use stdio or http", config.Server.Transport.Type))
+	}
+
+	if config.Middleware.JWT.Enabled && config.Middleware.JWT.JWKSUri == "" {
+		problems = append(problems, "middleware.jwt.jwks_uri is required when the JWT middleware is enabled")
+	}
+
+	credentials := []struct {
+		key   string
+		value string
+	}{
+		{key: "twitter.api_key", value: config.Twitter.APIKey},
+		{key: "twitter.api_key_secret", value: config.Twitter.APIKeySecret},
+		{key: "twitter.access_token", value: config.Twitter.AccessToken},
+		{key: "twitter.access_token_secret", value: config.Twitter.AccessTokenSecret},
+		{key: "twitter.bearer_token", value: config.Twitter.BearerToken},
+	}
+	for _, credential := range credentials {
+		if credential.value == "" {
+			problems = append(problems, fmt.Sprintf("%s is empty", credential.key))
+		}
+	}
+
+	if config.Twitter.OAuth2.ClientID != "" && config.Twitter.OAuth2.RefreshToken == "" && config.Twitter.OAuth2.TokenFile == "" {
+		problems = append(problems, "twitter.oauth2.refresh_token is required when twitter.oauth2.client_id is set")
+	}
+
+	limits := config.Tools.Limits
+	if limits.MaxTextBytes < 0 || limits.MaxThreadTweets < 0 || limits.MaxTopics < 0 {
+		problems = append(problems, "tools.limits values can not be negative")
+	}
+
+	if sampleRate := config.Middleware.AccessLogs.SampleRate; sampleRate != nil && (*sampleRate < 0 || *sampleRate > 1) {
+		problems = append(problems, "middleware.access_logs.sample_rate must be between 0.0 and 1.0")
+	}
+
+	if config.Tools.DailyWriteBudget < 0 {
+		problems = append(problems, "tools.daily_write_budget can not be negative")
+	}
+
+	for toolName, ttl := range config.Tools.CacheTTL {
+		if ttl < 0 {
+			problems = append(problems, fmt.Sprintf("tools.cache_ttl.%s can not be negative", toolName))
+		}
+	}
+
+	if config.Schedule.MinGap < 0 {
+		problems = append(problems, "schedule.min_gap can not be negative")
+	}
+
+	if config.Twitter.Timeout < 0 {
+		problems = append(problems, "twitter.timeout can not be negative")
+	}
+
+	if config.Twitter.Retry.MaxAttempts < 0 {
+		problems = append(problems, "twitter.retry.max_attempts can not be negative")
+	}
+	if config.Twitter.Retry.BaseDelay < 0 {
+		problems = append(problems, "twitter.retry.base_delay can not be negative")
+	}
+
+	if proxyURL := config.Twitter.ProxyURL; proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil || !slices.Contains([]string{"http", "https", "socks5"}, parsed.Scheme) || parsed.Host == "" {
+			problems = append(problems, "twitter.proxy_url is not a valid http, https or socks5 URL")
+		}
+	}
+
+	urls := []struct {
+		key   string
+		value string
+	}{
+		{"schedule.webhook_url", config.Schedule.WebhookURL},
+		{"twitter.base_url_v1", config.Twitter.BaseURLV1},
+		{"twitter.base_url_v2", config.Twitter.BaseURLV2},
+	}
+	for _, optionalURL := range urls {
+		if optionalURL.value != "" && !isHTTPURL(optionalURL.value) {
+			problems = append(problems, fmt.Sprintf("%s is not a valid http or https URL", optionalURL.key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// isHTTPURL checks whether a string is an absolute http or https URL
+func isHTTPURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// Redact returns a copy of the configuration with secrets masked, safe to be printed
+func Redact(config api.Configuration) api.Configuration {
+	secrets := []*string{
+		&config.Twitter.APIKey,
+		&config.Twitter.APIKeySecret,
+		&config.Twitter.AccessToken,
+		&config.Twitter.AccessTokenSecret,
+		&config.Twitter.BearerToken,
+		&config.Twitter.OAuth2.ClientSecret,
+		&config.Twitter.OAuth2.AccessToken,
+		&config.Twitter.OAuth2.RefreshToken,
+		// Webhook URLs of chat services, like Slack, carry their token
+		&config.Schedule.WebhookURL,
+		&config.Schedule.WebhookSecret,
+	}
+
+	for _, secret := range secrets {
+		if *secret != "" {
+			*secret = redactedValue
+		}
+	}
+
+	return config
 }
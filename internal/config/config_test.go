@@ -0,0 +1,182 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+)
+
+// writeFile writes a file inside dir, failing the test on error
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	filePath := filepath.Join(dir, name)
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed writing '%s': %v", filePath, err)
+	}
+	return filePath
+}
+
+func TestReadFileWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TEST_TWITTER_API_KEY", "key-from-env")
+
+	basePath := writeFile(t, dir, "config.yaml", `
+server:
+  name: "twitter-mcp"
+  version: "0.1.0"
+twitter:
+  api_key: "placeholder"
+  bearer_token: "base-bearer"
+schedule_file: "schedule.yaml"
+includes:
+  - "secrets.yaml"
+  - "conf.d/*.yaml"
+`)
+	writeFile(t, dir, "secrets.yaml", `
+twitter:
+  api_key: "$TEST_TWITTER_API_KEY"
+  access_token: "secret-token"
+`)
+	os.Mkdir(filepath.Join(dir, "conf.d"), 0o700)
+	writeFile(t, dir, "conf.d/10-schedule.yaml", `schedule_file: "first.yaml"`)
+	writeFile(t, dir, "conf.d/20-schedule.yaml", `schedule_file: "second.yaml"`)
+
+	config, err := ReadFile(basePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.Twitter.APIKey != "key-from-env" {
+		t.Errorf("expected included api_key with env expanded, got '%s'", config.Twitter.APIKey)
+	}
+	if config.Twitter.AccessToken != "secret-token" {
+		t.Errorf("expected included access_token, got '%s'", config.Twitter.AccessToken)
+	}
+	if config.Twitter.BearerToken != "base-bearer" {
+		t.Errorf("expected bearer_token from base file to be kept, got '%s'", config.Twitter.BearerToken)
+	}
+	if config.Server.Name != "twitter-mcp" {
+		t.Errorf("expected server name from base file to be kept, got '%s'", config.Server.Name)
+	}
+	if config.ScheduleFile != "second.yaml" {
+		t.Errorf("expected later includes to win, got '%s'", config.ScheduleFile)
+	}
+}
+
+func TestReadFileIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		files         map[string]string
+		expectedError string
+	}{
+		{
+			name:          "missing include",
+			files:         map[string]string{"config.yaml": `includes: ["missing.yaml"]`},
+			expectedError: "does not exist",
+		},
+		{
+			name: "nested includes",
+			files: map[string]string{
+				"config.yaml": `includes: ["other.yaml"]`,
+				"other.yaml":  `includes: ["config.yaml"]`,
+			},
+			expectedError: "not supported",
+		},
+		{
+			name:  "empty glob",
+			files: map[string]string{"config.yaml": `includes: ["conf.d/*.yaml"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+
+			_, err := ReadFile(filepath.Join(dir, "config.yaml"))
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	config := api.Configuration{}
+	config.Server.Transport.Type = "http"
+	config.Twitter.APIKey = "key"
+	config.Schedule.MinGap = -time.Minute
+	config.Schedule.WebhookURL = "hooks.example.com/publish"
+	config.Twitter.BaseURLV2 = "ftp://mirror.example.com/2"
+	config.Twitter.ProxyURL = "proxy.local:3128"
+	config.Twitter.Retry.MaxAttempts = -1
+	sampleRate := 1.5
+	config.Middleware.AccessLogs.SampleRate = &sampleRate
+
+	err := Validate(config)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	for _, expected := range []string{"server.transport.http.host", "twitter.bearer_token is empty", "schedule.min_gap", "schedule.webhook_url", "twitter.base_url_v2", "twitter.proxy_url", "twitter.retry.max_attempts", "sample_rate"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to mention '%s', got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "twitter.api_key is empty") {
+		t.Errorf("expected filled credentials not to be reported, got: %v", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	config := api.Configuration{}
+	config.Twitter.APIKey = "key"
+	config.Twitter.BearerToken = "bearer"
+	config.ScheduleFile = "schedule.yaml"
+	config.Schedule.WebhookSecret = "secret"
+
+	redacted := Redact(config)
+
+	if redacted.Twitter.APIKey != redactedValue || redacted.Twitter.BearerToken != redactedValue {
+		t.Errorf("expected secrets to be redacted, got %+v", redacted.Twitter)
+	}
+	if redacted.Schedule.WebhookSecret != redactedValue {
+		t.Errorf("expected webhook secret to be redacted, got '%s'", redacted.Schedule.WebhookSecret)
+	}
+	if redacted.Twitter.AccessToken != "" {
+		t.Errorf("expected empty secrets to stay empty, got '%s'", redacted.Twitter.AccessToken)
+	}
+	if redacted.ScheduleFile != "schedule.yaml" {
+		t.Errorf("expected non-secret fields untouched, got '%s'", redacted.ScheduleFile)
+	}
+	if config.Twitter.APIKey != "key" {
+		t.Errorf("expected original config not to be modified")
+	}
+}
@@ -17,8 +17,10 @@ package globals
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"twitter-mcp/api"
 	"twitter-mcp/internal/config"
 )
@@ -27,6 +29,12 @@ type ApplicationContext struct {
 	Context context.Context
 	Logger  *slog.Logger
 	Config  *api.Configuration
+
+	// ConfigPath is the file the config was read from, to read it again on reloads
+	ConfigPath string
+
+	// PrintConfig asks to validate and print the effective config, instead of starting the server
+	PrintConfig bool
 }
 
 func NewApplicationContext() (*ApplicationContext, error) {
@@ -38,13 +46,60 @@ func NewApplicationContext() (*ApplicationContext, error) {
 
 	// Parse and store the config
 	var configFlag = flag.String("config", "config.yaml", "path to the config file")
+	var printConfigFlag = flag.Bool("print-config", false, "validate the config, print it with secrets redacted and exit")
 	flag.Parse()
 
+	appCtx.PrintConfig = *printConfigFlag
+
 	configContent, err := config.ReadFile(*configFlag)
 	if err != nil {
 		return appCtx, err
 	}
 	appCtx.Config = &configContent
+	appCtx.ConfigPath = *configFlag
+
+	// Build the logger according to the config.
+	// LOG_LEVEL env var takes precedence over the config to ease debugging
+	logLevel := appCtx.Config.Server.LogLevel
+	if envLogLevel := os.Getenv("LOG_LEVEL"); envLogLevel != "" {
+		logLevel = envLogLevel
+	}
+
+	logger, err := NewLogger(logLevel, appCtx.Config.Server.LogFormat)
+	if err != nil {
+		return appCtx, err
+	}
+	appCtx.Logger = logger
 
 	return appCtx, nil
 }
+
+// NewLogger creates a structured logger writing to stderr.
+// Level is one of: debug, info, warn, error (default: info).
+// Format is one of: json, text (default: json)
+func NewLogger(level string, format string) (*slog.Logger, error) {
+	var slogLevel slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		slogLevel = slog.LevelDebug
+	case "", "info":
+		slogLevel = slog.LevelInfo
+	case "warn", "warning":
+		slogLevel = slog.LevelWarn
+	case "error":
+		slogLevel = slog.LevelError
+	default:
+		return nil, fmt.Errorf("invalid log level '%s': use debug, info, warn or error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: slogLevel}
+
+	switch strings.ToLower(format) {
+	case "", "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format '%s': use json or text", format)
+	}
+}
@@ -14,18 +14,43 @@
 
 package handlers
 
-import "twitter-mcp/internal/globals"
+import (
+	"net/http"
+	"time"
+
+	"twitter-mcp/internal/globals"
+)
+
+const (
+	// upstreamRequestTimeout bounds every outbound request made by the handlers
+	upstreamRequestTimeout = 10 * time.Second
+)
+
+// JWKSSource provides the JWKS document cached by the JWT validation middleware
+type JWKSSource interface {
+	GetCachedJWKS() []byte
+}
 
 type HandlersManagerDependencies struct {
 	AppCtx *globals.ApplicationContext
+
+	// JWKSSource is only needed when the JWKS passthrough endpoint is enabled
+	JWKSSource JWKSSource
 }
 
 type HandlersManager struct {
 	dependencies HandlersManagerDependencies
+
+	// Carried stuff
+	httpClient               *http.Client
+	oauthAuthorizationServer cachedDocument
 }
 
 func NewHandlersManager(deps HandlersManagerDependencies) *HandlersManager {
 	return &HandlersManager{
 		dependencies: deps,
+		httpClient: &http.Client{
+			Timeout: upstreamRequestTimeout,
+		},
 	}
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+)
+
+// HandleJWKS process requests for endpoint: /.well-known/jwks.json
+// It serves the JWKS cached by the JWT validation middleware, so clients that can not reach
+// the identity provider can still get the keys through this server
+func (h *HandlersManager) HandleJWKS(response http.ResponseWriter, request *http.Request) {
+
+	if h.dependencies.JWKSSource == nil {
+		http.Error(response, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	jwksBytes := h.dependencies.JWKSSource.GetCachedJWKS()
+	if jwksBytes == nil {
+		h.dependencies.AppCtx.Logger.Error("JWKS requested before it was fetched from remote")
+		http.Error(response, "Bad Gateway: JWKS is not available yet", http.StatusBadGateway)
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.Header().Set("Cache-Control", "max-age=300")
+	response.Header().Set("Access-Control-Allow-Origin", "*")
+	response.Header().Set("Access-Control-Allow-Methods", "GET")
+	response.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	_, err := response.Write(jwksBytes)
+	if err != nil {
+		h.dependencies.AppCtx.Logger.Error("error sending response to client", "error", err.Error())
+		return
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeJWKSSource serves a fixed JWKS document
+type fakeJWKSSource struct {
+	body []byte
+}
+
+func (f *fakeJWKSSource) GetCachedJWKS() []byte {
+	return f.body
+}
+
+func TestHandleJWKS(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         JWKSSource
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "cached document",
+			source:         &fakeJWKSSource{body: []byte(`{"keys": [{"kid": "abc", "x5c": ["MIIC"]}]}`)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"keys": [{"kid": "abc", "x5c": ["MIIC"]}]}`,
+		},
+		{
+			name:           "not fetched yet",
+			source:         &fakeJWKSSource{},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "no source",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hm := newTestHandlersManager("")
+			hm.dependencies.JWKSSource = tt.source
+
+			recorder := httptest.NewRecorder()
+			hm.HandleJWKS(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+			if tt.expectedBody != "" && recorder.Body.String() != tt.expectedBody {
+				t.Errorf("expected body to be served untouched, got '%s'", recorder.Body.String())
+			}
+		})
+	}
+}
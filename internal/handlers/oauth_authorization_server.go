@@ -15,31 +15,47 @@
 package handlers
 
 import (
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	// oauthAuthorizationServerDefaultTTL is used when upstream does not send a usable Cache-Control max-age
+	oauthAuthorizationServerDefaultTTL = 1 * time.Hour
+)
+
+// cachedDocument keeps the last-good copy of a remote document in memory
+type cachedDocument struct {
+	mutex      sync.Mutex
+	body       []byte
+	ttl        time.Duration
+	expiresAt  time.Time
+	refreshing bool
+}
+
 // HandleOauthAuthorizationServer process requests for endpoint: /.well-known/oauth-authorization-server
 func (h *HandlersManager) HandleOauthAuthorizationServer(response http.ResponseWriter, request *http.Request) {
 
-	remoteUrl := h.dependencies.AppCtx.Config.OAuthAuthorizationServer.IssuerUri + "/.well-known/openid-configuration"
-	remoteResponse, err := http.Get(remoteUrl)
+	remoteResponseBytes, ttl, err := h.getOauthAuthorizationServerDocument()
 	if err != nil {
 		h.dependencies.AppCtx.Logger.Error("error getting content from /.well-known/openid-configuration", "error", err.Error())
-		http.Error(response, "Internal Server Error", http.StatusInternalServerError)
-		return
-	}
-
-	//
-	remoteResponseBytes, err := io.ReadAll(remoteResponse.Body)
-	if err != nil {
-		h.dependencies.AppCtx.Logger.Error("error reading bytes from remote response", "error", err.Error())
-		http.Error(response, "Internal Server Error", http.StatusInternalServerError)
+		http.Error(response, "Bad Gateway: authorization server metadata is not available", http.StatusBadGateway)
 		return
 	}
 
 	response.Header().Set("Content-Type", "application/json")
-	response.Header().Set("Cache-Control", "max-age=3600")
+	// Clients are told the same as upstream told us
+	if ttl > 0 {
+		response.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(ttl.Seconds())))
+	} else {
+		response.Header().Set("Cache-Control", "no-cache")
+	}
 	response.Header().Set("Access-Control-Allow-Origin", "*")
 	response.Header().Set("Access-Control-Allow-Methods", "GET")
 	response.Header().Set("Access-Control-Allow-Headers", "Content-Type")
@@ -50,3 +66,117 @@ func (h *HandlersManager) HandleOauthAuthorizationServer(response http.ResponseW
 		return
 	}
 }
+
+// getOauthAuthorizationServerDocument returns the cached openid-configuration document and how long it can be cached.
+// The first call fetches it synchronously. Later calls serve the cached copy, triggering a background refresh
+// once it expires. Documents upstream forbids caching (zero TTL) are fetched on every call instead.
+// On fetch failures, the last-good copy is served
+func (h *HandlersManager) getOauthAuthorizationServerDocument() ([]byte, time.Duration, error) {
+	cache := &h.oauthAuthorizationServer
+
+	cache.mutex.Lock()
+	if cache.body != nil && cache.ttl > 0 {
+		body, ttl := cache.body, cache.ttl
+		if time.Now().After(cache.expiresAt) && !cache.refreshing {
+			cache.refreshing = true
+			go h.refreshOauthAuthorizationServerDocument()
+		}
+		cache.mutex.Unlock()
+		return body, ttl, nil
+	}
+	lastGood := cache.body
+	cache.mutex.Unlock()
+
+	body, ttl, err := h.fetchOauthAuthorizationServerDocument()
+	if err != nil {
+		if lastGood != nil {
+			h.dependencies.AppCtx.Logger.Warn("error fetching /.well-known/openid-configuration, serving last-good copy", "error", err.Error())
+			return lastGood, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	cache.mutex.Lock()
+	cache.body = body
+	cache.ttl = ttl
+	cache.expiresAt = time.Now().Add(ttl)
+	cache.mutex.Unlock()
+
+	return body, ttl, nil
+}
+
+// refreshOauthAuthorizationServerDocument updates the cached document in the background
+func (h *HandlersManager) refreshOauthAuthorizationServerDocument() {
+	cache := &h.oauthAuthorizationServer
+
+	body, ttl, err := h.fetchOauthAuthorizationServerDocument()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.refreshing = false
+
+	if err != nil {
+		h.dependencies.AppCtx.Logger.Warn("error refreshing /.well-known/openid-configuration, serving last-good copy", "error", err.Error())
+		return
+	}
+
+	cache.body = body
+	cache.ttl = ttl
+	cache.expiresAt = time.Now().Add(ttl)
+}
+
+// fetchOauthAuthorizationServerDocument retrieves the openid-configuration document from the issuer.
+// It also returns how long the document can be cached, according to upstream Cache-Control
+func (h *HandlersManager) fetchOauthAuthorizationServerDocument() ([]byte, time.Duration, error) {
+	remoteUrl := h.dependencies.AppCtx.Config.OAuthAuthorizationServer.IssuerUri + "/.well-known/openid-configuration"
+
+	remoteResponse, err := h.httpClient.Get(remoteUrl)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error requesting remote document: %w", err)
+	}
+	defer remoteResponse.Body.Close()
+
+	//
+	remoteResponseBytes, err := io.ReadAll(remoteResponse.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading bytes from remote response: %w", err)
+	}
+
+	if remoteResponse.StatusCode < 200 || remoteResponse.StatusCode >= 300 {
+		return nil, 0, fmt.Errorf("unexpected status code from remote: %d", remoteResponse.StatusCode)
+	}
+
+	// Never forward arbitrary bytes (HTML error pages, etc.) as metadata
+	mediaType, _, err := mime.ParseMediaType(remoteResponse.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil, 0, fmt.Errorf("unexpected content type from remote: '%s'", remoteResponse.Header.Get("Content-Type"))
+	}
+
+	return remoteResponseBytes, getCacheControlMaxAge(remoteResponse.Header, oauthAuthorizationServerDefaultTTL), nil
+}
+
+// getCacheControlMaxAge extracts max-age from a Cache-Control header.
+// Zero is returned when caching is forbidden (no-store, no-cache or max-age=0),
+// and the default value when the header is absent or its max-age invalid
+func getCacheControlMaxAge(header http.Header, defaultVal time.Duration) time.Duration {
+	maxAge := defaultVal
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+
+		if directive == "no-store" || directive == "no-cache" {
+			return 0
+		}
+
+		value, found := strings.CutPrefix(directive, "max-age=")
+		if !found {
+			continue
+		}
+
+		seconds, err := strconv.Atoi(value)
+		if err == nil && seconds >= 0 {
+			maxAge = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return maxAge
+}
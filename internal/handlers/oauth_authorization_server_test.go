@@ -0,0 +1,205 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/globals"
+)
+
+func newTestHandlersManager(issuerUri string) *HandlersManager {
+	return NewHandlersManager(HandlersManagerDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Config: &api.Configuration{
+				OAuthAuthorizationServer: api.OAuthAuthorizationServer{
+					Enabled:   true,
+					IssuerUri: issuerUri,
+				},
+			},
+		},
+	})
+}
+
+func TestHandleOauthAuthorizationServer(t *testing.T) {
+	tests := []struct {
+		name           string
+		upstream       http.HandlerFunc
+		clientTimeout  time.Duration
+		expectedStatus int
+	}{
+		{
+			name: "valid upstream document",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.Write([]byte(`{"issuer": "https://idp.example.com"}`))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "upstream error status",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "boom", http.StatusInternalServerError)
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name: "upstream non-json content",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte("<html></html>"))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name: "slow upstream",
+			upstream: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{}`))
+			},
+			clientTimeout:  50 * time.Millisecond,
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(tt.upstream)
+			defer upstream.Close()
+
+			h := newTestHandlersManager(upstream.URL)
+			if tt.clientTimeout > 0 {
+				h.httpClient.Timeout = tt.clientTimeout
+			}
+
+			recorder := httptest.NewRecorder()
+			h.HandleOauthAuthorizationServer(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-authorization-server", nil))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
+
+func TestHandleOauthAuthorizationServerServesLastGood(t *testing.T) {
+	failing := false
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer": "https://idp.example.com"}`))
+	}))
+	defer upstream.Close()
+
+	h := newTestHandlersManager(upstream.URL)
+
+	recorder := httptest.NewRecorder()
+	h.HandleOauthAuthorizationServer(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	// Expire the cached copy and break upstream
+	failing = true
+	h.oauthAuthorizationServer.mutex.Lock()
+	h.oauthAuthorizationServer.expiresAt = time.Now().Add(-time.Minute)
+	h.oauthAuthorizationServer.mutex.Unlock()
+
+	recorder = httptest.NewRecorder()
+	h.HandleOauthAuthorizationServer(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected last-good copy with status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if recorder.Body.String() != `{"issuer": "https://idp.example.com"}` {
+		t.Errorf("unexpected body: %s", recorder.Body.String())
+	}
+}
+
+func TestGetCacheControlMaxAge(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{"", time.Hour},
+		{"max-age=300", 5 * time.Minute},
+		{"public, max-age=60", time.Minute},
+		{"no-store", 0},
+		{"no-cache", 0},
+		{"max-age=300, no-store", 0},
+		{"max-age=0", 0},
+		{"max-age=abc", time.Hour},
+		{"max-age=-5", time.Hour},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("Cache-Control", tt.header)
+
+		result := getCacheControlMaxAge(header, time.Hour)
+		if result != tt.expected {
+			t.Errorf("getCacheControlMaxAge(%q) = %v, expected %v", tt.header, result, tt.expected)
+		}
+	}
+}
+
+func TestHandleOauthAuthorizationServerHonorsNoStore(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 2 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte(fmt.Sprintf(`{"issuer": "https://idp.example.com", "version": %d}`, requests.Load())))
+	}))
+	defer upstream.Close()
+
+	h := newTestHandlersManager(upstream.URL)
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		h.HandleOauthAuthorizationServer(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	serve()
+	recorder := serve()
+	if requests.Load() != 2 || !strings.Contains(recorder.Body.String(), `"version": 2`) {
+		t.Errorf("expected the document to be fetched on every call, got %d requests and %s", requests.Load(), recorder.Body.String())
+	}
+	if cacheControl := recorder.Header().Get("Cache-Control"); cacheControl != "no-cache" {
+		t.Errorf("expected clients to be told not to cache it, got '%s'", cacheControl)
+	}
+
+	// Upstream breaks: the last-good copy is served
+	recorder = serve()
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"version": 2`) {
+		t.Errorf("expected the last-good copy, got %d and %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/parser"
+)
+
+// claimHelpers are the CEL functions available in JWT allow conditions and tool policies.
+// Each one can be called as 'hasScope("x")', checking the JWT payload, or as 'hasScope(payload, "x")'
+var claimHelpers = map[string]func(payload map[string]any, value string) bool{
+	"hasScope": payloadHasScope,
+	"hasRole":  payloadHasRole,
+	"inGroup":  payloadInGroup,
+}
+
+// claimsLib registers the claim helpers in a CEL environment. Use it as cel.Lib(claimsLib{})
+type claimsLib struct{}
+
+func (claimsLib) CompileOptions() []cel.EnvOption {
+	var options []cel.EnvOption
+
+	for name, helper := range claimHelpers {
+		options = append(options,
+			cel.Function(name,
+				cel.Overload(name+"_dyn_string", []*cel.Type{cel.DynType, cel.StringType}, cel.BoolType,
+					cel.BinaryBinding(claimHelperBinding(helper)),
+				),
+			),
+			cel.Macros(cel.GlobalMacro(name, 1, payloadMacroExpander(name))),
+		)
+	}
+
+	return options
+}
+
+func (claimsLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+// payloadMacroExpander rewrites 'name(x)' into 'name(payload, x)'
+func payloadMacroExpander(name string) parser.MacroExpander {
+	return func(eh parser.ExprHelper, target ast.Expr, args []ast.Expr) (ast.Expr, *common.Error) {
+		return eh.NewCall(name, eh.NewIdent("payload"), args[0]), nil
+	}
+}
+
+// claimHelperBinding adapts a claim helper to CEL values. Payloads that are not maps never match
+func claimHelperBinding(helper func(payload map[string]any, value string) bool) func(lhs, rhs ref.Val) ref.Val {
+	return func(lhs, rhs ref.Val) ref.Val {
+		native, err := lhs.ConvertToNative(reflect.TypeOf(map[string]any{}))
+		if err != nil {
+			return types.False
+		}
+		payload, ok := native.(map[string]any)
+		if !ok {
+			return types.False
+		}
+
+		value, ok := rhs.(types.String)
+		if !ok {
+			return types.False
+		}
+
+		return types.Bool(helper(payload, string(value)))
+	}
+}
+
+// payloadHasScope checks the 'scope' claim (space-delimited string, as in RFC 8693)
+// and the 'scp' claim (array or string, as used by some providers)
+func payloadHasScope(payload map[string]any, scope string) bool {
+	return slices.Contains(claimValues(payload["scope"]), scope) ||
+		slices.Contains(claimValues(payload["scp"]), scope)
+}
+
+// payloadHasRole checks the 'roles' and 'role' claims, and the nested roles used by Keycloak:
+// 'realm_access.roles' and 'resource_access.<client>.roles'
+func payloadHasRole(payload map[string]any, role string) bool {
+	if slices.Contains(claimValues(payload["roles"]), role) ||
+		slices.Contains(claimValues(payload["role"]), role) {
+		return true
+	}
+
+	if realmAccess, ok := payload["realm_access"].(map[string]any); ok {
+		if slices.Contains(claimValues(realmAccess["roles"]), role) {
+			return true
+		}
+	}
+
+	if resourceAccess, ok := payload["resource_access"].(map[string]any); ok {
+		for _, client := range resourceAccess {
+			if clientAccess, ok := client.(map[string]any); ok && slices.Contains(claimValues(clientAccess["roles"]), role) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// payloadInGroup checks the 'groups' claim. Group paths like '/admins' also match 'admins'
+func payloadInGroup(payload map[string]any, group string) bool {
+	for _, value := range claimValues(payload["groups"]) {
+		if value == group || strings.TrimPrefix(value, "/") == group {
+			return true
+		}
+	}
+	return false
+}
+
+// claimValues flattens a claim into its values. Strings are split by spaces,
+// arrays keep their string items and anything else has no values
+func claimValues(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	case []string:
+		return value
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+)
+
+func TestPayloadHasScope(t *testing.T) {
+	tests := []struct {
+		payload  map[string]any
+		scope    string
+		expected bool
+	}{
+		{payload: map[string]any{"scope": "tweet.read tweet.write"}, scope: "tweet.write", expected: true},
+		{payload: map[string]any{"scope": "tweet.read tweet.writer"}, scope: "tweet.write", expected: false},
+		{payload: map[string]any{"scp": []any{"tweet.read", "tweet.write"}}, scope: "tweet.write", expected: true},
+		{payload: map[string]any{"scp": "tweet.write"}, scope: "tweet.write", expected: true},
+		{payload: map[string]any{}, scope: "tweet.write", expected: false},
+	}
+
+	for _, tt := range tests {
+		if result := payloadHasScope(tt.payload, tt.scope); result != tt.expected {
+			t.Errorf("payloadHasScope(%v, %q) = %v, expected %v", tt.payload, tt.scope, result, tt.expected)
+		}
+	}
+}
+
+func TestPayloadHasRole(t *testing.T) {
+	tests := []struct {
+		payload  map[string]any
+		role     string
+		expected bool
+	}{
+		{payload: map[string]any{"roles": []any{"admin", "editor"}}, role: "editor", expected: true},
+		{payload: map[string]any{"role": "editor"}, role: "editor", expected: true},
+		{payload: map[string]any{"realm_access": map[string]any{"roles": []any{"editor"}}}, role: "editor", expected: true},
+		{payload: map[string]any{"resource_access": map[string]any{
+			"twitter-mcp": map[string]any{"roles": []any{"editor"}},
+		}}, role: "editor", expected: true},
+		{payload: map[string]any{"roles": []any{"admin"}}, role: "editor", expected: false},
+	}
+
+	for _, tt := range tests {
+		if result := payloadHasRole(tt.payload, tt.role); result != tt.expected {
+			t.Errorf("payloadHasRole(%v, %q) = %v, expected %v", tt.payload, tt.role, result, tt.expected)
+		}
+	}
+}
+
+func TestPayloadInGroup(t *testing.T) {
+	tests := []struct {
+		payload  map[string]any
+		group    string
+		expected bool
+	}{
+		{payload: map[string]any{"groups": []any{"writers", "readers"}}, group: "writers", expected: true},
+		{payload: map[string]any{"groups": []any{"/writers"}}, group: "writers", expected: true},
+		{payload: map[string]any{"groups": "writers"}, group: "writers", expected: true},
+		{payload: map[string]any{"groups": []any{"writers-team"}}, group: "writers", expected: false},
+		{payload: map[string]any{}, group: "writers", expected: false},
+	}
+
+	for _, tt := range tests {
+		if result := payloadInGroup(tt.payload, tt.group); result != tt.expected {
+			t.Errorf("payloadInGroup(%v, %q) = %v, expected %v", tt.payload, tt.group, result, tt.expected)
+		}
+	}
+}
+
+func TestClaimsLibExpressions(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("payload", cel.DynType),
+		cel.Lib(claimsLib{}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating CEL environment: %v", err)
+	}
+
+	payload := map[string]any{
+		"scope":        "tweet.read tweet.write",
+		"groups":       []any{"/writers"},
+		"realm_access": map[string]any{"roles": []any{"editor"}},
+	}
+
+	tests := map[string]bool{
+		`hasScope("tweet.write")`:                     true,
+		`hasScope(payload, "tweet.read")`:             true,
+		`hasScope("dm.write")`:                        false,
+		`hasRole("editor") && inGroup("writers")`:     true,
+		`inGroup("admins") || hasScope("tweet.read")`: true,
+		`hasRole("admin")`:                            false,
+	}
+
+	for expression, expected := range tests {
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			t.Fatalf("failed compiling '%s': %v", expression, issues.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("failed building program for '%s': %v", expression, err)
+		}
+
+		out, _, err := prg.Eval(map[string]any{"payload": payload})
+		if err != nil {
+			t.Fatalf("failed evaluating '%s': %v", expression, err)
+		}
+		if out.Value() != expected {
+			t.Errorf("'%s' = %v, expected %v", expression, out.Value(), expected)
+		}
+	}
+}
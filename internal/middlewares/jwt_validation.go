@@ -41,8 +41,9 @@ type JWTValidationMiddleware struct {
 	dependencies JWTValidationMiddlewareDependencies
 
 	// Carried stuff
-	jwks  *JWKS
-	mutex sync.Mutex
+	jwks     *JWKS
+	jwksBody []byte
+	mutex    sync.Mutex
 
 	//
 	celPrograms []*cel.Program
@@ -63,6 +64,7 @@ func NewJWTValidationMiddleware(deps JWTValidationMiddlewareDependencies) (*JWTV
 	// They will be truly used later.
 	allowConditionsEnv, err := cel.NewEnv(
 		cel.Variable("payload", cel.DynType),
+		cel.Lib(claimsLib{}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("CEL environment creation error: %s", err.Error())
@@ -21,6 +21,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
 	"strings"
@@ -64,6 +65,7 @@ func (mw *JWTValidationMiddleware) cacheJWKS() {
 
 	for {
 		var jwks JWKS
+		var jwksBody []byte
 
 		//
 		resp, err := http.Get(mw.dependencies.AppCtx.Config.Middleware.JWT.JWKSUri)
@@ -72,17 +74,24 @@ func (mw *JWTValidationMiddleware) cacheJWKS() {
 			goto haveANap
 		}
 
+		// Raw bytes are kept too, to serve them untouched on the JWKS passthrough endpoint
+		jwksBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			mw.dependencies.AppCtx.Logger.Error("failed reading JWKS from remote", "error", err.Error())
+			goto haveANap
+		}
+
 		//
-		if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
-			resp.Body.Close()
+		if err := json.Unmarshal(jwksBody, &jwks); err != nil {
 			mw.dependencies.AppCtx.Logger.Error("failed decoding JWKS from remote", "error", err.Error())
 			goto haveANap
 		}
-		resp.Body.Close()
 
 		//
 		mw.mutex.Lock()
 		mw.jwks = &jwks
+		mw.jwksBody = jwksBody
 		mw.mutex.Unlock()
 
 		// Don't be greedy, man
@@ -91,6 +100,14 @@ func (mw *JWTValidationMiddleware) cacheJWKS() {
 	}
 }
 
+// GetCachedJWKS returns the last JWKS document fetched from remote, as it was received.
+// It is nil until the first successful fetch
+func (mw *JWTValidationMiddleware) GetCachedJWKS() []byte {
+	mw.mutex.Lock()
+	defer mw.mutex.Unlock()
+	return mw.jwksBody
+}
+
 func (mw *JWTValidationMiddleware) isTokenValid(token string) (bool, error) {
 	// Get JWT header
 	header, err := parseJWTHeader(token)
@@ -15,11 +15,20 @@
 package middlewares
 
 import (
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
+
 	"twitter-mcp/internal/globals"
 )
 
+const (
+	// redactedHeaderPrefixLength is how many characters of long redacted header values are logged
+	redactedHeaderPrefixLength = 10
+)
+
 type AccessLogsMiddlewareDependencies struct {
 	AppCtx *globals.ApplicationContext
 }
@@ -39,25 +48,16 @@ func (mw *AccessLogsMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 
 		start := time.Now()
-		next.ServeHTTP(rw, req)
+		recorder := &responseRecorder{ResponseWriter: rw}
+		next.ServeHTTP(recorder, req)
 		duration := time.Since(start)
 
-		filteredHeaders := req.Header.Clone()
-		// Redact selected headers
-		for _, redactedHeader := range mw.dependencies.AppCtx.Config.Middleware.AccessLogs.RedactedHeaders {
-			tmpHeader := filteredHeaders.Get(redactedHeader)
-
-			if len(tmpHeader) >= 10 {
-				filteredHeaders.Set(redactedHeader, tmpHeader[:10]+"***")
-				continue
-			}
-			filteredHeaders.Set(redactedHeader, "***")
+		accessLogsConfig := mw.dependencies.AppCtx.Config.Middleware.AccessLogs
+		if !shouldLogRequest(recorder.Status(), accessLogsConfig.SampleRate) {
+			return
 		}
 
-		// Exclude selected headers
-		for _, excludedHeader := range mw.dependencies.AppCtx.Config.Middleware.AccessLogs.ExcludedHeaders {
-			filteredHeaders.Del(excludedHeader)
-		}
+		filteredHeaders := filterHeaders(req.Header, accessLogsConfig.RedactedHeaders, accessLogsConfig.ExcludedHeaders)
 
 		mw.dependencies.AppCtx.Logger.Info("AccessLogsMiddleware output",
 			"method", req.Method,
@@ -65,7 +65,94 @@ func (mw *AccessLogsMiddleware) Middleware(next http.Handler) http.Handler {
 			"remote_addr", req.RemoteAddr,
 			"user_agent", req.UserAgent(),
 			"headers", filteredHeaders,
+			"status", recorder.Status(),
+			"bytes_written", recorder.bytesWritten,
 			"request_duration", duration.String(),
 		)
 	})
 }
+
+// shouldLogRequest decides whether a request is logged: errors always are, the rest according to the sample rate
+func shouldLogRequest(status int, sampleRate *float64) bool {
+	if status >= http.StatusBadRequest || sampleRate == nil {
+		return true
+	}
+	return rand.Float64() < *sampleRate
+}
+
+// filterHeaders returns a copy of the headers with the redacted ones masked and the excluded ones removed.
+// Names match case-insensitively, and every value of multi-value headers is masked
+func filterHeaders(headers http.Header, redacted, excluded []string) http.Header {
+	filtered := make(http.Header, len(headers))
+
+	for name, values := range headers {
+		if slices.ContainsFunc(excluded, func(excludedName string) bool { return strings.EqualFold(name, excludedName) }) {
+			continue
+		}
+
+		if !slices.ContainsFunc(redacted, func(redactedName string) bool { return strings.EqualFold(name, redactedName) }) {
+			filtered[name] = slices.Clone(values)
+			continue
+		}
+
+		masked := make([]string, 0, len(values))
+		for _, value := range values {
+			masked = append(masked, redactHeaderValue(value))
+		}
+		filtered[name] = masked
+	}
+
+	return filtered
+}
+
+// redactHeaderValue keeps the first characters of long values, which is enough to tell tokens apart.
+// Shorter values are fully masked, as the prefix would give most of them away
+func redactHeaderValue(value string) string {
+	if len(value) > 2*redactedHeaderPrefixLength {
+		return value[:redactedHeaderPrefixLength] + "***"
+	}
+	return "***"
+}
+
+// responseRecorder wraps a ResponseWriter to capture the status code and the size of the response
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode   int
+	bytesWritten int64
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush keeps streaming responses (SSE) working through the wrapper
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the wrapped ResponseWriter to http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the status code sent, which is 200 when the handler never set one
+func (r *responseRecorder) Status() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+	return r.statusCode
+}
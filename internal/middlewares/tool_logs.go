@@ -0,0 +1,128 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"twitter-mcp/internal/globals"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultToolLogsMaxArgumentLength is the max length for string arguments before truncating them
+	defaultToolLogsMaxArgumentLength = 100
+)
+
+type ToolLogsMiddlewareDependencies struct {
+	AppCtx *globals.ApplicationContext
+}
+
+type ToolLogsMiddleware struct {
+	dependencies ToolLogsMiddlewareDependencies
+}
+
+func NewToolLogsMiddleware(dependencies ToolLogsMiddlewareDependencies) *ToolLogsMiddleware {
+	return &ToolLogsMiddleware{
+		dependencies: dependencies,
+	}
+}
+
+func (mw *ToolLogsMiddleware) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+
+		start := time.Now()
+		result, err := next(ctx, request)
+		duration := time.Since(start)
+
+		logArgs := []any{
+			"tool", request.Params.Name,
+			"arguments", mw.redactArguments(request.GetArguments()),
+			"request_duration", duration.String(),
+		}
+
+		switch {
+		case err != nil:
+			mw.dependencies.AppCtx.Logger.Error("ToolLogsMiddleware output",
+				append(logArgs, "success", false, "error", err.Error())...)
+		case result != nil && result.IsError:
+			mw.dependencies.AppCtx.Logger.Warn("ToolLogsMiddleware output",
+				append(logArgs, "success", false, "error", getResultText(result))...)
+		default:
+			mw.dependencies.AppCtx.Logger.Info("ToolLogsMiddleware output",
+				append(logArgs, "success", true)...)
+		}
+
+		return result, err
+	}
+}
+
+// redactArguments returns a copy of the arguments safe to be logged:
+// selected arguments are masked and long strings are truncated
+func (mw *ToolLogsMiddleware) redactArguments(args map[string]any) map[string]any {
+	config := mw.dependencies.AppCtx.Config.Middleware.ToolLogs
+
+	maxLength := config.MaxArgumentLength
+	if maxLength <= 0 {
+		maxLength = defaultToolLogsMaxArgumentLength
+	}
+
+	filteredArgs := make(map[string]any, len(args))
+	for key, value := range args {
+		if slices.Contains(config.RedactedArguments, key) {
+			filteredArgs[key] = "***"
+			continue
+		}
+		filteredArgs[key] = truncateArgument(value, maxLength)
+	}
+
+	return filteredArgs
+}
+
+// truncateArgument shortens strings, also the ones inside arrays, to maxLength characters
+func truncateArgument(value any, maxLength int) any {
+	switch typedValue := value.(type) {
+	case string:
+		runes := []rune(typedValue)
+		if len(runes) > maxLength {
+			return fmt.Sprintf("%s...(%d chars)", string(runes[:maxLength]), len(runes))
+		}
+		return typedValue
+	case []any:
+		truncated := make([]any, 0, len(typedValue))
+		for _, item := range typedValue {
+			truncated = append(truncated, truncateArgument(item, maxLength))
+		}
+		return truncated
+	default:
+		return value
+	}
+}
+
+// getResultText joins the text contents of a tool result
+func getResultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text += textContent.Text
+		}
+	}
+	return text
+}
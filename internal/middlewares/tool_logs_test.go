@@ -0,0 +1,73 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"strings"
+	"testing"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/globals"
+)
+
+func TestRedactArguments(t *testing.T) {
+	mw := NewToolLogsMiddleware(ToolLogsMiddlewareDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Config: &api.Configuration{
+				Middleware: api.MiddlewareConfig{
+					ToolLogs: api.ToolLogsConfig{
+						RedactedArguments: []string{"secret"},
+						MaxArgumentLength: 5,
+					},
+				},
+			},
+		},
+	})
+
+	args := map[string]any{
+		"secret":      "top-secret",
+		"message":     "hello there",
+		"text":        "a long tweet text",
+		"short":       "abc",
+		"max_results": float64(10),
+		"tweets":      []any{"first tweet", "2nd"},
+	}
+
+	result := mw.redactArguments(args)
+
+	if result["secret"] != "***" {
+		t.Errorf("expected 'secret' to be masked, got '%v'", result["secret"])
+	}
+	if result["message"] != "hello...(11 chars)" {
+		t.Errorf("expected 'message' not to be masked unless configured, got '%v'", result["message"])
+	}
+	if text, _ := result["text"].(string); !strings.HasPrefix(text, "a lon...") {
+		t.Errorf("expected 'text' to be truncated, got '%v'", result["text"])
+	}
+	if result["short"] != "abc" {
+		t.Errorf("expected 'short' to be untouched, got '%v'", result["short"])
+	}
+	if result["max_results"] != float64(10) {
+		t.Errorf("expected 'max_results' to be untouched, got '%v'", result["max_results"])
+	}
+	if tweets, _ := result["tweets"].([]any); len(tweets) != 2 || tweets[1] != "2nd" {
+		t.Errorf("expected 'tweets' items to be truncated individually, got '%v'", result["tweets"])
+	}
+
+	// Original arguments must not be modified
+	if args["secret"] != "top-secret" {
+		t.Errorf("original arguments were modified")
+	}
+}
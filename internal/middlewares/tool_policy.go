@@ -17,8 +17,12 @@ package middlewares
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
 	"strings"
+	"sync"
 
+	"twitter-mcp/api"
 	"twitter-mcp/internal/globals"
 
 	"github.com/google/cel-go/cel"
@@ -26,19 +30,46 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// CompiledToolPolicy holds a precompiled CEL program and its allowed tools
+// CompiledToolPolicy holds a precompiled CEL program and its allowed and denied tools
 type CompiledToolPolicy struct {
 	Program      cel.Program
 	AllowedTools []string
+	DeniedTools  []string
+	Priority     int
+
+	// Index and Expression identify the policy in the config, for audit logs
+	Index      int
+	Expression string
+}
+
+// policyDecision is the outcome of evaluating the policies for a tool call
+type policyDecision struct {
+	Allowed bool
+
+	// Policy is the policy that decided, or nil when none listed the tool
+	Policy *CompiledToolPolicy
 }
 
+// toolCategoryPrefix marks a category in 'allowed_tools' or 'denied_tools', e.g. 'category:write'
+const toolCategoryPrefix = "category:"
+
 type ToolPolicyMiddlewareDependencies struct {
 	AppCtx *globals.ApplicationContext
+
+	// ToolCategories maps category names to the tools in them, to resolve 'category:<name>' entries
+	ToolCategories map[string][]string
 }
 
 type ToolPolicyMiddleware struct {
-	dependencies     ToolPolicyMiddlewareDependencies
+	dependencies ToolPolicyMiddlewareDependencies
+
+	// Carried stuff
+	celEnv *cel.Env
+
+	// Policies can be swapped at runtime by Reload, so they are guarded
+	mutex            sync.RWMutex
 	compiledPolicies []CompiledToolPolicy
+	auditLogger      *slog.Logger
 }
 
 func NewToolPolicyMiddleware(deps ToolPolicyMiddlewareDependencies) (*ToolPolicyMiddleware, error) {
@@ -47,39 +78,96 @@ func NewToolPolicyMiddleware(deps ToolPolicyMiddlewareDependencies) (*ToolPolicy
 	}
 
 	// Create CEL environment for policy evaluation
+	// Apart from the JWT payload, expressions can look at the called tool and its arguments
 	env, err := cel.NewEnv(
 		cel.Variable("payload", cel.DynType),
+		cel.Variable("tool", cel.StringType),
+		cel.Variable("args", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Lib(claimsLib{}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("CEL environment creation error: %s", err.Error())
 	}
+	mw.celEnv = env
+
+	if err := mw.Reload(deps.AppCtx.Config.Policies); err != nil {
+		return nil, err
+	}
+
+	return mw, nil
+}
+
+// Reload compiles the given policies and swaps them with the current ones.
+// On errors, current policies are kept untouched
+func (mw *ToolPolicyMiddleware) Reload(policies api.PoliciesConfig) error {
+	compiledPolicies, err := mw.compilePolicies(policies.Tools)
+	if err != nil {
+		return err
+	}
 
-	// Precompile all policy expressions
-	for _, policy := range deps.AppCtx.Config.Policies.Tools {
-		ast, issues := env.Compile(policy.Expression)
+	var auditLogger *slog.Logger
+	if policies.AuditLogs {
+		auditLogger = mw.dependencies.AppCtx.Logger.With("log_type", "audit")
+	}
+
+	mw.mutex.Lock()
+	mw.compiledPolicies = compiledPolicies
+	mw.auditLogger = auditLogger
+	mw.mutex.Unlock()
+
+	return nil
+}
+
+// compilePolicies precompiles all policy expressions, sorted by evaluation order
+func (mw *ToolPolicyMiddleware) compilePolicies(policies []api.ToolPolicyConfig) ([]CompiledToolPolicy, error) {
+	var compiledPolicies []CompiledToolPolicy
+
+	for index, policy := range policies {
+		ast, issues := mw.celEnv.Compile(policy.Expression)
 		if issues != nil && issues.Err() != nil {
 			return nil, fmt.Errorf("CEL policy compilation error for expression '%s': %s", policy.Expression, issues.Err())
 		}
 
-		prg, err := env.Program(ast)
+		prg, err := mw.celEnv.Program(ast)
 		if err != nil {
 			return nil, fmt.Errorf("CEL program construction error: %s", err.Error())
 		}
 
-		mw.compiledPolicies = append(mw.compiledPolicies, CompiledToolPolicy{
+		for _, entry := range slices.Concat(policy.AllowedTools, policy.DeniedTools) {
+			category, found := strings.CutPrefix(entry, toolCategoryPrefix)
+			if _, exists := mw.dependencies.ToolCategories[category]; found && !exists {
+				mw.dependencies.AppCtx.Logger.Warn("unknown tool category in policy", "category", category, "expression", policy.Expression)
+			}
+		}
+
+		compiledPolicies = append(compiledPolicies, CompiledToolPolicy{
 			Program:      prg,
 			AllowedTools: policy.AllowedTools,
+			DeniedTools:  policy.DeniedTools,
+			Priority:     policy.Priority,
+			Index:        index,
+			Expression:   policy.Expression,
 		})
 	}
 
-	return mw, nil
+	// Higher priority policies are checked first. Equal priorities keep the config order
+	slices.SortStableFunc(compiledPolicies, func(a, b CompiledToolPolicy) int {
+		return b.Priority - a.Priority
+	})
+
+	return compiledPolicies, nil
 }
 
 // Middleware wraps a tool handler and checks if the tool is allowed based on JWT claims
 func (mw *ToolPolicyMiddleware) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mw.mutex.RLock()
+		compiledPolicies := mw.compiledPolicies
+		auditLogger := mw.auditLogger
+		mw.mutex.RUnlock()
+
 		// If no policies configured, allow all
-		if len(mw.compiledPolicies) == 0 {
+		if len(compiledPolicies) == 0 {
 			return next(ctx, request)
 		}
 
@@ -94,45 +182,109 @@ func (mw *ToolPolicyMiddleware) Middleware(next server.ToolHandlerFunc) server.T
 
 		toolName := request.Params.Name
 
-		// Check each policy - first matching policy wins
-		for _, policy := range mw.compiledPolicies {
-			out, _, err := policy.Program.Eval(map[string]interface{}{
-				"payload": payload,
-			})
+		args := request.GetArguments()
+		if args == nil {
+			args = map[string]any{}
+		}
 
-			if err != nil {
-				mw.dependencies.AppCtx.Logger.Error("CEL policy evaluation error", "error", err.Error())
-				continue
-			}
+		decision := mw.isToolPermitted(compiledPolicies, toolName, args, payload)
+		auditDecision(auditLogger, toolName, payload, decision)
 
-			// If expression matches, check if tool is allowed
-			if out.Value() == true {
-				if mw.isToolAllowed(toolName, policy.AllowedTools) {
-					return next(ctx, request)
-				}
-			}
+		if decision.Allowed {
+			return next(ctx, request)
 		}
 
 		// No policy matched or tool not in allowed list
-		mw.dependencies.AppCtx.Logger.Warn("tool access denied by policy",
-			"tool", toolName,
-		)
+		if auditLogger == nil {
+			mw.dependencies.AppCtx.Logger.Warn("tool access denied by policy",
+				"tool", toolName,
+			)
+		}
 		return mcp.NewToolResultError(fmt.Sprintf("Access denied: you don't have permission to use '%s'", toolName)), nil
 	}
 }
 
-// isToolAllowed checks if a tool is in the allowed list
-func (mw *ToolPolicyMiddleware) isToolAllowed(toolName string, allowedTools []string) bool {
-	for _, allowed := range allowedTools {
-		if allowed == "*" {
+// isToolPermitted evaluates the policies, already in priority order, for the given tool call and JWT payload.
+// The first matching policy listing the tool decides. Within it, 'denied_tools' beats 'allowed_tools'.
+// Matching policies that don't list the tool are skipped, and the tool is denied when none lists it
+func (mw *ToolPolicyMiddleware) isToolPermitted(compiledPolicies []CompiledToolPolicy,
+	toolName string, args map[string]any, payload map[string]interface{}) policyDecision {
+	for i := range compiledPolicies {
+		policy := &compiledPolicies[i]
+
+		out, _, err := policy.Program.Eval(map[string]interface{}{
+			"payload": payload,
+			"tool":    toolName,
+			"args":    args,
+		})
+
+		if err != nil {
+			mw.dependencies.AppCtx.Logger.Error("CEL policy evaluation error", "error", err.Error())
+			continue
+		}
+
+		if out.Value() != true {
+			continue
+		}
+
+		if mw.isToolListed(toolName, policy.DeniedTools) {
+			return policyDecision{Allowed: false, Policy: policy}
+		}
+		if mw.isToolListed(toolName, policy.AllowedTools) {
+			return policyDecision{Allowed: true, Policy: policy}
+		}
+	}
+
+	return policyDecision{Allowed: false}
+}
+
+// auditDecision logs a policy decision, when audit logs are enabled (auditLogger is not nil)
+func auditDecision(auditLogger *slog.Logger, toolName string, payload map[string]interface{}, decision policyDecision) {
+	if auditLogger == nil {
+		return
+	}
+
+	result := "deny"
+	if decision.Allowed {
+		result = "allow"
+	}
+
+	subject, _ := payload["sub"].(string)
+
+	attrs := []any{
+		"tool", toolName,
+		"subject", subject,
+		"decision", result,
+	}
+	if decision.Policy != nil {
+		attrs = append(attrs,
+			"policy_index", decision.Policy.Index,
+			"policy_expression", decision.Policy.Expression,
+		)
+	}
+
+	auditLogger.Info("tool policy decision", attrs...)
+}
+
+// isToolListed checks if a tool is in a list of tool names, prefixes or categories
+func (mw *ToolPolicyMiddleware) isToolListed(toolName string, tools []string) bool {
+	for _, entry := range tools {
+		if entry == "*" {
 			return true
 		}
-		if allowed == toolName {
+		if entry == toolName {
 			return true
 		}
+		// Support categories (e.g., "category:write" matches "post_tweet")
+		if category, found := strings.CutPrefix(entry, toolCategoryPrefix); found {
+			if slices.Contains(mw.dependencies.ToolCategories[category], toolName) {
+				return true
+			}
+			continue
+		}
 		// Support prefix matching with * (e.g., "get_*" matches "get_timeline")
-		if strings.HasSuffix(allowed, "*") {
-			prefix := strings.TrimSuffix(allowed, "*")
+		if strings.HasSuffix(entry, "*") {
+			prefix := strings.TrimSuffix(entry, "*")
 			if strings.HasPrefix(toolName, prefix) {
 				return true
 			}
@@ -15,11 +15,44 @@
 package middlewares
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/globals"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
-func TestIsToolAllowed(t *testing.T) {
-	mw := &ToolPolicyMiddleware{}
+// newTestToolPolicyMiddleware builds a ToolPolicyMiddleware for the given policies
+func newTestToolPolicyMiddleware(t *testing.T, policies []api.ToolPolicyConfig) *ToolPolicyMiddleware {
+	t.Helper()
+
+	mw, err := NewToolPolicyMiddleware(ToolPolicyMiddlewareDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Config: &api.Configuration{
+				Policies: api.PoliciesConfig{Tools: policies},
+			},
+		},
+		ToolCategories: map[string][]string{
+			"read":  {"get_timeline", "get_me"},
+			"write": {"post_tweet", "delete_tweet"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating middleware: %v", err)
+	}
+	return mw
+}
+
+func TestIsToolListed(t *testing.T) {
+	mw := newTestToolPolicyMiddleware(t, nil)
 
 	tests := []struct {
 		toolName     string
@@ -33,13 +66,14 @@ func TestIsToolAllowed(t *testing.T) {
 		{"post_tweet", []string{"get_*"}, false},
 		{"get_mentions", []string{"get_timeline", "get_mentions"}, true},
 		{"search_tweets", []string{"search_*", "get_*"}, true},
+		{"delete_tweet", []string{"category:write"}, true},
+		{"get_me", []string{"category:write"}, false},
+		{"get_me", []string{"category:unknown"}, false},
 	}
 
 	for _, tt := range tests {
-		result := mw.isToolAllowed(tt.toolName, tt.allowedTools)
-		if result != tt.expected {
-			t.Errorf("isToolAllowed(%s, %v) = %v, expected %v",
-				tt.toolName, tt.allowedTools, result, tt.expected)
+		if result := mw.isToolListed(tt.toolName, tt.allowedTools); result != tt.expected {
+			t.Errorf("isToolListed(%q, %v) = %v, expected %v", tt.toolName, tt.allowedTools, result, tt.expected)
 		}
 	}
 }
@@ -48,3 +82,186 @@ func TestGetRequestScheme(t *testing.T) {
 	// Test without headers (should return http)
 	// This is a basic test - full testing would require http.Request mocking
 }
+
+func TestIsToolPermittedPrecedence(t *testing.T) {
+	mw := newTestToolPolicyMiddleware(t, []api.ToolPolicyConfig{
+		{
+			Expression:   `payload.role == "editor"`,
+			AllowedTools: []string{"*"},
+			DeniedTools:  []string{"delete_tweet"},
+		},
+		{
+			Expression:   `payload.role == "editor"`,
+			AllowedTools: []string{"delete_tweet"},
+		},
+		{
+			Expression:   `payload.role == "reader"`,
+			AllowedTools: []string{"category:read"},
+		},
+		{
+			Expression:   `payload.role == "reader"`,
+			AllowedTools: []string{"post_tweet"},
+		},
+		{
+			Expression:   `payload.role == "owner"`,
+			AllowedTools: []string{"*"},
+			Priority:     10,
+		},
+		{
+			Expression:  `true`,
+			DeniedTools: []string{"category:write"},
+			Priority:    5,
+		},
+	})
+
+	tests := []struct {
+		role     string
+		toolName string
+		expected bool
+	}{
+		// Deny beats allow within a matched policy, and later policies are not checked
+		{role: "editor", toolName: "delete_tweet", expected: false},
+		// A matched policy not listing the tool lets the next ones decide
+		{role: "reader", toolName: "get_me", expected: true},
+		// ...unless a higher priority policy denies it first
+		{role: "reader", toolName: "post_tweet", expected: false},
+		{role: "editor", toolName: "post_tweet", expected: false},
+		// Higher priority allows win over lower priority denies
+		{role: "owner", toolName: "delete_tweet", expected: true},
+		// Tools not listed by any matching policy are denied
+		{role: "stranger", toolName: "get_me", expected: false},
+	}
+
+	for _, tt := range tests {
+		payload := map[string]interface{}{"role": tt.role}
+		if result := mw.isToolPermitted(mw.compiledPolicies, tt.toolName, map[string]any{}, payload).Allowed; result != tt.expected {
+			t.Errorf("isToolPermitted(%q) for role '%s' = %v, expected %v", tt.toolName, tt.role, result, tt.expected)
+		}
+	}
+}
+
+func TestIsToolPermittedWithArguments(t *testing.T) {
+	mw := newTestToolPolicyMiddleware(t, []api.ToolPolicyConfig{
+		{
+			Expression:   `payload.role == "intern" && tool == "post_tweet" && has(args.text) && size(args.text) < 20`,
+			AllowedTools: []string{"post_tweet"},
+		},
+		{
+			Expression:   `payload.role == "intern"`,
+			AllowedTools: []string{"get_*"},
+		},
+	})
+
+	payload := map[string]interface{}{"role": "intern"}
+
+	tests := []struct {
+		toolName string
+		args     map[string]any
+		expected bool
+	}{
+		{toolName: "post_tweet", args: map[string]any{"text": "short tweet"}, expected: true},
+		{toolName: "post_tweet", args: map[string]any{"text": "this tweet is way too long for an intern"}, expected: false},
+		{toolName: "post_tweet", args: map[string]any{}, expected: false},
+		{toolName: "get_me", args: map[string]any{}, expected: true},
+	}
+
+	for _, tt := range tests {
+		if result := mw.isToolPermitted(mw.compiledPolicies, tt.toolName, tt.args, payload).Allowed; result != tt.expected {
+			t.Errorf("isToolPermitted(%q, %v) = %v, expected %v", tt.toolName, tt.args, result, tt.expected)
+		}
+	}
+}
+
+func TestToolPolicyAuditLogs(t *testing.T) {
+	var output bytes.Buffer
+
+	mw, err := NewToolPolicyMiddleware(ToolPolicyMiddlewareDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Logger: slog.New(slog.NewJSONHandler(&output, nil)),
+			Config: &api.Configuration{
+				Policies: api.PoliciesConfig{
+					AuditLogs: true,
+					Tools: []api.ToolPolicyConfig{
+						{Expression: `payload.role == "reader"`, AllowedTools: []string{"get_*"}},
+						{Expression: `payload.role == "writer"`, AllowedTools: []string{"*"}, Priority: 1},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating middleware: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), JWTContextKey, map[string]interface{}{
+		"sub":  "alice",
+		"role": "reader",
+	})
+	handler := mw.Middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	for _, toolName := range []string{"get_me", "post_tweet"} {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = toolName
+		if _, err := handler(ctx, request); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		entry := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed parsing log line '%s': %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d: %s", len(entries), output.String())
+	}
+
+	allowed := entries[0]
+	if allowed["log_type"] != "audit" || allowed["decision"] != "allow" || allowed["subject"] != "alice" ||
+		allowed["tool"] != "get_me" || allowed["policy_index"] != float64(0) || allowed["policy_expression"] != `payload.role == "reader"` {
+		t.Errorf("unexpected allow entry: %v", allowed)
+	}
+
+	denied := entries[1]
+	if denied["decision"] != "deny" || denied["tool"] != "post_tweet" {
+		t.Errorf("unexpected deny entry: %v", denied)
+	}
+	if _, found := denied["policy_index"]; found {
+		t.Errorf("expected no policy in deny entry without a deciding policy: %v", denied)
+	}
+}
+
+func TestToolPolicyReload(t *testing.T) {
+	mw := newTestToolPolicyMiddleware(t, []api.ToolPolicyConfig{
+		{Expression: `true`, AllowedTools: []string{"get_*"}},
+	})
+
+	payload := map[string]interface{}{"sub": "alice"}
+
+	err := mw.Reload(api.PoliciesConfig{
+		Tools: []api.ToolPolicyConfig{{Expression: `true`, AllowedTools: []string{"*"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error reloading valid policies: %v", err)
+	}
+	if !mw.isToolPermitted(mw.compiledPolicies, "post_tweet", map[string]any{}, payload).Allowed {
+		t.Errorf("expected reloaded policies to allow 'post_tweet'")
+	}
+
+	// Invalid policies must not replace the current ones
+	err = mw.Reload(api.PoliciesConfig{
+		Tools: []api.ToolPolicyConfig{{Expression: `this is not CEL`, AllowedTools: []string{"get_*"}}},
+	})
+	if err == nil {
+		t.Fatalf("expected error reloading invalid policies")
+	}
+	if !mw.isToolPermitted(mw.compiledPolicies, "post_tweet", map[string]any{}, payload).Allowed {
+		t.Errorf("expected previous policies to be kept after a failed reload")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultThreadTweets is the thread length drafted when not given
+	defaultThreadTweets = 5
+
+	// maxThreadTweets bounds the drafted threads, as longer ones lose readers
+	maxThreadTweets = 25
+
+	// defaultMentionsToSummarize is how many mentions are read when not given
+	defaultMentionsToSummarize = 20
+)
+
+// HandlePromptDraftThread handles the draft_thread prompt
+func (pm *PromptsManager) HandlePromptDraftThread(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+
+	topic := strings.TrimSpace(args["topic"])
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+
+	tweets, err := getIntArgument(args, "tweets", defaultThreadTweets, maxThreadTweets)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Draft a Twitter/X thread of %d tweets about: %s\n\n", tweets, topic)
+	if tone := strings.TrimSpace(args["tone"]); tone != "" {
+		fmt.Fprintf(&text, "Use a %s tone.\n\n", tone)
+	}
+	text.WriteString("Guidelines:\n")
+	text.WriteString("- The first tweet must hook the reader on its own, as it is the one shown in timelines\n")
+	text.WriteString("- Every tweet must fit in 280 characters. Check each one with preview_tweet, which counts links and emoji like Twitter does\n")
+	text.WriteString("- Keep hashtags to one or two, at the end of the thread\n\n")
+	text.WriteString("Show me the draft and wait for my approval. Then post it with post_thread, or queue it with schedule_tweet (type 'thread') if I give you a date.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Thread about %s", topic),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text.String()))},
+	), nil
+}
+
+// HandlePromptSummarizeMentions handles the summarize_mentions prompt
+func (pm *PromptsManager) HandlePromptSummarizeMentions(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	maxResults, err := getIntArgument(request.Params.Arguments, "max_results", defaultMentionsToSummarize, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Get my latest %d mentions with get_mentions and summarize them:\n"+
+		"- Group them by theme (questions, feedback, praise, complaints, spam)\n"+
+		"- For each group, give a one-line summary and the most relevant tweets with their authors\n"+
+		"- Flag the mentions worth answering first, and why\n\n"+
+		"Do not reply, like or retweet anything unless I ask for it.", maxResults)
+
+	return mcp.NewGetPromptResult(
+		"Summary of recent mentions",
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
+
+// HandlePromptAnalyzeEngagement handles the analyze_engagement prompt
+func (pm *PromptsManager) HandlePromptAnalyzeEngagement(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	tweetID, err := twitter.ParseTweetID(request.Params.Arguments["tweet_id"])
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Analyze the engagement of the tweet %s:\n"+
+		"- Get who liked it with get_liking_users, who retweeted it with get_retweeters, and its quotes with get_quote_tweets\n"+
+		"- Describe the audience: are they followers, people in the same field, bots?\n"+
+		"- Summarize what the quotes say about it\n"+
+		"- Explain what made it work or not, and suggest how to do better next time", tweetID)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Engagement analysis of tweet %s", tweetID),
+		[]mcp.PromptMessage{mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text))},
+	), nil
+}
+
+// getIntArgument parses an optional integer prompt argument between 1 and max.
+// Prompt arguments are always strings, unlike tool ones
+func getIntArgument(args map[string]string, key string, defaultValue, max int) (int, error) {
+	raw := strings.TrimSpace(args[key])
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 || value > max {
+		return 0, fmt.Errorf("%s must be a number between 1 and %d", key, max)
+	}
+	return value, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newPromptRequest(args map[string]string) mcp.GetPromptRequest {
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func promptText(t *testing.T, result *mcp.GetPromptResult) string {
+	t.Helper()
+
+	if len(result.Messages) != 1 {
+		t.Fatalf("expected a single message, got %d", len(result.Messages))
+	}
+	content, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Messages[0].Content)
+	}
+	return content.Text
+}
+
+func TestHandlePromptDraftThread(t *testing.T) {
+	pm := NewPromptsManager(PromptsManagerDependencies{})
+
+	result, err := pm.HandlePromptDraftThread(context.Background(), newPromptRequest(map[string]string{
+		"topic":  "Go generics",
+		"tweets": "3",
+		"tone":   "technical",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := promptText(t, result)
+	for _, expected := range []string{"3 tweets about: Go generics", "technical tone", "preview_tweet"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected prompt to contain '%s', got: %s", expected, text)
+		}
+	}
+
+	for name, args := range map[string]map[string]string{
+		"missing topic":  {},
+		"invalid tweets": {"topic": "Go", "tweets": "many"},
+		"too many":       {"topic": "Go", "tweets": "100"},
+	} {
+		if _, err := pm.HandlePromptDraftThread(context.Background(), newPromptRequest(args)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestHandlePromptAnalyzeEngagement(t *testing.T) {
+	pm := NewPromptsManager(PromptsManagerDependencies{})
+
+	result, err := pm.HandlePromptAnalyzeEngagement(context.Background(), newPromptRequest(map[string]string{
+		"tweet_id": "https://x.com/user/status/1234567890",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := promptText(t, result); !strings.Contains(text, "tweet 1234567890") {
+		t.Errorf("expected the tweet ID parsed from the URL, got: %s", text)
+	}
+
+	if _, err := pm.HandlePromptAnalyzeEngagement(context.Background(), newPromptRequest(nil)); err == nil {
+		t.Errorf("expected an error without tweet_id")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"twitter-mcp/internal/globals"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type PromptsManagerDependencies struct {
+	AppCtx *globals.ApplicationContext
+
+	McpServer *server.MCPServer
+}
+
+type PromptsManager struct {
+	dependencies PromptsManagerDependencies
+}
+
+func NewPromptsManager(deps PromptsManagerDependencies) *PromptsManager {
+	return &PromptsManager{
+		dependencies: deps,
+	}
+}
+
+// AddPrompts registers the prompt templates for common workflows.
+// They only build messages pointing to the tools, so they never call the API themselves
+func (pm *PromptsManager) AddPrompts() {
+
+	// draft_thread - Draft a thread about a topic
+	prompt := mcp.NewPrompt("draft_thread",
+		mcp.WithPromptDescription("Draft a Twitter/X thread about a topic, checked with preview_tweet before posting or scheduling it"),
+		mcp.WithArgument("topic",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("What the thread is about"),
+		),
+		mcp.WithArgument("tweets",
+			mcp.ArgumentDescription("How many tweets the thread should have (default: 5)"),
+		),
+		mcp.WithArgument("tone",
+			mcp.ArgumentDescription("Tone of the thread, like 'casual', 'technical' or 'promotional'"),
+		),
+	)
+	pm.dependencies.McpServer.AddPrompt(prompt, pm.HandlePromptDraftThread)
+
+	// summarize_mentions - Summarize recent mentions
+	prompt = mcp.NewPrompt("summarize_mentions",
+		mcp.WithPromptDescription("Summarize recent mentions of the authenticated account, grouped by theme, flagging the ones worth answering"),
+		mcp.WithArgument("max_results",
+			mcp.ArgumentDescription("How many mentions to read (default: 20)"),
+		),
+	)
+	pm.dependencies.McpServer.AddPrompt(prompt, pm.HandlePromptSummarizeMentions)
+
+	// analyze_engagement - Analyze the engagement of a tweet
+	prompt = mcp.NewPrompt("analyze_engagement",
+		mcp.WithPromptDescription("Analyze who engaged with a tweet (likes, retweets, quotes) and what made it work or not"),
+		mcp.WithArgument("tweet_id",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The ID or URL of the tweet"),
+		),
+	)
+	pm.dependencies.McpServer.AddPrompt(prompt, pm.HandlePromptAnalyzeEngagement)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// resourceCacheTTL is how long resources backed by the Twitter API are cached.
+	// Clients may read them on every turn, which would burn the rate limits otherwise
+	resourceCacheTTL = 5 * time.Minute
+)
+
+// cachedResource represents the marshaled contents of a resource
+type cachedResource struct {
+	text      string
+	expiresAt time.Time
+}
+
+// resourceCache keeps the JSON contents of resources by URI for a while
+type resourceCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedResource
+}
+
+func newResourceCache(ttl time.Duration) *resourceCache {
+	return &resourceCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedResource),
+	}
+}
+
+// GetOrFetch returns the cached contents of a resource, calling fetch and marshaling its result
+// when they are missing or expired. Errors are not cached
+func (c *resourceCache) GetOrFetch(uri string, fetch func() (any, error)) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, found := c.entries[uri]; found && time.Now().Before(entry.expiresAt) {
+		return entry.text, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return "", err
+	}
+
+	text, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resource '%s': %w", uri, err)
+	}
+
+	c.entries[uri] = cachedResource{text: string(text), expiresAt: time.Now().Add(c.ttl)}
+	return string(text), nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResourceCache(t *testing.T) {
+	cache := newResourceCache(50 * time.Millisecond)
+
+	calls := 0
+	fetch := func() (any, error) {
+		calls++
+		return map[string]int{"calls": calls}, nil
+	}
+
+	first, err := cache.GetOrFetch("me://profile", fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := cache.GetOrFetch("me://profile", fetch)
+	if first != `{"calls":1}` || second != first || calls != 1 {
+		t.Errorf("expected a single fetch while cached, got '%s', '%s' after %d calls", first, second, calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if third, _ := cache.GetOrFetch("me://profile", fetch); third != `{"calls":2}` {
+		t.Errorf("expected a new fetch after expiring, got '%s'", third)
+	}
+}
+
+func TestResourceCacheSkipsErrors(t *testing.T) {
+	cache := newResourceCache(time.Minute)
+
+	_, err := cache.GetOrFetch("trends://worldwide", func() (any, error) {
+		return nil, errors.New("rate limited")
+	})
+	if err == nil {
+		t.Fatalf("expected the fetch error")
+	}
+
+	text, err := cache.GetOrFetch("trends://worldwide", func() (any, error) {
+		return []string{"golang"}, nil
+	})
+	if err != nil || text != `["golang"]` {
+		t.Errorf("expected errors not to be cached, got '%s' (%v)", text, err)
+	}
+}
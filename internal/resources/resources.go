@@ -0,0 +1,79 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"twitter-mcp/internal/globals"
+	"twitter-mcp/internal/schedule"
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ResourcesManagerDependencies struct {
+	AppCtx *globals.ApplicationContext
+
+	McpServer     *server.MCPServer
+	TwitterClient *twitter.Client
+	ScheduleStore *schedule.Store
+}
+
+type ResourcesManager struct {
+	dependencies ResourcesManagerDependencies
+
+	// Carried stuff
+	cache *resourceCache
+}
+
+func NewResourcesManager(deps ResourcesManagerDependencies) *ResourcesManager {
+	return &ResourcesManager{
+		dependencies: deps,
+		cache:        newResourceCache(resourceCacheTTL),
+	}
+}
+
+// AddResources registers the read-only resources clients can pull as context, without calling tools.
+// Tool policies and the tools.enabled/disabled lists do not apply to resources
+func (rm *ResourcesManager) AddResources() {
+
+	// schedule://pending - Tweets waiting to be published
+	resource := mcp.NewResource(schedulePendingURI, "Pending scheduled tweets",
+		mcp.WithResourceDescription("Scheduled tweets and threads not published yet (pending and reviewed), sorted by scheduled time"),
+		mcp.WithMIMEType(jsonMIMEType),
+	)
+	rm.dependencies.McpServer.AddResource(resource, rm.HandleResourceSchedulePending)
+
+	// schedule://all - Whole scheduling queue
+	resource = mcp.NewResource(scheduleAllURI, "All scheduled tweets",
+		mcp.WithResourceDescription("Every scheduled tweet and thread in any status, including failed ones with their fail_reason"),
+		mcp.WithMIMEType(jsonMIMEType),
+	)
+	rm.dependencies.McpServer.AddResource(resource, rm.HandleResourceScheduleAll)
+
+	// me://profile - Authenticated user profile
+	resource = mcp.NewResource(profileURI, "Authenticated profile",
+		mcp.WithResourceDescription("Profile of the authenticated user, with bio and follower, following and tweet counts. Cached for 5 minutes"),
+		mcp.WithMIMEType(jsonMIMEType),
+	)
+	rm.dependencies.McpServer.AddResource(resource, rm.HandleResourceProfile)
+
+	// trends://worldwide - Worldwide trending topics
+	resource = mcp.NewResource(trendsWorldwideURI, "Worldwide trends",
+		mcp.WithResourceDescription("Current worldwide trending topics with their tweet volume. Cached for 5 minutes"),
+		mcp.WithMIMEType(jsonMIMEType),
+	)
+	rm.dependencies.McpServer.AddResource(resource, rm.HandleResourceTrendsWorldwide)
+}
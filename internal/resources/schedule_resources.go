@@ -0,0 +1,76 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"twitter-mcp/api"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	schedulePendingURI = "schedule://pending"
+	scheduleAllURI     = "schedule://all"
+
+	jsonMIMEType = "application/json"
+)
+
+// HandleResourceSchedulePending handles the schedule://pending resource
+func (rm *ResourcesManager) HandleResourceSchedulePending(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	tweets := slices.Concat(
+		rm.dependencies.ScheduleStore.List(api.ScheduledTweetStatusPending),
+		rm.dependencies.ScheduleStore.List(api.ScheduledTweetStatusReviewed),
+	)
+	slices.SortStableFunc(tweets, func(a, b api.ScheduledTweet) int {
+		return a.ScheduledAt.Compare(b.ScheduledAt)
+	})
+
+	return jsonResourceContents(schedulePendingURI, tweets)
+}
+
+// HandleResourceScheduleAll handles the schedule://all resource
+func (rm *ResourcesManager) HandleResourceScheduleAll(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return jsonResourceContents(scheduleAllURI, rm.dependencies.ScheduleStore.List(""))
+}
+
+// jsonResourceContents marshals a value as the JSON text contents of a resource.
+// Nil slices are sent as empty arrays, so clients always get a list
+func jsonResourceContents[T any](uri string, items []T) ([]mcp.ResourceContents, error) {
+	if items == nil {
+		items = []T{}
+	}
+
+	text, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource '%s': %w", uri, err)
+	}
+
+	return textResourceContents(uri, string(text)), nil
+}
+
+// textResourceContents wraps JSON text as the contents of a resource
+func textResourceContents(uri string, text string) []mcp.ResourceContents {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: jsonMIMEType,
+			Text:     text,
+		},
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleResourceSchedulePending(t *testing.T) {
+	store, err := schedule.NewStore(filepath.Join(t.TempDir(), "schedule.yaml"), schedule.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	rm := NewResourcesManager(ResourcesManagerDependencies{ScheduleStore: store})
+
+	contents, err := rm.HandleResourceSchedulePending(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := contents[0].(mcp.TextResourceContents).Text; text != "[]" {
+		t.Errorf("expected an empty list for an empty queue, got '%s'", text)
+	}
+
+	now := time.Now().UTC()
+	later, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"later"}, now.Add(2*time.Hour))
+	sooner, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"sooner"}, now.Add(time.Hour))
+	published, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"published"}, now)
+	store.Update(later.ID, func(t *api.ScheduledTweet) {
+		t.Reviewed = true
+		t.Status = api.ScheduledTweetStatusReviewed
+	})
+	store.Update(published.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusPublished
+	})
+
+	contents, err = rm.HandleResourceSchedulePending(context.Background(), mcp.ReadResourceRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	textContents := contents[0].(mcp.TextResourceContents)
+	if textContents.URI != schedulePendingURI || textContents.MIMEType != jsonMIMEType {
+		t.Errorf("unexpected contents metadata: %+v", textContents)
+	}
+
+	var tweets []api.ScheduledTweet
+	if err := json.Unmarshal([]byte(textContents.Text), &tweets); err != nil {
+		t.Fatalf("expected JSON contents: %v", err)
+	}
+	if len(tweets) != 2 || tweets[0].ID != sooner.ID || tweets[1].ID != later.ID {
+		t.Errorf("expected pending and reviewed tweets sorted by time, got %+v", tweets)
+	}
+}
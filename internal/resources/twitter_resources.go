@@ -0,0 +1,54 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	profileURI         = "me://profile"
+	trendsWorldwideURI = "trends://worldwide"
+
+	// worldwideWOEID is the Yahoo! Where On Earth ID of the whole world
+	worldwideWOEID = 1
+)
+
+// HandleResourceProfile handles the me://profile resource
+func (rm *ResourcesManager) HandleResourceProfile(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	text, err := rm.cache.GetOrFetch(profileURI, func() (any, error) {
+		return rm.dependencies.TwitterClient.GetMeProfile(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the authenticated profile: %w", err)
+	}
+
+	return textResourceContents(profileURI, text), nil
+}
+
+// HandleResourceTrendsWorldwide handles the trends://worldwide resource
+func (rm *ResourcesManager) HandleResourceTrendsWorldwide(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	text, err := rm.cache.GetOrFetch(trendsWorldwideURI, func() (any, error) {
+		return rm.dependencies.TwitterClient.GetTrends(ctx, worldwideWOEID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worldwide trends: %w", err)
+	}
+
+	return textResourceContents(trendsWorldwideURI, text), nil
+}
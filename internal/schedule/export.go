@@ -0,0 +1,193 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+	"twitter-mcp/api"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Import modes
+const (
+	ImportModeMerge   = "merge"
+	ImportModeReplace = "replace"
+)
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	Mode     string `json:"mode"`
+	Imported int    `json:"imported"`
+	Replaced int    `json:"replaced,omitempty"`
+
+	// RegeneratedIDs maps the imported IDs that were already taken to their new ones
+	RegeneratedIDs map[string]string `json:"regenerated_ids,omitempty"`
+}
+
+// Export serializes the whole store in the given format, using the same layout as the schedule file
+func (s *Store) Export(format string) ([]byte, error) {
+	s.mu.RLock()
+	data := api.ScheduleStore{ScheduledTweets: make([]api.ScheduledTweet, 0, len(s.data.ScheduledTweets))}
+	for _, t := range s.data.ScheduledTweets {
+		data.ScheduledTweets = append(data.ScheduledTweets, cloneScheduledTweet(t))
+	}
+	s.mu.RUnlock()
+
+	switch format {
+	case FormatJSON:
+		return json.Marshal(data)
+	case FormatYAML, "":
+		return yaml.Marshal(&data)
+	default:
+		return nil, fmt.Errorf("unknown format '%s', use json or yaml", format)
+	}
+}
+
+// Import loads tweets exported by Export, either JSON or YAML, detected from the content.
+// In merge mode they are added to the current ones, with a new ID when theirs is taken. In replace mode
+// they are the only ones kept. Imported tweets skip the min gap and duplicates checks, as they restore a queue
+func (s *Store) Import(data []byte, mode string) (*ImportResult, error) {
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return nil, fmt.Errorf("unknown import mode '%s', use merge or replace", mode)
+	}
+
+	imported, err := decodeExport(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range imported.ScheduledTweets {
+		if err := normalizeImported(&imported.ScheduledTweets[i]); err != nil {
+			return nil, fmt.Errorf("scheduled tweet %d: %w", i, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &ImportResult{Mode: mode, Imported: len(imported.ScheduledTweets)}
+
+	current := s.data.ScheduledTweets
+	if mode == ImportModeReplace {
+		result.Replaced = len(current)
+		current = nil
+	}
+
+	takenIDs := make(map[string]bool, len(current)+len(imported.ScheduledTweets))
+	for _, t := range current {
+		takenIDs[t.ID] = true
+	}
+
+	tweets := make([]api.ScheduledTweet, 0, len(current)+len(imported.ScheduledTweets))
+	tweets = append(tweets, current...)
+	for _, t := range imported.ScheduledTweets {
+		if takenIDs[t.ID] {
+			newID := uuid.New().String()
+			if result.RegeneratedIDs == nil {
+				result.RegeneratedIDs = map[string]string{}
+			}
+			result.RegeneratedIDs[t.ID] = newID
+			t.ID = newID
+		}
+		takenIDs[t.ID] = true
+		tweets = append(tweets, t)
+	}
+
+	previous := s.data.ScheduledTweets
+	s.data.ScheduledTweets = tweets
+	if err := s.save(); err != nil {
+		s.data.ScheduledTweets = previous
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// decodeExport parses JSON when the data looks like an object, and YAML otherwise
+func decodeExport(data []byte) (api.ScheduleStore, error) {
+	var store api.ScheduleStore
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return store, fmt.Errorf("nothing to import")
+	}
+
+	if trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &store); err != nil {
+			return store, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return store, nil
+	}
+
+	if err := yaml.Unmarshal(trimmed, &store); err != nil {
+		return store, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return store, nil
+}
+
+// normalizeImported validates an imported tweet and fills the fields a hand-written file may omit
+func normalizeImported(t *api.ScheduledTweet) error {
+	if len(t.Content) == 0 {
+		return fmt.Errorf("content is required")
+	}
+	if t.ScheduledAt.IsZero() {
+		return fmt.Errorf("scheduled_at is required")
+	}
+
+	switch t.Type {
+	case api.ScheduledTweetTypeTweet, api.ScheduledTweetTypeThread:
+	case "":
+		t.Type = api.ScheduledTweetTypeTweet
+		if len(t.Content) > 1 {
+			t.Type = api.ScheduledTweetTypeThread
+		}
+	default:
+		return fmt.Errorf("unknown type '%s'", t.Type)
+	}
+
+	switch t.Status {
+	case api.ScheduledTweetStatusPending, api.ScheduledTweetStatusReviewed,
+		api.ScheduledTweetStatusPublished, api.ScheduledTweetStatusFailed:
+	case "":
+		t.Status = api.ScheduledTweetStatusPending
+		if t.Reviewed {
+			t.Status = api.ScheduledTweetStatusReviewed
+		}
+	default:
+		return fmt.Errorf("unknown status '%s'", t.Status)
+	}
+
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now().UTC()
+	}
+	return nil
+}
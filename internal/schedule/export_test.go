@@ -0,0 +1,97 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+)
+
+func TestStoreExportImport(t *testing.T) {
+	source := newTestStore(t)
+	base := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	first, _ := source.Add(api.ScheduledTweetTypeTweet, []string{"first"}, base)
+	source.Add(api.ScheduledTweetTypeThread, []string{"second", "third"}, base.Add(time.Hour))
+
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			exported, err := source.Export(format)
+			if err != nil {
+				t.Fatalf("unexpected error exporting: %v", err)
+			}
+
+			target := newTestStore(t)
+			target.Add(api.ScheduledTweetTypeTweet, []string{"existing"}, base)
+
+			result, err := target.Import(exported, ImportModeReplace)
+			if err != nil {
+				t.Fatalf("unexpected error importing: %v", err)
+			}
+			if result.Imported != 2 || result.Replaced != 1 {
+				t.Errorf("expected 2 imported and 1 replaced, got %+v", result)
+			}
+
+			imported, err := target.GetByID(first.ID)
+			if err != nil {
+				t.Fatalf("expected IDs to be kept: %v", err)
+			}
+			if imported.Content[0] != "first" || !imported.ScheduledAt.Equal(base) || imported.Status != api.ScheduledTweetStatusPending {
+				t.Errorf("unexpected imported tweet: %+v", imported)
+			}
+
+			// Merging the same data again takes new IDs for all of them
+			result, err = target.Import(exported, ImportModeMerge)
+			if err != nil {
+				t.Fatalf("unexpected error merging: %v", err)
+			}
+			if len(result.RegeneratedIDs) != 2 || result.RegeneratedIDs[first.ID] == "" {
+				t.Errorf("expected regenerated IDs for both tweets, got %v", result.RegeneratedIDs)
+			}
+			if tweets := target.List(""); len(tweets) != 4 {
+				t.Errorf("expected 4 tweets after merging, got %d", len(tweets))
+			}
+		})
+	}
+}
+
+func TestStoreImportErrors(t *testing.T) {
+	store := newTestStore(t)
+	store.Add(api.ScheduledTweetTypeTweet, []string{"existing"}, time.Now())
+
+	tests := map[string]struct {
+		data string
+		mode string
+	}{
+		"empty data":      {data: "  ", mode: ImportModeMerge},
+		"unknown mode":    {data: `{"scheduled_tweets": []}`, mode: "append"},
+		"invalid JSON":    {data: `{"scheduled_tweets": [`, mode: ImportModeMerge},
+		"missing content": {data: "scheduled_tweets:\n  - scheduled_at: 2030-01-02T10:00:00Z\n", mode: ImportModeReplace},
+		"unknown status":  {data: "scheduled_tweets:\n  - content: [hi]\n    scheduled_at: 2030-01-02T10:00:00Z\n    status: queued\n", mode: ImportModeReplace},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.Import([]byte(test.data), test.mode); err == nil {
+				t.Errorf("expected an error")
+			}
+			if tweets := store.List(""); len(tweets) != 1 {
+				t.Errorf("expected the store to be untouched, got %d tweets", len(tweets))
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ package schedule
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 	"twitter-mcp/api"
@@ -25,16 +27,28 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// Store manages persistence of scheduled tweets
+// Options represents the optional behaviour of a Store
+type Options struct {
+	// MinGap is the minimum time between two queued (pending or reviewed) tweets. Disabled when zero
+	MinGap time.Duration
+
+	// RejectDuplicates makes Add and Update fail when the content matches another queued tweet.
+	// Twitter rejects duplicated content, so those tweets would fail when published anyway
+	RejectDuplicates bool
+}
+
+// Store manages persistence of scheduled tweets.
+// Reads share a read lock, while mutations and saving to disk take the write lock
 type Store struct {
-	mu       sync.Mutex
+	mu       sync.RWMutex
 	filepath string
+	options  Options
 	data     api.ScheduleStore
 }
 
 // NewStore creates a new Store and loads existing data from disk
-func NewStore(filepath string) (*Store, error) {
-	s := &Store{filepath: filepath}
+func NewStore(filepath string, options Options) (*Store, error) {
+	s := &Store{filepath: filepath, options: options}
 	if err := s.load(); err != nil {
 		return nil, err
 	}
@@ -90,28 +104,36 @@ func (s *Store) Add(tweetType api.ScheduledTweetType, content []string, schedule
 		CreatedAt:   time.Now().UTC(),
 	}
 
+	if err := s.checkGap(tweet); err != nil {
+		return nil, err
+	}
+
+	if s.options.RejectDuplicates {
+		if err := s.checkDuplicate(tweet); err != nil {
+			return nil, err
+		}
+	}
+
 	s.data.ScheduledTweets = append(s.data.ScheduledTweets, tweet)
 
 	if err := s.save(); err != nil {
 		return nil, err
 	}
 
-	return &tweet, nil
+	copy := cloneScheduledTweet(tweet)
+	return &copy, nil
 }
 
-// List returns all scheduled tweets, optionally filtered by status
+// List returns all scheduled tweets, optionally filtered by status.
+// The returned tweets are copies, so callers can use them freely while the store changes
 func (s *Store) List(status api.ScheduledTweetStatus) []api.ScheduledTweet {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if status == "" {
-		return s.data.ScheduledTweets
-	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	var result []api.ScheduledTweet
 	for _, t := range s.data.ScheduledTweets {
-		if t.Status == status {
-			result = append(result, t)
+		if status == "" || t.Status == status {
+			result = append(result, cloneScheduledTweet(t))
 		}
 	}
 	return result
@@ -119,12 +141,12 @@ func (s *Store) List(status api.ScheduledTweetStatus) []api.ScheduledTweet {
 
 // GetByID returns a scheduled tweet by ID
 func (s *Store) GetByID(id string) (*api.ScheduledTweet, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	for _, t := range s.data.ScheduledTweets {
 		if t.ID == id {
-			copy := t
+			copy := cloneScheduledTweet(t)
 			return &copy, nil
 		}
 	}
@@ -132,14 +154,32 @@ func (s *Store) GetByID(id string) (*api.ScheduledTweet, error) {
 	return nil, fmt.Errorf("scheduled tweet with id '%s' not found", id)
 }
 
-// Update modifies an existing scheduled tweet
+// Update modifies an existing scheduled tweet.
+// Changes are discarded when they move the tweet too close to another queued one, or duplicate its content
+// when duplicates are rejected
 func (s *Store) Update(id string, fn func(*api.ScheduledTweet)) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i, t := range s.data.ScheduledTweets {
 		if t.ID == id {
-			fn(&s.data.ScheduledTweets[i])
+			updated := cloneScheduledTweet(t)
+			fn(&updated)
+
+			// Only check the gap when the tweet is moved, so already stored tweets can still be edited
+			if !updated.ScheduledAt.Equal(t.ScheduledAt) || (!isQueued(t) && isQueued(updated)) {
+				if err := s.checkGap(updated); err != nil {
+					return err
+				}
+			}
+
+			if s.options.RejectDuplicates && !slices.Equal(updated.Content, t.Content) {
+				if err := s.checkDuplicate(updated); err != nil {
+					return err
+				}
+			}
+
+			s.data.ScheduledTweets[i] = updated
 			return s.save()
 		}
 	}
@@ -147,6 +187,48 @@ func (s *Store) Update(id string, fn func(*api.ScheduledTweet)) error {
 	return fmt.Errorf("scheduled tweet with id '%s' not found", id)
 }
 
+// Requeue moves a failed tweet back to the queue, as reviewed or pending, clearing its fail reason.
+// A non-zero scheduledAt replaces the publishing time. Tweets in any other status are rejected
+func (s *Store) Requeue(id string, reviewed bool, scheduledAt time.Time) (*api.ScheduledTweet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, t := range s.data.ScheduledTweets {
+		if t.ID != id {
+			continue
+		}
+
+		if t.Status != api.ScheduledTweetStatusFailed {
+			return nil, fmt.Errorf("scheduled tweet with id '%s' is '%s', only failed tweets can be requeued", id, t.Status)
+		}
+
+		tweet := cloneScheduledTweet(t)
+		tweet.Reviewed = reviewed
+		tweet.Status = api.ScheduledTweetStatusPending
+		if reviewed {
+			tweet.Status = api.ScheduledTweetStatusReviewed
+		}
+		tweet.FailReason = ""
+		if !scheduledAt.IsZero() {
+			tweet.ScheduledAt = scheduledAt
+		}
+
+		if err := s.checkGap(tweet); err != nil {
+			return nil, err
+		}
+
+		s.data.ScheduledTweets[i] = tweet
+		if err := s.save(); err != nil {
+			return nil, err
+		}
+
+		copy := cloneScheduledTweet(tweet)
+		return &copy, nil
+	}
+
+	return nil, fmt.Errorf("scheduled tweet with id '%s' not found", id)
+}
+
 // Delete removes a scheduled tweet by ID
 func (s *Store) Delete(id string) error {
 	s.mu.Lock()
@@ -165,12 +247,68 @@ func (s *Store) Delete(id string) error {
 // GetPublishable returns tweets that are reviewed, scheduled_at is past,
 // and no other tweet was published within minHoursSinceLast hours
 func (s *Store) GetPublishable(minHoursSinceLast int) []api.ScheduledTweet {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	now := time.Now().UTC()
 
-	// Find the last published tweet
+	// Check if enough time has passed since last publish
+	if blockedUntil := cooldownEnd(s.lastPublishedAt(), minHoursSinceLast); now.Before(blockedUntil) {
+		return nil
+	}
+
+	// Return reviewed tweets whose scheduled time has passed
+	var result []api.ScheduledTweet
+	for _, t := range s.data.ScheduledTweets {
+		if t.Reviewed && t.Status == api.ScheduledTweetStatusReviewed && t.ScheduledAt.Before(now) {
+			result = append(result, cloneScheduledTweet(t))
+		}
+	}
+
+	return result
+}
+
+// Stats summarizes the queue: tweets per status, the next queued tweet by scheduled time,
+// and whether publishing is blocked by the cooldown since the last published tweet
+func (s *Store) Stats(minHoursSinceLast int) api.ScheduleStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := api.ScheduleStats{
+		Counts: map[api.ScheduledTweetStatus]int{
+			api.ScheduledTweetStatusPending:   0,
+			api.ScheduledTweetStatusReviewed:  0,
+			api.ScheduledTweetStatusPublished: 0,
+			api.ScheduledTweetStatusFailed:    0,
+		},
+	}
+
+	for _, t := range s.data.ScheduledTweets {
+		stats.Counts[t.Status]++
+
+		if isQueued(t) && (stats.NextDue == nil || t.ScheduledAt.Before(stats.NextDue.ScheduledAt)) {
+			next := cloneScheduledTweet(t)
+			stats.NextDue = &next
+		}
+	}
+
+	lastPublishedAt := s.lastPublishedAt()
+	if lastPublishedAt.IsZero() {
+		return stats
+	}
+	stats.LastPublishedAt = &lastPublishedAt
+
+	if blockedUntil := cooldownEnd(lastPublishedAt, minHoursSinceLast); time.Now().UTC().Before(blockedUntil) {
+		stats.Blocked = true
+		stats.BlockedUntil = &blockedUntil
+	}
+
+	return stats
+}
+
+// lastPublishedAt returns when the last tweet was published, or zero when none was.
+// It must be called with the lock held
+func (s *Store) lastPublishedAt() time.Time {
 	var lastPublishedAt time.Time
 	for _, t := range s.data.ScheduledTweets {
 		if t.Status == api.ScheduledTweetStatusPublished && t.PublishedAt != nil {
@@ -179,22 +317,93 @@ func (s *Store) GetPublishable(minHoursSinceLast int) []api.ScheduledTweet {
 			}
 		}
 	}
+	return lastPublishedAt
+}
 
-	// Check if enough time has passed since last publish
-	if minHoursSinceLast > 0 && !lastPublishedAt.IsZero() {
-		minGap := time.Duration(minHoursSinceLast) * time.Hour
-		if now.Sub(lastPublishedAt) < minGap {
-			return nil
+// cooldownEnd returns when publishing is allowed again after the last published tweet.
+// It is zero when there is no cooldown
+func cooldownEnd(lastPublishedAt time.Time, minHoursSinceLast int) time.Time {
+	if minHoursSinceLast <= 0 || lastPublishedAt.IsZero() {
+		return time.Time{}
+	}
+	return lastPublishedAt.Add(time.Duration(minHoursSinceLast) * time.Hour)
+}
+
+// FindDuplicate returns the ID of a queued tweet, other than excludeID, with the same content
+// once whitespace is normalized. It is empty when there is none
+func (s *Store) FindDuplicate(content []string, excludeID string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.findDuplicate(content, excludeID)
+}
+
+// findDuplicate is FindDuplicate without locking. It must be called with the lock held
+func (s *Store) findDuplicate(content []string, excludeID string) string {
+	normalized := normalizeContent(content)
+
+	for _, t := range s.data.ScheduledTweets {
+		if t.ID == excludeID || !isQueued(t) {
+			continue
+		}
+		if slices.Equal(normalizeContent(t.Content), normalized) {
+			return t.ID
 		}
 	}
+	return ""
+}
+
+// checkDuplicate returns an error naming the queued tweet with the same content as the given one.
+// It must be called with the lock held
+func (s *Store) checkDuplicate(tweet api.ScheduledTweet) error {
+	if id := s.findDuplicate(tweet.Content, tweet.ID); id != "" {
+		return fmt.Errorf("content duplicates scheduled tweet '%s', Twitter rejects duplicated tweets", id)
+	}
+	return nil
+}
+
+// normalizeContent collapses whitespace runs into single spaces and trims every item
+func normalizeContent(content []string) []string {
+	normalized := make([]string, len(content))
+	for i, text := range content {
+		normalized[i] = strings.Join(strings.Fields(text), " ")
+	}
+	return normalized
+}
+
+// checkGap returns an error naming the first queued tweet scheduled within the minimum gap of the given one.
+// It must be called with the lock held
+func (s *Store) checkGap(tweet api.ScheduledTweet) error {
+	if s.options.MinGap <= 0 || !isQueued(tweet) {
+		return nil
+	}
 
-	// Return reviewed tweets whose scheduled time has passed
-	var result []api.ScheduledTweet
 	for _, t := range s.data.ScheduledTweets {
-		if t.Reviewed && t.Status == api.ScheduledTweetStatusReviewed && t.ScheduledAt.Before(now) {
-			result = append(result, t)
+		if t.ID == tweet.ID || !isQueued(t) {
+			continue
+		}
+
+		gap := tweet.ScheduledAt.Sub(t.ScheduledAt).Abs()
+		if gap < s.options.MinGap {
+			return fmt.Errorf("scheduled_at is %s away from scheduled tweet '%s' (%s), the minimum gap is %s",
+				gap, t.ID, t.ScheduledAt.Format(time.RFC3339), s.options.MinGap)
 		}
 	}
 
-	return result
+	return nil
+}
+
+// isQueued reports whether a tweet is still waiting to be published
+func isQueued(t api.ScheduledTweet) bool {
+	return t.Status == api.ScheduledTweetStatusPending || t.Status == api.ScheduledTweetStatusReviewed
+}
+
+// cloneScheduledTweet returns a deep copy of a scheduled tweet, not sharing its content or dates with the store
+func cloneScheduledTweet(t api.ScheduledTweet) api.ScheduledTweet {
+	t.Content = slices.Clone(t.Content)
+	if t.PublishedAt != nil {
+		publishedAt := *t.PublishedAt
+		t.PublishedAt = &publishedAt
+	}
+	return t
 }
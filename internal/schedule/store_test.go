@@ -0,0 +1,296 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+)
+
+func newTestStore(t *testing.T) *Store {
+	return newTestStoreWithOptions(t, Options{})
+}
+
+func newTestStoreWithOptions(t *testing.T, options Options) *Store {
+	store, err := NewStore(filepath.Join(t.TempDir(), "schedule.yaml"), options)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %v", err)
+	}
+	return store
+}
+
+// TestStoreConcurrentAccess is meant to be run with -race
+func TestStoreConcurrentAccess(t *testing.T) {
+	store := newTestStore(t)
+
+	var waitGroup sync.WaitGroup
+	for i := range 10 {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			tweet, err := store.Add(api.ScheduledTweetTypeTweet, []string{fmt.Sprintf("tweet %d", i)}, time.Now().Add(-time.Minute))
+			if err != nil {
+				t.Errorf("unexpected error adding: %v", err)
+				return
+			}
+			store.Update(tweet.ID, func(t *api.ScheduledTweet) {
+				t.Reviewed = true
+				t.Status = api.ScheduledTweetStatusReviewed
+			})
+		}()
+
+		go func() {
+			defer waitGroup.Done()
+
+			for _, tweet := range store.List("") {
+				store.GetByID(tweet.ID)
+			}
+			store.List(api.ScheduledTweetStatusReviewed)
+			store.GetPublishable(0)
+		}()
+	}
+	waitGroup.Wait()
+
+	if tweets := store.List(""); len(tweets) != 10 {
+		t.Errorf("expected 10 tweets, got %d", len(tweets))
+	}
+	if publishable := store.GetPublishable(0); len(publishable) != 10 {
+		t.Errorf("expected 10 publishable tweets, got %d", len(publishable))
+	}
+}
+
+func TestStoreReturnsCopies(t *testing.T) {
+	store := newTestStore(t)
+
+	added, err := store.Add(api.ScheduledTweetTypeThread, []string{"first", "second"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+	added.Content[0] = "changed through Add"
+
+	for _, status := range []api.ScheduledTweetStatus{"", api.ScheduledTweetStatusPending} {
+		tweets := store.List(status)
+		tweets[0].Content[0] = "changed through List"
+		tweets[0].Status = api.ScheduledTweetStatusFailed
+		tweets[0] = api.ScheduledTweet{ID: "injected"}
+	}
+
+	fetched, _ := store.GetByID(added.ID)
+	fetched.Content[1] = "changed through GetByID"
+
+	stored, err := store.GetByID(added.ID)
+	if err != nil {
+		t.Fatalf("expected tweet to be in the store: %v", err)
+	}
+	if stored.Content[0] != "first" || stored.Content[1] != "second" {
+		t.Errorf("expected content untouched, got %v", stored.Content)
+	}
+	if stored.Status != api.ScheduledTweetStatusPending {
+		t.Errorf("expected status untouched, got '%s'", stored.Status)
+	}
+	if tweets := store.List(""); len(tweets) != 1 || tweets[0].ID != added.ID {
+		t.Errorf("expected the store to keep only the added tweet, got %+v", tweets)
+	}
+}
+
+func TestStoreRequeue(t *testing.T) {
+	store := newTestStore(t)
+
+	added, err := store.Add(api.ScheduledTweetTypeTweet, []string{"hello"}, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+
+	if _, err := store.Requeue(added.ID, true, time.Time{}); err == nil {
+		t.Errorf("expected pending tweets not to be requeued")
+	}
+	if _, err := store.Requeue("missing", true, time.Time{}); err == nil {
+		t.Errorf("expected unknown tweets not to be requeued")
+	}
+
+	store.Update(added.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusFailed
+		t.FailReason = "duplicate content"
+	})
+
+	newTime := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	requeued, err := store.Requeue(added.ID, true, newTime)
+	if err != nil {
+		t.Fatalf("unexpected error requeuing: %v", err)
+	}
+	if requeued.Status != api.ScheduledTweetStatusReviewed || !requeued.Reviewed {
+		t.Errorf("expected reviewed tweet, got status '%s' (reviewed: %v)", requeued.Status, requeued.Reviewed)
+	}
+	if requeued.FailReason != "" {
+		t.Errorf("expected fail reason to be cleared, got '%s'", requeued.FailReason)
+	}
+	if !requeued.ScheduledAt.Equal(newTime) {
+		t.Errorf("expected scheduled_at %v, got %v", newTime, requeued.ScheduledAt)
+	}
+
+	store.Update(added.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusFailed
+	})
+
+	requeued, err = store.Requeue(added.ID, false, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error requeuing: %v", err)
+	}
+	if requeued.Status != api.ScheduledTweetStatusPending || requeued.Reviewed {
+		t.Errorf("expected pending tweet, got status '%s' (reviewed: %v)", requeued.Status, requeued.Reviewed)
+	}
+	if !requeued.ScheduledAt.Equal(newTime) {
+		t.Errorf("expected scheduled_at to be kept, got %v", requeued.ScheduledAt)
+	}
+}
+
+func TestStoreMinGap(t *testing.T) {
+	store := newTestStoreWithOptions(t, Options{MinGap: 30 * time.Minute})
+	base := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	first, err := store.Add(api.ScheduledTweetTypeTweet, []string{"first"}, base)
+	if err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+
+	_, err = store.Add(api.ScheduledTweetTypeTweet, []string{"too close"}, base.Add(-10*time.Minute))
+	if err == nil || !strings.Contains(err.Error(), first.ID) {
+		t.Fatalf("expected a gap error naming '%s', got: %v", first.ID, err)
+	}
+
+	second, err := store.Add(api.ScheduledTweetTypeTweet, []string{"second"}, base.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("expected tweets exactly at the gap to be accepted: %v", err)
+	}
+
+	err = store.Update(second.ID, func(t *api.ScheduledTweet) {
+		t.ScheduledAt = base.Add(5 * time.Minute)
+	})
+	if err == nil {
+		t.Fatalf("expected moving a tweet too close to fail")
+	}
+	if stored, _ := store.GetByID(second.ID); !stored.ScheduledAt.Equal(base.Add(30 * time.Minute)) {
+		t.Errorf("expected rejected update to be discarded, got %v", stored.ScheduledAt)
+	}
+
+	err = store.Update(second.ID, func(t *api.ScheduledTweet) {
+		t.Content = []string{"edited"}
+	})
+	if err != nil {
+		t.Errorf("expected content edits not to check the gap: %v", err)
+	}
+
+	// Published tweets do not block the queue
+	store.Update(first.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusPublished
+	})
+	if _, err := store.Add(api.ScheduledTweetTypeTweet, []string{"third"}, base.Add(-5*time.Minute)); err != nil {
+		t.Errorf("expected published tweets to be ignored: %v", err)
+	}
+}
+
+func TestStoreStats(t *testing.T) {
+	store := newTestStore(t)
+
+	stats := store.Stats(1)
+	if stats.NextDue != nil || stats.LastPublishedAt != nil || stats.Blocked {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+
+	now := time.Now().UTC()
+	later, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"later"}, now.Add(2*time.Hour))
+	sooner, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"sooner"}, now.Add(time.Hour))
+	published, _ := store.Add(api.ScheduledTweetTypeTweet, []string{"published"}, now.Add(-time.Hour))
+
+	publishedAt := now.Add(-30 * time.Minute)
+	store.Update(published.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusPublished
+		t.PublishedAt = &publishedAt
+	})
+	store.Update(later.ID, func(t *api.ScheduledTweet) {
+		t.Reviewed = true
+		t.Status = api.ScheduledTweetStatusReviewed
+	})
+
+	stats = store.Stats(1)
+	if stats.Counts[api.ScheduledTweetStatusPending] != 1 || stats.Counts[api.ScheduledTweetStatusReviewed] != 1 ||
+		stats.Counts[api.ScheduledTweetStatusPublished] != 1 || stats.Counts[api.ScheduledTweetStatusFailed] != 0 {
+		t.Errorf("unexpected counts: %v", stats.Counts)
+	}
+	if stats.NextDue == nil || stats.NextDue.ID != sooner.ID {
+		t.Errorf("expected next due tweet '%s', got %+v", sooner.ID, stats.NextDue)
+	}
+	if stats.LastPublishedAt == nil || !stats.LastPublishedAt.Equal(publishedAt) {
+		t.Errorf("expected last published at %v, got %v", publishedAt, stats.LastPublishedAt)
+	}
+	if !stats.Blocked || stats.BlockedUntil == nil || !stats.BlockedUntil.Equal(publishedAt.Add(time.Hour)) {
+		t.Errorf("expected publishing blocked until %v, got %+v", publishedAt.Add(time.Hour), stats)
+	}
+
+	if stats := store.Stats(0); stats.Blocked {
+		t.Errorf("expected no cooldown without min hours")
+	}
+}
+
+func TestStoreDuplicates(t *testing.T) {
+	store := newTestStoreWithOptions(t, Options{RejectDuplicates: true})
+	base := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	first, err := store.Add(api.ScheduledTweetTypeThread, []string{"Hello  world", "second\ntweet"}, base)
+	if err != nil {
+		t.Fatalf("unexpected error adding: %v", err)
+	}
+
+	_, err = store.Add(api.ScheduledTweetTypeThread, []string{" Hello world ", "second tweet"}, base.Add(time.Hour))
+	if err == nil || !strings.Contains(err.Error(), first.ID) {
+		t.Fatalf("expected a duplicate error naming '%s', got: %v", first.ID, err)
+	}
+
+	other, err := store.Add(api.ScheduledTweetTypeTweet, []string{"Hello world"}, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("expected different content to be accepted: %v", err)
+	}
+
+	err = store.Update(other.ID, func(t *api.ScheduledTweet) {
+		t.Type = api.ScheduledTweetTypeThread
+		t.Content = []string{"Hello world", "second tweet"}
+	})
+	if err == nil {
+		t.Errorf("expected updating to duplicated content to fail")
+	}
+
+	if id := store.FindDuplicate([]string{"hello world"}, ""); id != "" {
+		t.Errorf("expected comparison to be case sensitive, got '%s'", id)
+	}
+	if id := store.FindDuplicate([]string{"Hello world"}, other.ID); id != "" {
+		t.Errorf("expected the excluded tweet to be skipped, got '%s'", id)
+	}
+
+	// Published tweets are not in the queue anymore
+	store.Update(first.ID, func(t *api.ScheduledTweet) {
+		t.Status = api.ScheduledTweetStatusPublished
+	})
+	if _, err := store.Add(api.ScheduledTweetTypeThread, []string{"Hello world", "second tweet"}, base.Add(2*time.Hour)); err != nil {
+		t.Errorf("expected published tweets to be ignored: %v", err)
+	}
+}
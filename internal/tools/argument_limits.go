@@ -0,0 +1,97 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"twitter-mcp/api"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultMaxTextBytes caps every string argument. It is far beyond any tweet,
+	// but leaves room for schedule_import data
+	defaultMaxTextBytes = 64 * 1024
+
+	// defaultMaxThreadTweets caps the tweets of a thread ('tweets' and 'content' arguments)
+	defaultMaxThreadTweets = 25
+
+	// defaultMaxTopics caps the topics of topic tools, as every topic is a search request
+	defaultMaxTopics = 10
+)
+
+// argumentLimits holds the size limits checked on tool arguments before running any handler
+type argumentLimits struct {
+	maxTextBytes int
+
+	// maxItems caps array arguments by name
+	maxItems map[string]int
+}
+
+// newArgumentLimits builds the limits from config, using the defaults for unset values
+func newArgumentLimits(config api.ToolLimitsConfig) argumentLimits {
+	withDefault := func(value, defaultValue int) int {
+		if value <= 0 {
+			return defaultValue
+		}
+		return value
+	}
+
+	maxThreadTweets := withDefault(config.MaxThreadTweets, defaultMaxThreadTweets)
+	return argumentLimits{
+		maxTextBytes: withDefault(config.MaxTextBytes, defaultMaxTextBytes),
+		maxItems: map[string]int{
+			"tweets":  maxThreadTweets,
+			"content": maxThreadTweets,
+			"topics":  withDefault(config.MaxTopics, defaultMaxTopics),
+		},
+	}
+}
+
+// check returns an error for the first argument over the limits
+func (l argumentLimits) check(args map[string]any) error {
+	for key, value := range args {
+		switch typed := value.(type) {
+		case string:
+			if len(typed) > l.maxTextBytes {
+				return fmt.Errorf("argument '%s' is %d bytes long, over the %d limit", key, len(typed), l.maxTextBytes)
+			}
+		case []any:
+			if maxItems, limited := l.maxItems[key]; limited && len(typed) > maxItems {
+				return fmt.Errorf("argument '%s' has %d items, over the %d limit", key, len(typed), maxItems)
+			}
+			for i, item := range typed {
+				if text, ok := item.(string); ok && len(text) > l.maxTextBytes {
+					return fmt.Errorf("argument '%s' item %d is %d bytes long, over the %d limit", key, i, len(text), l.maxTextBytes)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// withArgumentLimits wraps a tool handler, rejecting oversized arguments before it runs
+func (tm *ToolsManager) withArgumentLimits(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := tm.argumentLimits.check(getArgs(request)); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, request)
+	}
+}
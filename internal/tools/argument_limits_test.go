@@ -0,0 +1,65 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"twitter-mcp/api"
+)
+
+func TestNewArgumentLimits(t *testing.T) {
+	limits := newArgumentLimits(api.ToolLimitsConfig{})
+	if limits.maxTextBytes != defaultMaxTextBytes || limits.maxItems["tweets"] != defaultMaxThreadTweets || limits.maxItems["topics"] != defaultMaxTopics {
+		t.Errorf("expected defaults, got %+v", limits)
+	}
+
+	limits = newArgumentLimits(api.ToolLimitsConfig{MaxTextBytes: 100, MaxThreadTweets: 5, MaxTopics: 3})
+	if limits.maxTextBytes != 100 || limits.maxItems["content"] != 5 || limits.maxItems["topics"] != 3 {
+		t.Errorf("expected configured limits, got %+v", limits)
+	}
+}
+
+func TestArgumentLimitsCheck(t *testing.T) {
+	limits := newArgumentLimits(api.ToolLimitsConfig{MaxTextBytes: 10, MaxThreadTweets: 2, MaxTopics: 3})
+
+	tests := map[string]struct {
+		args     map[string]any
+		expected string
+	}{
+		"within limits":   {args: map[string]any{"text": "hello", "tweets": []any{"a", "b"}, "max_results": float64(100)}},
+		"long text":       {args: map[string]any{"text": strings.Repeat("a", 11)}, expected: "argument 'text' is 11 bytes long"},
+		"long thread":     {args: map[string]any{"content": []any{"a", "b", "c"}}, expected: "argument 'content' has 3 items"},
+		"many topics":     {args: map[string]any{"topics": []any{"a", "b", "c", "d"}}, expected: "argument 'topics' has 4 items"},
+		"long array item": {args: map[string]any{"tweets": []any{"a", strings.Repeat("b", 20)}}, expected: "argument 'tweets' item 1"},
+		"other arrays":    {args: map[string]any{"exclude": []any{"a", "b", "c", "d"}}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := limits.check(test.args)
+			if test.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("expected error containing '%s', got: %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// capability represents a registered tool as returned by list_capabilities
+type capability struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	InputSchema mcp.ToolInputSchema `json:"input_schema"`
+}
+
+// HandleToolListCapabilities lists the registered tools, optionally only those of a category
+func (tm *ToolsManager) HandleToolListCapabilities(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	category := getString(args, "category", "")
+
+	if _, exists := ToolCategories[category]; category != "" && !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("unknown category '%s'", category)), nil
+	}
+
+	capabilities := []capability{}
+	for _, tool := range tm.registeredTools {
+		categories := toolCategoriesOf(tool.Name)
+		if category != "" && !slices.Contains(categories, category) {
+			continue
+		}
+
+		capabilities = append(capabilities, capability{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Categories:  categories,
+			InputSchema: tool.InputSchema,
+		})
+	}
+	sort.Slice(capabilities, func(i, j int) bool { return capabilities[i].Name < capabilities[j].Name })
+
+	result, _ := json.Marshal(map[string]any{
+		"count": len(capabilities),
+		"tools": capabilities,
+	})
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// toolCategoriesOf returns the sorted categories a tool belongs to
+func toolCategoriesOf(toolName string) []string {
+	categories := []string{}
+	for category, members := range ToolCategories {
+		if slices.Contains(members, toolName) {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"twitter-mcp/api"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListCapabilities(t *testing.T) {
+	tm, _ := newTestToolsManager(api.ToolsConfig{Disabled: []string{"post_tweet"}})
+
+	result, err := tm.HandleToolListCapabilities(context.Background(), newCallToolRequest(map[string]any{"category": "read"}))
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result)
+	}
+
+	var response struct {
+		Count int          `json:"count"`
+		Tools []capability `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, tool := range response.Tools {
+		names[tool.Name] = true
+	}
+	if !names["list_capabilities"] || !names["get_timeline"] {
+		t.Errorf("expected read tools to be listed, got %v", names)
+	}
+	if names["post_thread"] {
+		t.Errorf("expected write tools to be filtered out")
+	}
+	if response.Count != len(response.Tools) {
+		t.Errorf("expected count %d, got %d", len(response.Tools), response.Count)
+	}
+
+	result, _ = tm.HandleToolListCapabilities(context.Background(), newCallToolRequest(map[string]any{}))
+	if strings.Contains(result.Content[0].(mcp.TextContent).Text, `"name":"post_tweet"`) {
+		t.Errorf("expected disabled tools not to be listed")
+	}
+
+	result, _ = tm.HandleToolListCapabilities(context.Background(), newCallToolRequest(map[string]any{"category": "unknown"}))
+	if !result.IsError {
+		t.Errorf("expected an error for an unknown category")
+	}
+}
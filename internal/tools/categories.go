@@ -0,0 +1,92 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	// CategoryPrefix marks a category name where a tool name is expected, e.g. 'category:write'
+	CategoryPrefix = "category:"
+
+	CategoryRead       = "read"
+	CategoryWrite      = "write"
+	CategoryEngagement = "engagement"
+	CategorySchedule   = "schedule"
+	CategoryAdmin      = "admin"
+)
+
+// ToolCategories groups tool names by what they do. A tool can belong to several categories,
+// but every tool is either in 'read' or in 'write'
+var ToolCategories = map[string][]string{
+	CategoryRead: {
+		"get_me", "get_me_full", "preview_tweet", "get_timeline", "get_mentions", "get_mentions_of", "search_tweets", "search_all",
+		"get_trends", "get_trend_changes", "list_trend_locations", "search_places", "search_topics", "get_topics_heat", "get_tweet_topics",
+		"get_user_profile", "search_users", "get_user_tweets", "find_mutuals", "find_inactive_following", "get_tweet_metrics", "get_liking_users", "get_retweeters", "get_quote_tweets",
+		"get_bookmarks", "get_my_likes", "get_list_tweets", "get_spaces", "get_space", "list_capabilities",
+		"schedule_list", "schedule_get", "schedule_status", "schedule_export", "schedule_get_publishable",
+	},
+	CategoryWrite: {
+		"post_tweet", "post_thread", "continue_thread", "delete_tweet", "delete_thread", "pin_tweet",
+		"like_tweet", "unlike_tweet", "retweet", "undo_retweet",
+		"follow_user", "unfollow_user", "follow_users", "unfollow_users", "bookmark_tweet", "remove_bookmark",
+		"create_list", "add_list_member", "remove_list_member",
+		"schedule_tweet", "schedule_update", "schedule_requeue", "schedule_delete", "schedule_import", "schedule_publish",
+	},
+	CategoryEngagement: {
+		"like_tweet", "unlike_tweet", "retweet", "undo_retweet",
+		"follow_user", "unfollow_user", "follow_users", "unfollow_users", "bookmark_tweet", "remove_bookmark",
+	},
+	CategorySchedule: {
+		"schedule_tweet", "schedule_update", "schedule_requeue", "schedule_delete", "schedule_import",
+		"schedule_list", "schedule_get", "schedule_status", "schedule_export", "schedule_get_publishable", "schedule_publish",
+	},
+	// Destructive or account-level actions, usually kept for the account owners
+	CategoryAdmin: {
+		"delete_tweet", "delete_thread", "pin_tweet",
+		"create_list", "add_list_member", "remove_list_member",
+		"schedule_delete", "schedule_import",
+	},
+}
+
+// isKnownTool checks whether a tool name belongs to any category
+func isKnownTool(toolName string) bool {
+	for _, members := range ToolCategories {
+		if slices.Contains(members, toolName) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesToolSelector checks if a tool is selected by an entry of a tools list.
+// Entries are tool names or categories in the form 'category:<name>'
+func matchesToolSelector(toolName string, selector string) bool {
+	if category, found := strings.CutPrefix(selector, CategoryPrefix); found {
+		return slices.Contains(ToolCategories[category], toolName)
+	}
+	return selector == toolName
+}
+
+// isValidToolSelector checks whether an entry of a tools list points to a known tool or category
+func isValidToolSelector(selector string) bool {
+	if category, found := strings.CutPrefix(selector, CategoryPrefix); found {
+		_, exists := ToolCategories[category]
+		return exists
+	}
+	return isKnownTool(selector)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/globals"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTestToolsManager builds a ToolsManager with every tool registered in a throwaway MCP server
+func newTestToolsManager(config api.ToolsConfig) (*ToolsManager, *server.MCPServer) {
+	mcpServer := server.NewMCPServer("test", "0.0.0")
+
+	tm := NewToolsManager(ToolsManagerDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Config: &api.Configuration{
+				Tools:   config,
+				Twitter: api.TwitterConfig{FullArchiveAccess: true},
+			},
+		},
+		McpServer: mcpServer,
+	})
+	tm.AddTools()
+
+	return tm, mcpServer
+}
+
+func TestEveryToolHasReadOrWriteCategory(t *testing.T) {
+	_, mcpServer := newTestToolsManager(api.ToolsConfig{})
+
+	for toolName := range mcpServer.ListTools() {
+		if !matchesToolSelector(toolName, CategoryPrefix+CategoryRead) &&
+			!matchesToolSelector(toolName, CategoryPrefix+CategoryWrite) {
+			t.Errorf("tool '%s' is neither in the 'read' nor in the 'write' category", toolName)
+		}
+	}
+}
+
+func TestAddToolsHonorsEnabledAndDisabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     api.ToolsConfig
+		registered []string
+		skipped    []string
+	}{
+		{
+			name:       "read only",
+			config:     api.ToolsConfig{Disabled: []string{"category:write"}},
+			registered: []string{"get_timeline", "schedule_list"},
+			skipped:    []string{"post_tweet", "like_tweet", "schedule_publish"},
+		},
+		{
+			name:       "enabled list with a disabled carve-out",
+			config:     api.ToolsConfig{Enabled: []string{"category:schedule", "get_me"}, Disabled: []string{"schedule_publish"}},
+			registered: []string{"get_me", "schedule_tweet"},
+			skipped:    []string{"get_timeline", "schedule_publish"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, mcpServer := newTestToolsManager(tt.config)
+
+			for _, toolName := range tt.registered {
+				if mcpServer.GetTool(toolName) == nil {
+					t.Errorf("expected '%s' to be registered", toolName)
+				}
+			}
+			for _, toolName := range tt.skipped {
+				if mcpServer.GetTool(toolName) != nil {
+					t.Errorf("expected '%s' not to be registered", toolName)
+				}
+			}
+		})
+	}
+}
+
+func TestIsValidToolSelector(t *testing.T) {
+	tests := map[string]bool{
+		"post_tweet":          true,
+		"category:engagement": true,
+		"post_tweets":         false,
+		"category:dm":         false,
+	}
+
+	for selector, expected := range tests {
+		if result := isValidToolSelector(selector); result != expected {
+			t.Errorf("isValidToolSelector(%q) = %v, expected %v", selector, result, expected)
+		}
+	}
+}
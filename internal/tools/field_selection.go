@@ -0,0 +1,111 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fieldsArgument is the argument of read tools selecting the tweet or user fields to return
+const fieldsArgument = "fields"
+
+// withFieldsArgument declares the 'fields' argument. Tools declaring it get their output filtered by addTool
+func withFieldsArgument(items string) mcp.ToolOption {
+	return mcp.WithArray(fieldsArgument,
+		mcp.Description("Optional: only return these fields of the "+items+" to save context, e.g. [\"text\", \"public_metrics\"]. "+
+			"'id' is always kept, and meta and includes are left untouched (default: all fields)"),
+	)
+}
+
+// hasFieldsArgument checks whether a tool declares the 'fields' argument
+func hasFieldsArgument(tool mcp.Tool) bool {
+	_, declared := tool.InputSchema.Properties[fieldsArgument]
+	return declared
+}
+
+// withFieldSelection wraps a tool handler, keeping only the requested fields of its items
+func withFieldSelection(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+
+		fields := getStringSlice(getArgs(request), fieldsArgument)
+		if err != nil || result == nil || result.IsError || len(fields) == 0 || len(result.Content) != 1 {
+			return result, err
+		}
+
+		textContent, isText := result.Content[0].(mcp.TextContent)
+		if !isText {
+			return result, nil
+		}
+
+		selected, selectErr := selectFields(textContent.Text, fields)
+		if selectErr != nil {
+			return result, nil
+		}
+		return mcp.NewToolResultText(selected), nil
+	}
+}
+
+// selectFields keeps only the given fields of the items of a JSON result, plus their 'id'.
+// Items are the entries of 'data' when present, or the result itself, either an object or an array
+func selectFields(text string, fields []string) (string, error) {
+	// Numbers are kept as they are, so big IDs don't lose precision
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return "", err
+	}
+
+	if object, isObject := decoded.(map[string]any); isObject {
+		if data, hasData := object["data"]; hasData {
+			object["data"] = filterItems(data, fields)
+		} else {
+			decoded = filterItems(object, fields)
+		}
+	} else {
+		decoded = filterItems(decoded, fields)
+	}
+
+	selected, err := json.Marshal(decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(selected), nil
+}
+
+// filterItems keeps the selected fields of an object, or of every object in an array
+func filterItems(value any, fields []string) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key := range typed {
+			if key != "id" && !slices.Contains(fields, key) {
+				delete(typed, key)
+			}
+		}
+	case []any:
+		for i, item := range typed {
+			typed[i] = filterItems(item, fields)
+		}
+	}
+	return value
+}
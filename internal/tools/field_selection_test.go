@@ -0,0 +1,82 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSelectFields(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected string
+	}{
+		"data array": {
+			text:     `{"data":[{"id":"1","text":"a","author_id":"9","public_metrics":{"like_count":3}}],"meta":{"result_count":1}}`,
+			expected: `{"data":[{"id":"1","public_metrics":{"like_count":3},"text":"a"}],"meta":{"result_count":1}}`,
+		},
+		"object": {
+			text:     `{"id":"1","username":"me","description":"bio","protected":true}`,
+			expected: `{"id":"1","username":"me"}`,
+		},
+		"array": {
+			text:     `[{"id":"1","text":"a","lang":"en"},{"id":"2","text":"b"}]`,
+			expected: `[{"id":"1","text":"a"},{"id":"2","text":"b"}]`,
+		},
+		"big ids": {
+			text:     `{"data":[{"id":"1","media_id":1234567890123456789}]}`,
+			expected: `{"data":[{"id":"1","media_id":1234567890123456789}]}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, err := selectFields(test.text, []string{"text", "username", "public_metrics", "media_id"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if selected != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, selected)
+			}
+		})
+	}
+
+	if _, err := selectFields("not json", []string{"text"}); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+}
+
+func TestWithFieldSelection(t *testing.T) {
+	handler := withFieldSelection(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"data":[{"id":"1","text":"a","lang":"en"}]}`), nil
+	})
+
+	result, _ := handler(context.Background(), newCallToolRequest(map[string]any{}))
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"data":[{"id":"1","text":"a","lang":"en"}]}` {
+		t.Errorf("expected the full output without fields, got %s", text)
+	}
+
+	result, _ = handler(context.Background(), newCallToolRequest(map[string]any{"fields": []any{"lang"}}))
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"data":[{"id":"1","lang":"en"}]}` {
+		t.Errorf("expected only the selected fields, got %s", text)
+	}
+
+	if !hasFieldsArgument(mcp.NewTool("test", withFieldsArgument("tweets"))) || hasFieldsArgument(mcp.NewTool("test")) {
+		t.Errorf("expected the fields argument to be detected only when declared")
+	}
+}
@@ -17,6 +17,13 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"twitter-mcp/internal/twitter"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -26,45 +33,180 @@ func (tm *ToolsManager) HandleToolPostTweet(ctx context.Context, request mcp.Cal
 	args := getArgs(request)
 	text := getString(args, "text", "")
 	replyToID := getString(args, "reply_to_id", "")
+	if replyToID != "" {
+		var err error
+		if replyToID, err = getTweetID(args, "reply_to_id"); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	placeID, hasPlaceID := args["place_id"].(string)
+	placeID = strings.TrimSpace(placeID)
+	if hasPlaceID && placeID == "" {
+		return mcp.NewToolResultError("place_id must not be empty when provided, use search_places to find one"), nil
+	}
 
-	tweet, err := tm.dependencies.TwitterClient.PostTweet(text, replyToID)
+	idempotencyKey := getString(args, "idempotency_key", "")
+	if idempotencyKey != "" {
+		idempotencyKey = "post_tweet/" + idempotencyKey
+		if used := tm.reserveIdempotencyKey(ctx, idempotencyKey); used != nil {
+			return used, nil
+		}
+	}
+
+	tweet, err := tm.dependencies.TwitterClient.PostTweet(ctx, text, replyToID, twitter.PostTweetOptions{PlaceID: placeID})
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		if idempotencyKey != "" {
+			tm.releaseIdempotencyKey(idempotencyKey, err)
+		}
+		return tm.toolError(err), nil
 	}
 
 	result, _ := json.Marshal(tweet)
+	if idempotencyKey != "" {
+		tm.idempotency.Complete(idempotencyKey, string(result))
+	}
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolPreviewTweet handles the preview_tweet tool
+func (tm *ToolsManager) HandleToolPreviewTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	text := getString(args, "text", "")
+
+	if text == "" {
+		return mcp.NewToolResultError("text is required"), nil
+	}
+
+	preview, err := twitter.PreviewTweet(text, getStringSlice(args, "media_paths"))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, _ := json.Marshal(preview)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolDeleteTweet handles the delete_tweet tool
 func (tm *ToolsManager) HandleToolDeleteTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
-
-	err := tm.dependencies.TwitterClient.DeleteTweet(tweetID)
+	tweetID, err := getTweetID(args, "tweet_id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	err = tm.dependencies.TwitterClient.DeleteTweet(ctx, tweetID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
 	return mcp.NewToolResultText(`{"success": true, "message": "Tweet deleted"}`), nil
 }
 
+// HandleToolDeleteThread handles the delete_thread tool
+func (tm *ToolsManager) HandleToolDeleteThread(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	rawIDs := getStringSlice(args, "tweet_ids")
+	rootTweetID := getString(args, "root_tweet_id", "")
+
+	if (len(rawIDs) == 0) == (rootTweetID == "") {
+		return mcp.NewToolResultError("pass either tweet_ids or root_tweet_id"), nil
+	}
+
+	var tweetIDs []string
+	for _, rawID := range rawIDs {
+		tweetID, err := twitter.ParseTweetID(rawID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid tweet_ids: %v", err)), nil
+		}
+		tweetIDs = append(tweetIDs, tweetID)
+	}
+
+	if rootTweetID != "" {
+		rootID, err := getTweetID(args, "root_tweet_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+		}
+
+		tweetIDs, err = tm.dependencies.TwitterClient.GetThreadTweetIDs(ctx, rootID, me.Username)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+	}
+
+	summary, err := tm.dependencies.TwitterClient.DeleteThread(ctx, tweetIDs)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(summary)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolPinTweet handles the pin_tweet tool
+func (tm *ToolsManager) HandleToolPinTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	err = tm.dependencies.TwitterClient.PinTweet(ctx, tweetID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	return mcp.NewToolResultText(`{"success": true, "message": "Tweet pinned"}`), nil
+}
+
 // HandleToolGetTimeline handles the get_timeline tool
 func (tm *ToolsManager) HandleToolGetTimeline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	maxResults := getInt(args, "max_results", 10)
+	maxResults := tm.maxResults.get(args, 1)
 
 	// First get the authenticated user's ID
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	timeline, err := tm.dependencies.TwitterClient.GetTimeline(me.ID, maxResults)
+	opts := twitter.TimelineOptions{
+		Exclude: getStringSlice(args, "exclude"),
+		SinceID: getString(args, "since_id", ""),
+		UntilID: getString(args, "until_id", ""),
+	}
+
+	fromUsernames, err := getOptionalUsernames(args, "from_usernames")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	excludeUsernames, err := getOptionalUsernames(args, "exclude_usernames")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	if fetchAll, maxTotal := getFetchAll(args); fetchAll {
+		timeline, err := tm.dependencies.TwitterClient.GetTimelineAll(ctx, me.ID, maxTotal, opts)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+		twitter.FilterTweetsByAuthor(&timeline.TweetsResponse, fromUsernames, excludeUsernames)
+
+		result, _ := json.Marshal(timeline)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	timeline, err := tm.dependencies.TwitterClient.GetTimeline(ctx, me.ID, maxResults, opts)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+	twitter.FilterTweetsByAuthor(timeline, fromUsernames, excludeUsernames)
+
 	result, _ := json.Marshal(timeline)
 	return mcp.NewToolResultText(string(result)), nil
 }
@@ -72,49 +214,223 @@ func (tm *ToolsManager) HandleToolGetTimeline(ctx context.Context, request mcp.C
 // HandleToolGetMentions handles the get_mentions tool
 func (tm *ToolsManager) HandleToolGetMentions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	maxResults := getInt(args, "max_results", 10)
+	maxResults := tm.maxResults.get(args, 5)
 
 	// First get the authenticated user's ID
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	mentions, err := tm.dependencies.TwitterClient.GetMentions(me.ID, maxResults)
+	mentions, err := tm.dependencies.TwitterClient.GetMentions(ctx, me.ID, maxResults, getString(args, "since_id", ""))
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	result, _ := json.Marshal(mentions)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolGetMentionsOf handles the get_mentions_of tool
+func (tm *ToolsManager) HandleToolGetMentionsOf(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	maxResults := tm.maxResults.get(args, 10)
+
+	username, err := getUsername(args, "username")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	startTime, endTime, err := getTimeRange(args)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	if fetchAll, maxTotal := getFetchAll(args); fetchAll {
+		tweets, err := tm.dependencies.TwitterClient.GetMentionsOfAll(ctx, username, startTime, endTime, maxTotal)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+
+		result, _ := json.Marshal(tweets)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.GetMentionsOf(ctx, username, startTime, endTime, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(tweets)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolSearchTweets handles the search_tweets tool
 func (tm *ToolsManager) HandleToolSearchTweets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
 	query := getString(args, "query", "")
-	maxResults := getInt(args, "max_results", 10)
+	maxResults := tm.maxResults.get(args, 10)
+
+	startTime, endTime, err := getTimeRange(args)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
 
-	tweets, err := tm.dependencies.TwitterClient.SearchTweets(query, maxResults)
+	query, err = getSearchFilters(args).apply(query)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	sortBy := getString(args, "sort_by", "")
+	if sortBy != "" && !slices.Contains(twitter.TweetSortMetrics, sortBy) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid sort_by '%s': must be one of %v", sortBy, twitter.TweetSortMetrics)), nil
+	}
+
+	sortOrder := getString(args, "sort_order", "")
+
+	if fetchAll, maxTotal := getFetchAll(args); fetchAll {
+		tweets, err := tm.dependencies.TwitterClient.SearchTweetsInRangeAll(ctx, query, startTime, endTime, maxTotal, sortOrder)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+
+		if sortBy != "" {
+			twitter.SortTweetsByMetric(tweets.Data, sortBy)
+		}
+
+		result, _ := json.Marshal(tweets)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.SearchTweetsInRange(ctx, query, startTime, endTime, maxResults, sortOrder)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	if sortBy != "" {
+		twitter.SortTweetsByMetric(tweets.Data, sortBy)
+	}
+
+	result, _ := json.Marshal(tweets)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolSearchAll handles the search_all tool
+func (tm *ToolsManager) HandleToolSearchAll(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := getString(args, "query", "")
+	maxResults := getInt(args, "max_results", 10)
+
+	startTime, endTime, err := getTimeRange(args)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.SearchTweetsAll(ctx, query, startTime, endTime, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
 	result, _ := json.Marshal(tweets)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolGetTrends handles the get_trends tool
 func (tm *ToolsManager) HandleToolGetTrends(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := getArgs(request)
+	woeid, errResult := tm.resolveTrendsWOEID(ctx, getArgs(request))
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	snapshot, err := tm.dependencies.TwitterClient.GetTrendsSnapshot(ctx, woeid)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	// Trends are cached per location, so tell how old they are
+	result, _ := json.Marshal(map[string]any{
+		"woeid":             snapshot.WOEID,
+		"trends":            snapshot.Trends,
+		"fetched_at":        snapshot.FetchedAt.UTC().Format(time.RFC3339),
+		"cache_age_seconds": int(time.Since(snapshot.FetchedAt).Seconds()),
+	})
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// resolveTrendsWOEID gets the WOEID of trend tools from 'location_name', 'lat'/'long' or 'woeid', in that order.
+// Errors come as a tool result
+func (tm *ToolsManager) resolveTrendsWOEID(ctx context.Context, args map[string]any) (int, *mcp.CallToolResult) {
 	woeid := getInt(args, "woeid", 1)
 
-	trends, err := tm.dependencies.TwitterClient.GetTrends(woeid)
+	locationName := getString(args, "location_name", "")
+	lat, hasLat := args["lat"].(float64)
+	long, hasLong := args["long"].(float64)
+
+	if hasLat != hasLong {
+		return 0, mcp.NewToolResultError("lat and long must be provided together")
+	}
+	if locationName != "" && hasLat {
+		return 0, mcp.NewToolResultError("use either location_name or lat/long, not both")
+	}
+
+	switch {
+	case locationName != "":
+		resolved, err := tm.dependencies.TwitterClient.ResolveTrendLocation(ctx, locationName)
+		if err != nil {
+			return 0, tm.toolError(err)
+		}
+		woeid = resolved
+
+	case hasLat:
+		locations, err := tm.dependencies.TwitterClient.GetClosestTrendLocations(ctx, lat, long)
+		if err != nil {
+			return 0, tm.toolError(err)
+		}
+		woeid = locations[0].WOEID
+	}
+
+	return woeid, nil
+}
+
+// HandleToolListTrendLocations handles the list_trend_locations tool
+func (tm *ToolsManager) HandleToolListTrendLocations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := strings.ToLower(getString(args, "query", ""))
+
+	locations, err := tm.dependencies.TwitterClient.GetAvailableTrendLocations(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
+	}
+
+	filtered := []twitter.TrendLocation{}
+	for _, location := range locations {
+		if query != "" &&
+			!strings.Contains(strings.ToLower(location.Name), query) &&
+			!strings.Contains(strings.ToLower(location.Country), query) {
+			continue
+		}
+		filtered = append(filtered, location)
+	}
+
+	result, _ := json.Marshal(filtered)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolSearchPlaces handles the search_places tool
+func (tm *ToolsManager) HandleToolSearchPlaces(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := getString(args, "query", "")
+
+	if query == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+
+	places, err := tm.dependencies.TwitterClient.SearchPlaces(ctx, query)
+	if err != nil {
+		return tm.toolError(err), nil
 	}
 
-	result, _ := json.Marshal(trends)
+	result, _ := json.Marshal(places)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
@@ -136,12 +452,33 @@ func (tm *ToolsManager) HandleToolSearchTopics(ctx context.Context, request mcp.
 		return mcp.NewToolResultError("no topics provided"), nil
 	}
 
-	results, err := tm.dependencies.TwitterClient.GetTrendsByTopic(topics, maxResults)
+	results, err := tm.dependencies.TwitterClient.GetTrendsByTopic(ctx, topics, maxResults)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
+	}
+
+	// Failed topics are reported apart, so they are not mistaken for topics without tweets
+	response := struct {
+		Results map[string]*twitter.TweetsResponse `json:"results"`
+		Failed  map[string]twitter.ErrorDetails    `json:"failed,omitempty"`
+	}{
+		Results: make(map[string]*twitter.TweetsResponse),
+	}
+
+	for topic, topicResult := range results {
+		if topicResult.Err != nil {
+			tm.dependencies.AppCtx.Logger.Debug("topic search failed", "topic", topic, "error", topicResult.Err.Error())
+
+			if response.Failed == nil {
+				response.Failed = make(map[string]twitter.ErrorDetails)
+			}
+			response.Failed[topic] = twitter.GetErrorDetails(topicResult.Err)
+			continue
+		}
+		response.Results[topic] = topicResult.Tweets
 	}
 
-	result, _ := json.Marshal(results)
+	result, _ := json.Marshal(response)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
@@ -160,57 +497,106 @@ func (tm *ToolsManager) HandleToolGetTopicsHeat(ctx context.Context, request mcp
 		return mcp.NewToolResultError("no topics provided"), nil
 	}
 
-	heatResults, err := tm.dependencies.TwitterClient.GetTopicsHeat(topics, sampleSize)
+	scoring := getString(args, "scoring", twitter.HeatScoringEngagement)
+	if !slices.Contains(twitter.HeatScorings, scoring) {
+		return mcp.NewToolResultError(fmt.Sprintf("scoring must be one of: %s", strings.Join(twitter.HeatScorings, ", "))), nil
+	}
+
+	heatResults, err := tm.dependencies.TwitterClient.GetTopicsHeat(ctx, topics, sampleSize, scoring)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	result, _ := json.Marshal(heatResults)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolGetTweetTopics handles the get_tweet_topics tool
+func (tm *ToolsManager) HandleToolGetTweetTopics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := getString(args, "query", "")
+	maxResults := getInt(args, "max_results", 50)
+
+	tweets, err := tm.dependencies.TwitterClient.SearchTweets(ctx, query, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	topics := twitter.GetTweetTopics(tweets.Data)
+	if len(topics) == 0 {
+		return mcp.NewToolResultText(`{"topics": [], "message": "No topic annotations available for the matching tweets"}`), nil
+	}
+
+	result, _ := json.Marshal(map[string]interface{}{
+		"tweet_count": len(tweets.Data),
+		"topics":      topics,
+	})
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolGetMe handles the get_me tool
 func (tm *ToolsManager) HandleToolGetMe(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	result, _ := json.Marshal(me)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolGetMeFull handles the get_me_full tool
+func (tm *ToolsManager) HandleToolGetMeFull(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	profile, err := tm.dependencies.TwitterClient.GetMeProfile(ctx)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(profile)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolLikeTweet handles the like_tweet tool
 func (tm *ToolsManager) HandleToolLikeTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.LikeTweet(me.ID, tweetID)
+	liked, err := tm.dependencies.TwitterClient.LikeTweet(ctx, me.ID, tweetID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
-	return mcp.NewToolResultText(`{"success": true, "message": "Tweet liked"}`), nil
+	result, _ := json.Marshal(map[string]any{"success": true, "liked": liked.Liked, "message": "Tweet liked"})
+	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolUnlikeTweet handles the unlike_tweet tool
 func (tm *ToolsManager) HandleToolUnlikeTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.UnlikeTweet(me.ID, tweetID)
+	err = tm.dependencies.TwitterClient.UnlikeTweet(ctx, me.ID, tweetID)
+	if errors.Is(err, twitter.ErrNothingToUndo) {
+		return mcp.NewToolResultText(`{"success": true, "changed": false, "message": "Tweet was not liked, nothing to undo"}`), nil
+	}
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	return mcp.NewToolResultText(`{"success": true, "message": "Tweet unliked"}`), nil
@@ -219,34 +605,44 @@ func (tm *ToolsManager) HandleToolUnlikeTweet(ctx context.Context, request mcp.C
 // HandleToolRetweet handles the retweet tool
 func (tm *ToolsManager) HandleToolRetweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.Retweet(me.ID, tweetID)
+	retweeted, err := tm.dependencies.TwitterClient.Retweet(ctx, me.ID, tweetID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
-	return mcp.NewToolResultText(`{"success": true, "message": "Tweet retweeted"}`), nil
+	result, _ := json.Marshal(map[string]any{"success": true, "retweeted": retweeted.Retweeted, "message": "Tweet retweeted"})
+	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolUndoRetweet handles the undo_retweet tool
 func (tm *ToolsManager) HandleToolUndoRetweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.UndoRetweet(me.ID, tweetID)
+	err = tm.dependencies.TwitterClient.UndoRetweet(ctx, me.ID, tweetID)
+	if errors.Is(err, twitter.ErrNothingToUndo) {
+		return mcp.NewToolResultText(`{"success": true, "changed": false, "message": "Tweet was not retweeted, nothing to undo"}`), nil
+	}
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	return mcp.NewToolResultText(`{"success": true, "message": "Retweet removed"}`), nil
@@ -255,114 +651,429 @@ func (tm *ToolsManager) HandleToolUndoRetweet(ctx context.Context, request mcp.C
 // HandleToolFollowUser handles the follow_user tool
 func (tm *ToolsManager) HandleToolFollowUser(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	username := getString(args, "username", "")
+	username, err := getUsername(args, "username")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	targetUser, err := tm.dependencies.TwitterClient.GetUserByUsername(username)
+	targetUser, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get target user: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get target user: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.FollowUser(me.ID, targetUser.ID)
+	follow, err := tm.dependencies.TwitterClient.FollowUser(ctx, me.ID, targetUser.ID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
+	}
+
+	// Protected accounts have to approve the follow first
+	message := "User followed"
+	if follow.PendingFollow {
+		message = "Follow request sent, pending approval by the user"
 	}
 
-	return mcp.NewToolResultText(`{"success": true, "message": "User followed"}`), nil
+	result, _ := json.Marshal(map[string]any{
+		"success":        true,
+		"following":      follow.Following,
+		"pending_follow": follow.PendingFollow,
+		"message":        message,
+	})
+	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolUnfollowUser handles the unfollow_user tool
 func (tm *ToolsManager) HandleToolUnfollowUser(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	username := getString(args, "username", "")
+	username, err := getUsername(args, "username")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	targetUser, err := tm.dependencies.TwitterClient.GetUserByUsername(username)
+	targetUser, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get target user: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get target user: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.UnfollowUser(me.ID, targetUser.ID)
+	err = tm.dependencies.TwitterClient.UnfollowUser(ctx, me.ID, targetUser.ID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	return mcp.NewToolResultText(`{"success": true, "message": "User unfollowed"}`), nil
 }
 
+// HandleToolFollowUsers handles the follow_users tool
+func (tm *ToolsManager) HandleToolFollowUsers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	usernames, err := getUsernames(getArgs(request), "usernames")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+	}
+
+	summary, err := tm.dependencies.TwitterClient.FollowUsers(ctx, me.ID, usernames)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(summary)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolUnfollowUsers handles the unfollow_users tool
+func (tm *ToolsManager) HandleToolUnfollowUsers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	usernames, err := getUsernames(getArgs(request), "usernames")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+	}
+
+	summary, err := tm.dependencies.TwitterClient.UnfollowUsers(ctx, me.ID, usernames)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(summary)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolGetUserProfile handles the get_user_profile tool
 func (tm *ToolsManager) HandleToolGetUserProfile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	username := getString(args, "username", "")
-
-	profile, err := tm.dependencies.TwitterClient.GetUserProfile(username)
+	username, err := getUsername(args, "username")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	result, _ := json.Marshal(profile)
+	profile, err := tm.dependencies.TwitterClient.GetUserProfile(ctx, username)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	includeEngagement, _ := args["include_engagement"].(bool)
+	if !includeEngagement {
+		result, _ := json.Marshal(profile)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	response := struct {
+		*twitter.UserProfile
+		Engagement *twitter.EngagementStats `json:"engagement,omitempty"`
+		Warning    string                   `json:"warning,omitempty"`
+	}{UserProfile: profile}
+
+	// The engagement is a bonus, the profile is returned anyway when the tweets can't be fetched
+	recentTweets := min(max(getInt(args, "recent_tweets", 20), 5), 100)
+	tweets, err := tm.dependencies.TwitterClient.GetUserTweets(ctx, profile.ID, recentTweets)
+	if err != nil {
+		response.Warning = "engagement not available, failed to fetch recent tweets: " + twitter.GetErrorDetails(err).Message
+	} else {
+		followers := 0
+		if profile.PublicMetrics != nil {
+			followers = profile.PublicMetrics.FollowersCount
+		}
+		engagement := twitter.ComputeEngagement(tweets.Data, followers)
+		response.Engagement = &engagement
+	}
+
+	result, _ := json.Marshal(response)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolSearchUsers handles the search_users tool
+func (tm *ToolsManager) HandleToolSearchUsers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := getString(args, "query", "")
+	maxResults := getInt(args, "max_results", 20)
+
+	if query == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+
+	users, err := tm.dependencies.TwitterClient.SearchUsers(ctx, query, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(users)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolGetUserTweets handles the get_user_tweets tool
 func (tm *ToolsManager) HandleToolGetUserTweets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	username := getString(args, "username", "")
-	maxResults := getInt(args, "max_results", 10)
+	maxResults := tm.maxResults.get(args, 5)
 
-	user, err := tm.dependencies.TwitterClient.GetUserByUsername(username)
+	username, err := getUsername(args, "username")
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user: " + err.Error()), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	tweets, err := tm.dependencies.TwitterClient.GetUserTweets(user.ID, maxResults)
+	startTime, endTime, err := getTimeRange(args)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	// Private metrics are only given to the author of the tweets
+	if includePrivateMetrics, _ := args["include_private_metrics"].(bool); includePrivateMetrics {
+		me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+		}
+		if !strings.EqualFold(me.Username, username) {
+			return mcp.NewToolResultError(fmt.Sprintf("include_private_metrics is only available for your own tweets (@%s), not for @%s", me.Username, username)), nil
+		}
+
+		tweets, err := tm.dependencies.TwitterClient.GetOwnTweetsInRange(ctx, startTime, endTime, maxResults)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+
+		result, _ := json.Marshal(tweets)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	user, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user: %w", err)), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.GetUserTweetsInRange(ctx, user.ID, startTime, endTime, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(tweets)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolFindMutuals handles the find_mutuals tool
+func (tm *ToolsManager) HandleToolFindMutuals(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	limit := tm.networkUsersLimit()
+
+	username, err := getUsername(args, "username")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	user, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user: %w", err)), nil
+	}
+
+	followers, err := tm.dependencies.TwitterClient.GetFollowers(ctx, user.ID, limit)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get followers of %s: %w", username, err)), nil
+	}
+
+	// Without a second user, the overlap is with the accounts the authenticated user follows
+	var others *twitter.UsersResponse
+	if getString(args, "other_username", "") == "" {
+		me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+		}
+
+		others, err = tm.dependencies.TwitterClient.GetFollowing(ctx, me.ID, limit)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get followed users: %w", err)), nil
+		}
+	} else {
+		otherUsername, err := getUsername(args, "other_username")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		otherUser, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, otherUsername)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get user: %w", err)), nil
+		}
+
+		others, err = tm.dependencies.TwitterClient.GetFollowers(ctx, otherUser.ID, limit)
+		if err != nil {
+			return tm.toolError(fmt.Errorf("failed to get followers of %s: %w", otherUsername, err)), nil
+		}
+	}
+
+	result, _ := json.Marshal(twitter.IntersectUsers(followers, others))
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolFindInactiveFollowing handles the find_inactive_following tool
+func (tm *ToolsManager) HandleToolFindInactiveFollowing(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	limit := tm.activityChecksLimit()
+
+	inactiveDays := getInt(args, "inactive_days", 90)
+	if inactiveDays <= 0 {
+		return mcp.NewToolResultError("inactive_days must be greater than 0"), nil
+	}
+
+	maxUsers := getInt(args, "max_users", min(defaultActivityChecks, limit))
+	if maxUsers <= 0 || maxUsers > limit {
+		maxUsers = limit
+	}
+
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+	}
+
+	inactive, err := tm.dependencies.TwitterClient.FindInactiveFollowing(ctx, me.ID, inactiveDays, maxUsers)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get followed users: %w", err)), nil
+	}
+
+	result, _ := json.Marshal(inactive)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolGetTweetMetrics handles the get_tweet_metrics tool
+func (tm *ToolsManager) HandleToolGetTweetMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	metrics, err := tm.dependencies.TwitterClient.GetTweetMetrics(ctx, tweetID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(metrics)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolGetLikingUsers handles the get_liking_users tool
+func (tm *ToolsManager) HandleToolGetLikingUsers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	maxResults := getInt(args, "max_results", 100)
+
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	users, err := tm.dependencies.TwitterClient.GetLikingUsers(ctx, tweetID, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	if len(users.Data) == 0 {
+		return mcp.NewToolResultText(`{"result_count": 0, "message": "No liking users available: the tweet has no likes or they are hidden"}`), nil
+	}
+
+	result, _ := json.Marshal(users)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolGetQuoteTweets handles the get_quote_tweets tool
+func (tm *ToolsManager) HandleToolGetQuoteTweets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	maxResults := getInt(args, "max_results", 100)
+
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.GetQuoteTweets(ctx, tweetID, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	if len(tweets.Data) == 0 {
+		return mcp.NewToolResultText(`{"result_count": 0, "message": "No quote tweets available: the tweet has not been quoted"}`), nil
+	}
+
 	result, _ := json.Marshal(tweets)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolGetRetweeters handles the get_retweeters tool
+func (tm *ToolsManager) HandleToolGetRetweeters(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	maxResults := getInt(args, "max_results", 100)
+
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	users, err := tm.dependencies.TwitterClient.GetRetweeters(ctx, tweetID, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	if len(users.Data) == 0 {
+		return mcp.NewToolResultText(`{"result_count": 0, "message": "No retweeters available: the tweet has not been retweeted"}`), nil
+	}
+
+	result, _ := json.Marshal(users)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolBookmarkTweet handles the bookmark_tweet tool
 func (tm *ToolsManager) HandleToolBookmarkTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.BookmarkTweet(me.ID, tweetID)
+	bookmarked, err := tm.dependencies.TwitterClient.BookmarkTweet(ctx, me.ID, tweetID)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
-	return mcp.NewToolResultText(`{"success": true, "message": "Tweet bookmarked"}`), nil
+	result, _ := json.Marshal(map[string]any{"success": true, "bookmarked": bookmarked.Bookmarked, "message": "Tweet bookmarked"})
+	return mcp.NewToolResultText(string(result)), nil
 }
 
 // HandleToolRemoveBookmark handles the remove_bookmark tool
 func (tm *ToolsManager) HandleToolRemoveBookmark(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	tweetID := getString(args, "tweet_id", "")
+	tweetID, err := getTweetID(args, "tweet_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	err = tm.dependencies.TwitterClient.RemoveBookmark(me.ID, tweetID)
+	err = tm.dependencies.TwitterClient.RemoveBookmark(ctx, me.ID, tweetID)
+	if errors.Is(err, twitter.ErrNothingToUndo) {
+		return mcp.NewToolResultText(`{"success": true, "changed": false, "message": "Tweet was not bookmarked, nothing to undo"}`), nil
+	}
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	return mcp.NewToolResultText(`{"success": true, "message": "Bookmark removed"}`), nil
@@ -371,22 +1082,61 @@ func (tm *ToolsManager) HandleToolRemoveBookmark(ctx context.Context, request mc
 // HandleToolGetBookmarks handles the get_bookmarks tool
 func (tm *ToolsManager) HandleToolGetBookmarks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
-	maxResults := getInt(args, "max_results", 10)
+	maxResults := tm.maxResults.get(args, 1)
 
-	me, err := tm.dependencies.TwitterClient.GetMe()
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
 	if err != nil {
-		return mcp.NewToolResultError("failed to get user info: " + err.Error()), nil
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
 	}
 
-	bookmarks, err := tm.dependencies.TwitterClient.GetBookmarks(me.ID, maxResults)
+	if fetchAll, maxTotal := getFetchAll(args); fetchAll {
+		bookmarks, err := tm.dependencies.TwitterClient.GetBookmarksAll(ctx, me.ID, maxTotal)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+
+		result, _ := json.Marshal(bookmarks)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	bookmarks, err := tm.dependencies.TwitterClient.GetBookmarks(ctx, me.ID, maxResults)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return tm.toolError(err), nil
 	}
 
 	result, _ := json.Marshal(bookmarks)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolGetMyLikes handles the get_my_likes tool
+func (tm *ToolsManager) HandleToolGetMyLikes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	maxResults := tm.maxResults.get(args, 5)
+
+	me, err := tm.dependencies.TwitterClient.GetMe(ctx)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user info: %w", err)), nil
+	}
+
+	if fetchAll, maxTotal := getFetchAll(args); fetchAll {
+		likes, err := tm.dependencies.TwitterClient.GetLikedTweetsAll(ctx, me.ID, maxTotal)
+		if err != nil {
+			return tm.toolError(err), nil
+		}
+
+		result, _ := json.Marshal(likes)
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	likes, err := tm.dependencies.TwitterClient.GetLikedTweets(ctx, me.ID, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(likes)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolPostThread handles the post_thread tool
 func (tm *ToolsManager) HandleToolPostThread(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
@@ -396,13 +1146,63 @@ func (tm *ToolsManager) HandleToolPostThread(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultError("no tweets provided for thread"), nil
 	}
 
-	postedTweets, err := tm.dependencies.TwitterClient.PostThread(tweets)
+	return tm.postThread(ctx, "post_thread", tweets, "", getString(args, "idempotency_key", "")), nil
+}
+
+// HandleToolContinueThread handles the continue_thread tool
+func (tm *ToolsManager) HandleToolContinueThread(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	tweets := getStringSlice(args, "tweets")
+
+	if len(tweets) == 0 {
+		return mcp.NewToolResultError("no tweets provided for thread"), nil
+	}
+
+	replyToID, err := getTweetID(args, "reply_to_id")
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	result, _ := json.Marshal(postedTweets)
-	return mcp.NewToolResultText(string(result)), nil
+	// Replying to a missing tweet would start a new thread instead of continuing one
+	if _, err := tm.dependencies.TwitterClient.GetTweetByID(ctx, replyToID); err != nil {
+		return tm.toolError(fmt.Errorf("failed to check the tweet to reply to: %w", err)), nil
+	}
+
+	return tm.postThread(ctx, "continue_thread", tweets, replyToID, getString(args, "idempotency_key", "")), nil
 }
 
+// postThread posts a thread for post_thread and continue_thread. Partial failures return the posted tweets
+// as a tool error, which is kept under the idempotency key: retrying with it returns that error again instead
+// of reposting the head, and the rest is posted with continue_thread
+func (tm *ToolsManager) postThread(ctx context.Context, toolName string, tweets []string, replyToID, idempotencyKey string) *mcp.CallToolResult {
+	if idempotencyKey != "" {
+		idempotencyKey = toolName + "/" + idempotencyKey
+		if used := tm.reserveIdempotencyKey(ctx, idempotencyKey); used != nil {
+			return used
+		}
+	}
+
+	thread, err := tm.dependencies.TwitterClient.PostThread(ctx, tweets, replyToID)
+	if err != nil {
+		if thread == nil || thread.Posted == 0 {
+			if idempotencyKey != "" {
+				tm.releaseIdempotencyKey(idempotencyKey, err)
+			}
+			return tm.toolError(err)
+		}
+
+		// Part of the thread is already out, the caller needs the posted tweets to resume or delete them
+		tm.dependencies.AppCtx.Logger.Debug("thread posted partially", "posted", thread.Posted, "total", thread.Total, "error", err.Error())
+		result, _ := json.Marshal(thread)
+		if idempotencyKey != "" {
+			tm.idempotency.Fail(idempotencyKey, string(result))
+		}
+		return mcp.NewToolResultError(string(result))
+	}
 
+	result, _ := json.Marshal(thread)
+	if idempotencyKey != "" {
+		tm.idempotency.Complete(idempotencyKey, string(result))
+	}
+	return mcp.NewToolResultText(string(result))
+}
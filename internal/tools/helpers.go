@@ -14,7 +14,16 @@
 
 package tools
 
-import "github.com/mark3labs/mcp-go/mcp"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
 
 // getArgs safely extracts the Arguments map from a CallToolRequest
 func getArgs(request mcp.CallToolRequest) map[string]any {
@@ -52,3 +61,108 @@ func getStringSlice(args map[string]any, key string) []string {
 	}
 	return result
 }
+
+// getTweetID extracts a required tweet ID argument, accepting tweet URLs too
+func getTweetID(args map[string]any, key string) (string, error) {
+	v := getString(args, key, "")
+	if v == "" {
+		return "", fmt.Errorf("%s is required", key)
+	}
+
+	tweetID, err := twitter.ParseTweetID(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return tweetID, nil
+}
+
+// getUsername extracts a required username argument, accepting '@user' and profile URLs too
+func getUsername(args map[string]any, key string) (string, error) {
+	v := getString(args, key, "")
+	if v == "" {
+		return "", fmt.Errorf("%s is required", key)
+	}
+
+	username, err := twitter.NormalizeUsername(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return username, nil
+}
+
+// getUsernames extracts a required list of usernames, normalized like getUsername and without duplicates
+func getUsernames(args map[string]any, key string) ([]string, error) {
+	values := getStringSlice(args, key)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s is required", key)
+	}
+	if len(values) > twitter.MaxBatchSize {
+		return nil, fmt.Errorf("too many %s: max %d per call", key, twitter.MaxBatchSize)
+	}
+
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		username, err := twitter.NormalizeUsername(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+
+		// Handles are case-insensitive
+		if seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		usernames = append(usernames, username)
+	}
+	return usernames, nil
+}
+
+// getOptionalUsernames is getUsernames for arguments that can be left out, returning nil then
+func getOptionalUsernames(args map[string]any, key string) ([]string, error) {
+	if len(getStringSlice(args, key)) == 0 {
+		return nil, nil
+	}
+	return getUsernames(args, key)
+}
+
+// getTime extracts an optional RFC3339 time argument. Zero time is returned when it is absent
+func getTime(args map[string]any, key string) (time.Time, error) {
+	v := getString(args, key, "")
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s format, use RFC3339 (e.g. 2026-02-25T10:00:00Z): %s", key, err.Error())
+	}
+	return parsed, nil
+}
+
+// getTimeRange extracts optional 'start_time' and 'end_time' arguments, validating their order
+func getTimeRange(args map[string]any) (startTime time.Time, endTime time.Time, err error) {
+	startTime, err = getTime(args, "start_time")
+	if err != nil {
+		return startTime, endTime, err
+	}
+
+	endTime, err = getTime(args, "end_time")
+	if err != nil {
+		return startTime, endTime, err
+	}
+
+	if !startTime.IsZero() && !endTime.IsZero() && !startTime.Before(endTime) {
+		return startTime, endTime, fmt.Errorf("start_time must be before end_time")
+	}
+	return startTime, endTime, nil
+}
+
+// toolError builds a tool error result with a clean code and message instead of raw API bodies.
+// The original error is kept at debug level
+func (tm *ToolsManager) toolError(err error) *mcp.CallToolResult {
+	tm.dependencies.AppCtx.Logger.Debug("tool execution failed", "error", err.Error())
+
+	result, _ := json.Marshal(twitter.GetErrorDetails(err))
+	return mcp.NewToolResultError(string(result))
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultIdempotencyWindow is how long idempotency keys are remembered when not configured
+	defaultIdempotencyWindow = 1 * time.Hour
+)
+
+// idempotencyState is the stage of the write done under an idempotency key
+type idempotencyState int
+
+const (
+	// idempotencyPending is a write in progress, or one that failed without knowing whether X applied it
+	idempotencyPending idempotencyState = iota
+	// idempotencyDone is a write that succeeded, its result is returned again
+	idempotencyDone
+	// idempotencyFailed is a write that partially happened, e.g. a thread posted halfway, its error is returned again
+	idempotencyFailed
+)
+
+// idempotencyEntry represents the state and result stored for an idempotency key
+type idempotencyEntry struct {
+	state     idempotencyState
+	result    string
+	expiresAt time.Time
+}
+
+// idempotencyCache remembers the writes of write tools by caller-supplied key, so retries after a network
+// error do not post twice. Keys are reserved before writing, so concurrent calls with the same key do not write
+// twice either, and stay reserved when the outcome of the write is unknown
+type idempotencyCache struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	entries map[string]idempotencyEntry
+}
+
+func newIdempotencyCache(window time.Duration) *idempotencyCache {
+	if window <= 0 {
+		window = defaultIdempotencyWindow
+	}
+	return &idempotencyCache{
+		window:  window,
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+// Reserve claims a key before writing. When the key was already seen within the window,
+// it is not reserved and its entry is returned instead. Expired entries are purged on the way
+func (c *idempotencyCache) Reserve(key string) (idempotencyEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for storedKey, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, storedKey)
+		}
+	}
+
+	if entry, found := c.entries[key]; found {
+		return entry, false
+	}
+
+	c.entries[key] = idempotencyEntry{state: idempotencyPending, expiresAt: now.Add(c.window)}
+	return idempotencyEntry{}, true
+}
+
+// Complete stores the result of a successful write under a reserved key
+func (c *idempotencyCache) Complete(key string, result string) {
+	c.store(key, idempotencyDone, result)
+}
+
+// Fail stores the error of a write that partially happened under a reserved key
+func (c *idempotencyCache) Fail(key string, result string) {
+	c.store(key, idempotencyFailed, result)
+}
+
+// Release forgets a reserved key, for writes that surely did not happen, so they can be retried with it
+func (c *idempotencyCache) Release(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+}
+
+func (c *idempotencyCache) store(key string, state idempotencyState, result string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = idempotencyEntry{state: state, result: result, expiresAt: time.Now().Add(c.window)}
+}
+
+// idempotencyReplayKey holds, in the context of a tool call, whether it was answered without writing
+// because its idempotency key was already used, see trackIdempotencyReplay
+type idempotencyReplayKey struct{}
+
+// trackIdempotencyReplay returns a context where reserveIdempotencyKey reports calls answered
+// without writing, so wrappers like withWriteBudget do not count them
+func trackIdempotencyReplay(ctx context.Context) (context.Context, *bool) {
+	replayed := new(bool)
+	return context.WithValue(ctx, idempotencyReplayKey{}, replayed), replayed
+}
+
+// reserveIdempotencyKey reserves the key of a write tool call. It returns nil when the write can go on,
+// or the result to answer with when the key was already used
+func (tm *ToolsManager) reserveIdempotencyKey(ctx context.Context, key string) *mcp.CallToolResult {
+	entry, reserved := tm.idempotency.Reserve(key)
+	if reserved {
+		return nil
+	}
+
+	if replayed, ok := ctx.Value(idempotencyReplayKey{}).(*bool); ok {
+		*replayed = true
+	}
+
+	switch entry.state {
+	case idempotencyDone:
+		return mcp.NewToolResultText(entry.result)
+	case idempotencyFailed:
+		return mcp.NewToolResultError(entry.result)
+	default:
+		return mcp.NewToolResultError("a call with this idempotency_key is in progress, or failed without knowing whether X applied it. " +
+			"Check your recent tweets before retrying, and use a new idempotency_key to post again")
+	}
+}
+
+// releaseIdempotencyKey frees the key of a failed write when X surely did not apply it: it answered with
+// a client error or a rate limit. On network errors, timeouts or server errors the key stays reserved
+func (tm *ToolsManager) releaseIdempotencyKey(key string, err error) {
+	var apiErr *twitter.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+		tm.idempotency.Release(key)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	cache := newIdempotencyCache(50 * time.Millisecond)
+
+	if _, reserved := cache.Reserve("post_tweet/abc"); !reserved {
+		t.Fatalf("expected unknown key to be reserved")
+	}
+	if entry, reserved := cache.Reserve("post_tweet/abc"); reserved || entry.state != idempotencyPending {
+		t.Fatalf("expected a pending key not to be reserved twice, got %+v", entry)
+	}
+
+	cache.Complete("post_tweet/abc", `{"id": "1"}`)
+
+	entry, reserved := cache.Reserve("post_tweet/abc")
+	if reserved || entry.state != idempotencyDone || entry.result != `{"id": "1"}` {
+		t.Errorf("expected stored result, got %+v (reserved: %v)", entry, reserved)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, reserved := cache.Reserve("post_tweet/abc"); !reserved {
+		t.Errorf("expected key to expire after the window")
+	}
+
+	cache.Release("post_tweet/abc")
+	if _, reserved := cache.Reserve("post_tweet/abc"); !reserved {
+		t.Errorf("expected a released key to be reserved again")
+	}
+}
+
+func TestIdempotencyCacheDefaultWindow(t *testing.T) {
+	cache := newIdempotencyCache(0)
+	if cache.window != defaultIdempotencyWindow {
+		t.Errorf("expected default window %v, got %v", defaultIdempotencyWindow, cache.window)
+	}
+}
+
+// newPostingToolsManager returns a tools manager whose client posts to the handler
+func newPostingToolsManager(t *testing.T, handler http.HandlerFunc) *ToolsManager {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tm, _ := newTestToolsManager(api.ToolsConfig{})
+	tm.dependencies.TwitterClient = twitter.NewClientWithOptions(
+		twitter.WithCredentials("key", "secret", "token", "tokenSecret"),
+		twitter.WithBaseURL(server.URL),
+		twitter.WithRetry(twitter.RetryPolicy{BaseDelay: time.Millisecond}),
+	)
+	return tm
+}
+
+func TestPostTweetIdempotencyAfterFailures(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedPosts int32
+		expectRetried bool
+	}{
+		// X may have posted the tweet before failing, so the retry must not post again
+		{name: "server error keeps the key", status: http.StatusServiceUnavailable, expectedPosts: 1},
+		// X surely did not post it, so the retry can
+		{name: "client error releases the key", status: http.StatusForbidden, expectedPosts: 2, expectRetried: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var posts atomic.Int32
+			tm := newPostingToolsManager(t, func(w http.ResponseWriter, r *http.Request) {
+				if posts.Add(1) == 1 {
+					w.WriteHeader(test.status)
+					w.Write([]byte(`{"title": "failed"}`))
+					return
+				}
+				w.Write([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+			})
+
+			request := newCallToolRequest(map[string]any{"text": "hello", "idempotency_key": "abc"})
+			if result, _ := tm.HandleToolPostTweet(context.Background(), request); !result.IsError {
+				t.Fatalf("expected the first call to fail")
+			}
+
+			result, _ := tm.HandleToolPostTweet(context.Background(), request)
+			if posts.Load() != test.expectedPosts {
+				t.Errorf("expected %d posts, got %d", test.expectedPosts, posts.Load())
+			}
+			if result.IsError == test.expectRetried {
+				t.Errorf("expected the retry to succeed: %v, got %s", test.expectRetried, result.Content[0].(mcp.TextContent).Text)
+			}
+		})
+	}
+}
+
+func TestPostTweetIdempotencyConcurrentCalls(t *testing.T) {
+	var posts atomic.Int32
+	release := make(chan struct{})
+	tm := newPostingToolsManager(t, func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		<-release
+		w.Write([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+	})
+
+	request := newCallToolRequest(map[string]any{"text": "hello", "idempotency_key": "abc"})
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		tm.HandleToolPostTweet(context.Background(), request)
+	}()
+
+	// The second call arrives while the first one is still posting
+	for posts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	result, _ := tm.HandleToolPostTweet(context.Background(), request)
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "in progress") {
+		t.Errorf("expected an 'in progress' error, got %+v", result.Content)
+	}
+
+	close(release)
+	waitGroup.Wait()
+
+	result, _ = tm.HandleToolPostTweet(context.Background(), request)
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, `"id":"1"`) {
+		t.Errorf("expected the stored result once the first call is done, got %+v", result.Content)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("expected a single post, got %d", posts.Load())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HandleToolCreateList handles the create_list tool
+func (tm *ToolsManager) HandleToolCreateList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	name := getString(args, "name", "")
+	private, _ := args["private"].(bool)
+
+	list, err := tm.dependencies.TwitterClient.CreateList(ctx, name, private)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(list)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolAddListMember handles the add_list_member tool
+func (tm *ToolsManager) HandleToolAddListMember(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	listID := getString(args, "list_id", "")
+
+	if listID == "" {
+		return mcp.NewToolResultError("list_id is required"), nil
+	}
+
+	username, err := getUsername(args, "username")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	user, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user: %w", err)), nil
+	}
+
+	err = tm.dependencies.TwitterClient.AddListMember(ctx, listID, user.ID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	return mcp.NewToolResultText(`{"success": true, "message": "User added to list"}`), nil
+}
+
+// HandleToolRemoveListMember handles the remove_list_member tool
+func (tm *ToolsManager) HandleToolRemoveListMember(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	listID := getString(args, "list_id", "")
+
+	if listID == "" {
+		return mcp.NewToolResultError("list_id is required"), nil
+	}
+
+	username, err := getUsername(args, "username")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	user, err := tm.dependencies.TwitterClient.GetUserByUsername(ctx, username)
+	if err != nil {
+		return tm.toolError(fmt.Errorf("failed to get user: %w", err)), nil
+	}
+
+	err = tm.dependencies.TwitterClient.RemoveListMember(ctx, listID, user.ID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	return mcp.NewToolResultText(`{"success": true, "message": "User removed from list"}`), nil
+}
+
+// HandleToolGetListTweets handles the get_list_tweets tool
+func (tm *ToolsManager) HandleToolGetListTweets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	listID := getString(args, "list_id", "")
+	maxResults := tm.maxResults.get(args, 1)
+
+	if listID == "" {
+		return mcp.NewToolResultError("list_id is required"), nil
+	}
+
+	tweets, err := tm.dependencies.TwitterClient.GetListTweets(ctx, listID, maxResults)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(tweets)
+	return mcp.NewToolResultText(string(result)), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/twitter"
+)
+
+const (
+	// apiMaxResults is the hard limit of 'max_results' for most v2 read endpoints
+	apiMaxResults = 100
+
+	// defaultMaxResults is used when neither the caller nor the config set a value
+	defaultMaxResults = 10
+
+	// maxNetworkUsers is the default and the hard limit of users fetched per account by network tools
+	maxNetworkUsers = 1000
+
+	// defaultActivityChecks and maxActivityChecks are the default and the hard limit of accounts
+	// checked by find_inactive_following, as every one of them costs a request
+	defaultActivityChecks = 50
+	maxActivityChecks     = 200
+)
+
+// maxResultsLimits holds the default and the cap applied to 'max_results' arguments
+type maxResultsLimits struct {
+	defaultValue int
+	maxValue     int
+}
+
+// newMaxResultsLimits builds the limits from config, clamped to the API hard limits
+func newMaxResultsLimits(config api.ToolsConfig) maxResultsLimits {
+	limits := maxResultsLimits{
+		defaultValue: config.DefaultMaxResults,
+		maxValue:     config.MaxMaxResults,
+	}
+
+	if limits.maxValue <= 0 || limits.maxValue > apiMaxResults {
+		limits.maxValue = apiMaxResults
+	}
+	if limits.defaultValue <= 0 {
+		limits.defaultValue = defaultMaxResults
+	}
+	if limits.defaultValue > limits.maxValue {
+		limits.defaultValue = limits.maxValue
+	}
+
+	return limits
+}
+
+// get extracts the 'max_results' argument, applying the default and the cap.
+// apiMin is the lowest value the endpoint accepts, which always wins over the config
+func (l maxResultsLimits) get(args map[string]any, apiMin int) int {
+	maxResults := getInt(args, "max_results", l.defaultValue)
+	if maxResults <= 0 {
+		maxResults = l.defaultValue
+	}
+	if maxResults > l.maxValue {
+		maxResults = l.maxValue
+	}
+	if maxResults < apiMin {
+		maxResults = apiMin
+	}
+	return maxResults
+}
+
+// description returns the 'max_results' argument description for the given items
+func (l maxResultsLimits) description(items string) string {
+	return fmt.Sprintf("Maximum number of %s to return (default: %d, max: %d)", items, l.defaultValue, l.maxValue)
+}
+
+// networkUsersLimit returns how many followers or followed users network tools fetch per account
+func (tm *ToolsManager) networkUsersLimit() int {
+	limit := tm.dependencies.AppCtx.Config.Tools.MaxNetworkUsers
+	if limit <= 0 || limit > maxNetworkUsers {
+		return maxNetworkUsers
+	}
+	return limit
+}
+
+// activityChecksLimit returns how many followed accounts find_inactive_following checks at most
+func (tm *ToolsManager) activityChecksLimit() int {
+	limit := tm.dependencies.AppCtx.Config.Tools.MaxActivityChecks
+	if limit <= 0 || limit > maxActivityChecks {
+		return maxActivityChecks
+	}
+	return limit
+}
+
+// getFetchAll extracts the 'fetch_all' and 'max_total' arguments of tools able to follow pagination.
+// The total defaults to, and is capped at, twitter.MaxPaginatedTweets
+func getFetchAll(args map[string]any) (bool, int) {
+	fetchAll, _ := args["fetch_all"].(bool)
+
+	maxTotal := getInt(args, "max_total", twitter.MaxPaginatedTweets)
+	if maxTotal <= 0 || maxTotal > twitter.MaxPaginatedTweets {
+		maxTotal = twitter.MaxPaginatedTweets
+	}
+	return fetchAll, maxTotal
+}
+
+// fetchAllDescription returns the 'fetch_all' argument description for the given items
+func fetchAllDescription(items string) string {
+	return fmt.Sprintf("Optional: follow pagination to collect up to max_total %s across several requests, instead of a single page of max_results. "+
+		"Stops early on the rate limit, returning what was collected with 'rate_limited: true'", items)
+}
+
+// maxTotalDescription returns the 'max_total' argument description for the given items
+func maxTotalDescription(items string) string {
+	return fmt.Sprintf("Optional: maximum number of %s collected with fetch_all (default and max: %d)", items, twitter.MaxPaginatedTweets)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+
+	"twitter-mcp/api"
+)
+
+func TestNewMaxResultsLimits(t *testing.T) {
+	tests := []struct {
+		config          api.ToolsConfig
+		expectedDefault int
+		expectedMax     int
+	}{
+		{config: api.ToolsConfig{}, expectedDefault: 10, expectedMax: 100},
+		{config: api.ToolsConfig{DefaultMaxResults: 5, MaxMaxResults: 20}, expectedDefault: 5, expectedMax: 20},
+		{config: api.ToolsConfig{MaxMaxResults: 500}, expectedDefault: 10, expectedMax: 100},
+		{config: api.ToolsConfig{DefaultMaxResults: 50, MaxMaxResults: 25}, expectedDefault: 25, expectedMax: 25},
+	}
+
+	for _, tt := range tests {
+		limits := newMaxResultsLimits(tt.config)
+		if limits.defaultValue != tt.expectedDefault || limits.maxValue != tt.expectedMax {
+			t.Errorf("newMaxResultsLimits(%+v) = %d/%d, expected %d/%d",
+				tt.config, limits.defaultValue, limits.maxValue, tt.expectedDefault, tt.expectedMax)
+		}
+	}
+}
+
+func TestMaxResultsLimitsGet(t *testing.T) {
+	limits := newMaxResultsLimits(api.ToolsConfig{DefaultMaxResults: 5, MaxMaxResults: 20})
+
+	tests := []struct {
+		args     map[string]any
+		apiMin   int
+		expected int
+	}{
+		{args: map[string]any{}, apiMin: 1, expected: 5},
+		{args: map[string]any{"max_results": float64(15)}, apiMin: 1, expected: 15},
+		{args: map[string]any{"max_results": float64(80)}, apiMin: 1, expected: 20},
+		{args: map[string]any{"max_results": float64(0)}, apiMin: 1, expected: 5},
+		{args: map[string]any{}, apiMin: 10, expected: 10},
+	}
+
+	for _, tt := range tests {
+		if result := limits.get(tt.args, tt.apiMin); result != tt.expected {
+			t.Errorf("get(%v, %d) = %d, expected %d", tt.args, tt.apiMin, result, tt.expected)
+		}
+	}
+}
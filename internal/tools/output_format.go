@@ -0,0 +1,158 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// formatArgument is the argument of read tools choosing how tweets are rendered
+	formatArgument = "format"
+
+	outputFormatJSON     = "json"
+	outputFormatMarkdown = "markdown"
+	outputFormatText     = "text"
+)
+
+// outputFormats are the accepted values of the 'format' argument
+var outputFormats = []string{outputFormatJSON, outputFormatMarkdown, outputFormatText}
+
+// formattedTools are the tools returning tweets, whose 'format' argument is handled by withOutputFormat.
+// Other tools may have a 'format' argument of their own (e.g. schedule_export), so it is not enough to declare it
+var formattedTools = map[string]bool{
+	"get_timeline":     true,
+	"get_mentions":     true,
+	"get_mentions_of":  true,
+	"search_tweets":    true,
+	"search_all":       true,
+	"get_user_tweets":  true,
+	"get_quote_tweets": true,
+	"get_bookmarks":    true,
+	"get_my_likes":     true,
+	"get_list_tweets":  true,
+}
+
+// withFormatArgument declares the 'format' argument of the tools listed in formattedTools
+func withFormatArgument() mcp.ToolOption {
+	return mcp.WithString(formatArgument,
+		mcp.Description("Optional: 'json' (default), or 'markdown' and 'text' to get a readable list of tweets like '@author: text (❤ 12 🔁 3 💬 1)'. "+
+			"They take far fewer tokens, handy for summaries. 'fields' is ignored with them"),
+	)
+}
+
+// withOutputFormat wraps a tool handler returning tweets, rendering them in the requested format
+func withOutputFormat(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format := getString(getArgs(request), formatArgument, outputFormatJSON)
+		if !slices.Contains(outputFormats, format) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid format '%s': must be one of %v", format, outputFormats)), nil
+		}
+
+		result, err := handler(ctx, request)
+		if err != nil || result == nil || result.IsError || format == outputFormatJSON || len(result.Content) != 1 {
+			return result, err
+		}
+
+		textContent, isText := result.Content[0].(mcp.TextContent)
+		if !isText {
+			return result, nil
+		}
+
+		formatted, formatErr := formatTweets(textContent.Text, format)
+		if formatErr != nil {
+			return result, nil
+		}
+		return mcp.NewToolResultText(formatted), nil
+	}
+}
+
+// formattedTweets is what formatTweets reads from a JSON result: a page of tweets or several ones (fetch_all),
+// or a message when there are no tweets
+type formattedTweets struct {
+	twitter.PaginatedTweets
+	Message string `json:"message,omitempty"`
+}
+
+// formatTweets renders the tweets of a JSON result as a markdown or text list, one tweet per item
+func formatTweets(text string, format string) (string, error) {
+	var tweets formattedTweets
+	if err := json.Unmarshal([]byte(text), &tweets); err != nil {
+		return "", err
+	}
+
+	if len(tweets.Data) == 0 {
+		if tweets.Message != "" {
+			return tweets.Message, nil
+		}
+		return "No tweets found", nil
+	}
+
+	authors := map[string]string{}
+	for _, user := range tweets.Includes.Users {
+		authors[user.ID] = user.Username
+	}
+
+	var builder strings.Builder
+	for _, tweet := range tweets.Data {
+		// Without the author expansion, the author ID is the best there is
+		author := authors[tweet.AuthorID]
+		if author == "" {
+			author = tweet.AuthorID
+		}
+		if author == "" {
+			author = "unknown"
+		}
+		content := strings.Join(strings.Fields(tweet.Text), " ")
+
+		if format == outputFormatMarkdown {
+			fmt.Fprintf(&builder, "- **@%s**: %s%s · [%s](https://x.com/%s/status/%s)\n", author, content, formatMetrics(tweet.PublicMetrics), tweet.ID, author, tweet.ID)
+		} else {
+			fmt.Fprintf(&builder, "[%s] @%s: %s%s\n", tweet.ID, author, content, formatMetrics(tweet.PublicMetrics))
+		}
+	}
+
+	if tweets.RateLimited {
+		builder.WriteString("\nStopped by the rate limit, the tweets above are partial\n")
+	}
+	// Cursors stay visible, so pagination and incremental polling ('since_id') work in every format
+	if tweets.Meta.NextToken != "" || tweets.Meta.NewestID != "" {
+		builder.WriteString("\n")
+	}
+	if tweets.Meta.NextToken != "" {
+		fmt.Fprintf(&builder, "next_token: %s\n", tweets.Meta.NextToken)
+	}
+	if tweets.Meta.NewestID != "" {
+		fmt.Fprintf(&builder, "newest_id: %s\n", tweets.Meta.NewestID)
+	}
+	return strings.TrimRight(builder.String(), "\n"), nil
+}
+
+// formatMetrics renders the public metrics of a tweet, e.g. ' (❤ 12 🔁 3 💬 1)'. Tweets without them render nothing
+func formatMetrics(metrics *twitter.PublicMetrics) string {
+	if metrics == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (❤ %d 🔁 %d 💬 %d)", metrics.LikeCount, metrics.RetweetCount, metrics.ReplyCount)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestFormatTweets(t *testing.T) {
+	page := `{"data":[` +
+		`{"id":"1","text":"hello\nworld","author_id":"9","public_metrics":{"like_count":12,"retweet_count":3,"reply_count":1}},` +
+		`{"id":"2","text":"no metrics","author_id":"8"}],` +
+		`"includes":{"users":[{"id":"9","name":"Me","username":"me"}]},"meta":{"result_count":2,"next_token":"abc"}}`
+
+	tests := map[string]struct {
+		text     string
+		format   string
+		expected string
+	}{
+		"text": {
+			text:     page,
+			format:   outputFormatText,
+			expected: "[1] @me: hello world (❤ 12 🔁 3 💬 1)\n[2] @8: no metrics\n\nnext_token: abc",
+		},
+		"markdown": {
+			text:   page,
+			format: outputFormatMarkdown,
+			expected: "- **@me**: hello world (❤ 12 🔁 3 💬 1) · [1](https://x.com/me/status/1)\n" +
+				"- **@8**: no metrics · [2](https://x.com/8/status/2)\n\nnext_token: abc",
+		},
+		"rate limited": {
+			text:     `{"data":[{"id":"1","text":"a"}],"pages":2,"rate_limited":true}`,
+			format:   outputFormatText,
+			expected: "[1] @unknown: a\n\nStopped by the rate limit, the tweets above are partial",
+		},
+		"newest id": {
+			text:     `{"data":[{"id":"5","text":"a","author_id":"9"}],"meta":{"result_count":1,"newest_id":"5","oldest_id":"5"}}`,
+			format:   outputFormatText,
+			expected: "[5] @9: a\n\nnewest_id: 5",
+		},
+		"empty": {
+			text:     `{"meta":{"result_count":0}}`,
+			format:   outputFormatText,
+			expected: "No tweets found",
+		},
+		"message": {
+			text:     `{"result_count": 0, "message": "No quote tweets available"}`,
+			format:   outputFormatMarkdown,
+			expected: "No quote tweets available",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			formatted, err := formatTweets(test.text, test.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if formatted != test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestWithOutputFormat(t *testing.T) {
+	calls := 0
+	handler := withOutputFormat(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(`{"data":[{"id":"1","text":"a"}]}`), nil
+	})
+
+	result, _ := handler(context.Background(), newCallToolRequest(map[string]any{}))
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"data":[{"id":"1","text":"a"}]}` {
+		t.Errorf("expected JSON by default, got %s", text)
+	}
+
+	result, _ = handler(context.Background(), newCallToolRequest(map[string]any{"format": "text"}))
+	if text := result.Content[0].(mcp.TextContent).Text; text != "[1] @unknown: a" {
+		t.Errorf("expected a text list, got %s", text)
+	}
+
+	result, _ = handler(context.Background(), newCallToolRequest(map[string]any{"format": "xml"}))
+	if !result.IsError || calls != 2 {
+		t.Errorf("expected invalid formats to be rejected before calling the handler")
+	}
+}
+
+func TestOutputFormatOnlyWrapsTweetTools(t *testing.T) {
+	tm, mcpServer := newTestToolsManager(api.ToolsConfig{})
+	store, err := schedule.NewStore(filepath.Join(t.TempDir(), "schedule.yaml"), schedule.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tm.dependencies.ScheduleStore = store
+
+	// schedule_export has a 'format' argument of its own, which must reach its handler
+	result, err := mcpServer.GetTool("schedule_export").Handler(context.Background(), newCallToolRequest(map[string]any{"format": "yaml"}))
+	if err != nil || result.IsError {
+		t.Fatalf("expected a YAML export, got %v", result.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "scheduled_tweets") {
+		t.Errorf("expected the exported schedule, got %s", text)
+	}
+
+	for toolName := range formattedTools {
+		if _, declared := tm.toolDefinitions[toolName].InputSchema.Properties[formatArgument]; !declared {
+			t.Errorf("tool '%s' is formatted but does not declare the 'format' argument", toolName)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// cacheInvalidations lists the read tools whose cached responses go stale after a successful write tool.
+// It is loose on purpose: every cached response of those tools is dropped, whatever its arguments
+var cacheInvalidations = map[string][]string{
+	"post_tweet":      {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+	"post_thread":     {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+	"continue_thread": {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+	"delete_tweet":    {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+	"delete_thread":   {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+	"pin_tweet":       {"get_user_profile", "get_me_full"},
+	"like_tweet":      {"get_liking_users", "get_my_likes"},
+	"unlike_tweet":    {"get_liking_users", "get_my_likes"},
+	"retweet":         {"get_retweeters", "get_user_tweets"},
+	"undo_retweet":    {"get_retweeters", "get_user_tweets"},
+	"follow_user":     {"get_user_profile", "get_me_full", "find_mutuals", "find_inactive_following", "get_timeline"},
+	"unfollow_user":   {"get_user_profile", "get_me_full", "find_mutuals", "find_inactive_following", "get_timeline"},
+	"follow_users":    {"get_user_profile", "get_me_full", "find_mutuals", "find_inactive_following", "get_timeline"},
+	"unfollow_users":  {"get_user_profile", "get_me_full", "find_mutuals", "find_inactive_following", "get_timeline"},
+
+	"bookmark_tweet":     {"get_bookmarks"},
+	"remove_bookmark":    {"get_bookmarks"},
+	"add_list_member":    {"get_list_tweets"},
+	"remove_list_member": {"get_list_tweets"},
+	"schedule_publish":   {"get_user_tweets", "get_user_profile", "get_me_full", "get_timeline"},
+}
+
+// cachedToolResult represents a stored tool response
+type cachedToolResult struct {
+	result    *mcp.CallToolResult
+	expiresAt time.Time
+}
+
+// responseCache keeps successful responses of read tools for a configured time per tool,
+// keyed by the tool name and its arguments, so agents asking the same again do not spend quota
+type responseCache struct {
+	mutex   sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]map[string]cachedToolResult
+}
+
+func newResponseCache(ttls map[string]time.Duration) *responseCache {
+	return &responseCache{
+		ttls:    ttls,
+		entries: make(map[string]map[string]cachedToolResult),
+	}
+}
+
+// isCacheable reports whether a tool can be cached: only read tools asking the Twitter API
+func isCacheable(toolName string) bool {
+	return matchesToolSelector(toolName, CategoryPrefix+CategoryRead) &&
+		!matchesToolSelector(toolName, CategoryPrefix+CategorySchedule)
+}
+
+// Get returns the stored response of a tool for some arguments, if it did not expire
+func (c *responseCache) Get(toolName, key string) (*mcp.CallToolResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.entries[toolName][key]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries[toolName], key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// Set stores the response of a tool for some arguments. Expired entries of the tool are purged on the way
+func (c *responseCache) Set(toolName, key string, result *mcp.CallToolResult) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	entries, found := c.entries[toolName]
+	if !found {
+		entries = make(map[string]cachedToolResult)
+		c.entries[toolName] = entries
+	}
+	for storedKey, entry := range entries {
+		if now.After(entry.expiresAt) {
+			delete(entries, storedKey)
+		}
+	}
+
+	entries[key] = cachedToolResult{result: result, expiresAt: now.Add(c.ttls[toolName])}
+}
+
+// Invalidate drops every stored response of the tools made stale by a write tool
+func (c *responseCache) Invalidate(writeToolName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, toolName := range cacheInvalidations[writeToolName] {
+		delete(c.entries, toolName)
+	}
+}
+
+// withResponseCache wraps a tool handler with the response cache: read tools with a TTL are served from it,
+// and successful write tools invalidate the read tools they affect
+func (tm *ToolsManager) withResponseCache(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if _, invalidates := cacheInvalidations[toolName]; invalidates {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := handler(ctx, request)
+			if err == nil && result != nil && !result.IsError {
+				tm.responseCache.Invalidate(toolName)
+			}
+			return result, err
+		}
+	}
+
+	if ttl := tm.responseCache.ttls[toolName]; ttl <= 0 || !isCacheable(toolName) {
+		return handler
+	}
+
+	logger := tm.dependencies.AppCtx.Logger
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Maps are marshaled with sorted keys, so the same arguments always give the same key
+		keyBytes, _ := json.Marshal(getArgs(request))
+		key := string(keyBytes)
+
+		if cached, found := tm.responseCache.Get(toolName, key); found {
+			logger.Debug("tool response cache hit", "tool", toolName)
+			return cached, nil
+		}
+		logger.Debug("tool response cache miss", "tool", toolName)
+
+		result, err := handler(ctx, request)
+		if err == nil && result != nil && !result.IsError {
+			tm.responseCache.Set(toolName, key, result)
+		}
+		return result, err
+	}
+}
+
+// warnUncacheableTools warns about 'tools.cache_ttl' entries that will never be cached
+func (tm *ToolsManager) warnUncacheableTools() {
+	var toolNames []string
+	for toolName := range tm.responseCache.ttls {
+		toolNames = append(toolNames, toolName)
+	}
+	slices.Sort(toolNames)
+
+	for _, toolName := range toolNames {
+		if !isCacheable(toolName) {
+			tm.dependencies.AppCtx.Logger.Warn("tool can not be cached, only read tools calling the Twitter API can", "tool", toolName)
+		}
+	}
+}
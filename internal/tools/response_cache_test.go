@@ -0,0 +1,121 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/globals"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newCallToolRequest(args map[string]any) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	return request
+}
+
+func TestResponseCache(t *testing.T) {
+	cache := newResponseCache(map[string]time.Duration{"get_user_profile": 50 * time.Millisecond})
+
+	if _, found := cache.Get("get_user_profile", `{"username":"a"}`); found {
+		t.Fatalf("expected unknown key not to be found")
+	}
+
+	cache.Set("get_user_profile", `{"username":"a"}`, mcp.NewToolResultText("a"))
+	if _, found := cache.Get("get_user_profile", `{"username":"a"}`); !found {
+		t.Errorf("expected stored response")
+	}
+
+	cache.Invalidate("like_tweet")
+	if _, found := cache.Get("get_user_profile", `{"username":"a"}`); !found {
+		t.Errorf("expected unrelated writes to keep the response")
+	}
+
+	cache.Invalidate("follow_user")
+	if _, found := cache.Get("get_user_profile", `{"username":"a"}`); found {
+		t.Errorf("expected follow_user to invalidate profiles")
+	}
+
+	cache.Set("get_user_profile", `{"username":"a"}`, mcp.NewToolResultText("a"))
+	time.Sleep(60 * time.Millisecond)
+	if _, found := cache.Get("get_user_profile", `{"username":"a"}`); found {
+		t.Errorf("expected response to expire")
+	}
+}
+
+func TestWithResponseCache(t *testing.T) {
+	tm := NewToolsManager(ToolsManagerDependencies{
+		AppCtx: &globals.ApplicationContext{
+			Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Config: &api.Configuration{
+				Tools: api.ToolsConfig{CacheTTL: map[string]time.Duration{
+					"get_user_profile": time.Minute,
+					"post_tweet":       time.Minute,
+				}},
+			},
+		},
+	})
+
+	calls := 0
+	failing := false
+	readHandler := tm.withResponseCache("get_user_profile", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		if failing {
+			return mcp.NewToolResultError("rate limited"), nil
+		}
+		return mcp.NewToolResultText("profile"), nil
+	})
+	writeHandler := tm.withResponseCache("follow_user", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"success": true}`), nil
+	})
+
+	readHandler(context.Background(), newCallToolRequest(map[string]any{"username": "a"}))
+	readHandler(context.Background(), newCallToolRequest(map[string]any{"username": "a"}))
+	if calls != 1 {
+		t.Errorf("expected the second call to be cached, got %d calls", calls)
+	}
+
+	readHandler(context.Background(), newCallToolRequest(map[string]any{"username": "b"}))
+	if calls != 2 {
+		t.Errorf("expected other arguments not to be cached, got %d calls", calls)
+	}
+
+	writeHandler(context.Background(), newCallToolRequest(map[string]any{"username": "a"}))
+	failing = true
+	readHandler(context.Background(), newCallToolRequest(map[string]any{"username": "a"}))
+	readHandler(context.Background(), newCallToolRequest(map[string]any{"username": "a"}))
+	if calls != 4 {
+		t.Errorf("expected writes to invalidate and errors not to be cached, got %d calls", calls)
+	}
+
+	// Write tools are never cached, even when configured
+	writes := 0
+	postHandler := tm.withResponseCache("post_tweet", func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		writes++
+		return mcp.NewToolResultText("posted"), nil
+	})
+	postHandler(context.Background(), newCallToolRequest(map[string]any{"text": "hi"}))
+	postHandler(context.Background(), newCallToolRequest(map[string]any{"text": "hi"}))
+	if writes != 2 {
+		t.Errorf("expected write tools not to be cached, got %d calls", writes)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"time"
 	"twitter-mcp/api"
+	"twitter-mcp/internal/schedule"
+	"twitter-mcp/internal/twitter"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -45,12 +47,24 @@ func (tm *ToolsManager) HandleToolScheduleTweet(ctx context.Context, request mcp
 		return mcp.NewToolResultError(fmt.Sprintf("invalid scheduled_at format, use RFC3339 (e.g. 2026-02-25T10:00:00Z): %s", err.Error())), nil
 	}
 
+	// Duplicates are rejected by the store when configured, otherwise they are only warned
+	duplicateID := tm.dependencies.ScheduleStore.FindDuplicate(content, "")
+
 	tweet, err := tm.dependencies.ScheduleStore.Add(tweetType, content, scheduledAt)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	result, _ := json.Marshal(tweet)
+	response := struct {
+		*api.ScheduledTweet
+		Warning string `json:"warning,omitempty"`
+	}{ScheduledTweet: tweet}
+
+	if duplicateID != "" {
+		response.Warning = fmt.Sprintf("content duplicates scheduled tweet '%s', Twitter will reject it when published", duplicateID)
+	}
+
+	result, _ := json.Marshal(response)
 	return mcp.NewToolResultText(string(result)), nil
 }
 
@@ -94,6 +108,35 @@ func (tm *ToolsManager) HandleToolScheduleUpdate(ctx context.Context, request mc
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolScheduleRequeue handles the schedule_requeue tool
+func (tm *ToolsManager) HandleToolScheduleRequeue(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	id := getString(args, "id", "")
+
+	if id == "" {
+		return mcp.NewToolResultError("id is required"), nil
+	}
+
+	reviewed, _ := args["reviewed"].(bool)
+
+	var scheduledAt time.Time
+	if v := getString(args, "scheduled_at", ""); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid scheduled_at format, use RFC3339 (e.g. 2026-02-25T10:00:00Z): %s", err.Error())), nil
+		}
+		scheduledAt = parsed
+	}
+
+	tweet, err := tm.dependencies.ScheduleStore.Requeue(id, reviewed, scheduledAt)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, _ := json.Marshal(tweet)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolScheduleDelete handles the schedule_delete tool
 func (tm *ToolsManager) HandleToolScheduleDelete(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
@@ -121,6 +164,67 @@ func (tm *ToolsManager) HandleToolScheduleList(ctx context.Context, request mcp.
 	return mcp.NewToolResultText(string(result)), nil
 }
 
+// HandleToolScheduleGet handles the schedule_get tool
+func (tm *ToolsManager) HandleToolScheduleGet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	id := getString(args, "id", "")
+
+	if id == "" {
+		return mcp.NewToolResultError("id is required"), nil
+	}
+
+	tweet, err := tm.dependencies.ScheduleStore.GetByID(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, _ := json.Marshal(tweet)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolScheduleStatus handles the schedule_status tool
+func (tm *ToolsManager) HandleToolScheduleStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	minHours := getInt(args, "min_hours_since_last", 1)
+
+	stats := tm.dependencies.ScheduleStore.Stats(minHours)
+
+	result, _ := json.Marshal(stats)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolScheduleExport handles the schedule_export tool
+func (tm *ToolsManager) HandleToolScheduleExport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	format := getString(args, "format", schedule.FormatYAML)
+
+	exported, err := tm.dependencies.ScheduleStore.Export(format)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(exported)), nil
+}
+
+// HandleToolScheduleImport handles the schedule_import tool
+func (tm *ToolsManager) HandleToolScheduleImport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	data := getString(args, "data", "")
+	mode := getString(args, "mode", schedule.ImportModeMerge)
+
+	if data == "" {
+		return mcp.NewToolResultError("data is required"), nil
+	}
+
+	importResult, err := tm.dependencies.ScheduleStore.Import([]byte(data), mode)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, _ := json.Marshal(importResult)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
 // HandleToolScheduleGetPublishable handles the schedule_get_publishable tool
 func (tm *ToolsManager) HandleToolScheduleGetPublishable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := getArgs(request)
@@ -146,23 +250,39 @@ func (tm *ToolsManager) HandleToolSchedulePublish(ctx context.Context, request m
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	if validateOnly, _ := args["validate_only"].(bool); validateOnly {
+		result, _ := json.Marshal(validateScheduledTweet(tweet))
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
 	// Publish all content items (tweet or thread)
-	var lastTweetID string
-	for _, text := range tweet.Content {
-		posted, err := tm.dependencies.TwitterClient.PostTweet(text, lastTweetID)
-		if err != nil {
-			// Mark as failed
-			if updateErr := tm.dependencies.ScheduleStore.Update(id, func(t *api.ScheduledTweet) {
-				t.Status = api.ScheduledTweetStatusFailed
-				t.FailReason = err.Error()
-			}); updateErr != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to publish tweet and could not update status: %s", updateErr.Error())), nil
-			}
-			return mcp.NewToolResultError(fmt.Sprintf("failed to publish tweet: %s", err.Error())), nil
+	thread, err := tm.dependencies.TwitterClient.PostThread(ctx, tweet.Content, "")
+	if err != nil {
+		tm.webhook.Notify(newPublishEvent(id, "", err))
+
+		// Mark as failed
+		if updateErr := tm.dependencies.ScheduleStore.Update(id, func(t *api.ScheduledTweet) {
+			t.Status = api.ScheduledTweetStatusFailed
+			t.FailReason = err.Error()
+		}); updateErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to publish tweet and could not update status: %s", updateErr.Error())), nil
 		}
-		lastTweetID = posted.ID
+
+		// Part of the thread is already out: report the posted tweets like post_thread does,
+		// so they can be resumed or deleted, and the write budget counts them
+		if thread != nil && thread.Posted > 0 {
+			result, _ := json.Marshal(thread)
+			return mcp.NewToolResultError(string(result)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("failed to publish tweet: %s", err.Error())), nil
+	}
+	var firstTweetID string
+	if len(thread.Tweets) > 0 {
+		firstTweetID = thread.Tweets[0].ID
 	}
 
+	tm.webhook.Notify(newPublishEvent(id, firstTweetID, nil))
+
 	// Mark as published
 	now := time.Now().UTC()
 	if updateErr := tm.dependencies.ScheduleStore.Update(id, func(t *api.ScheduledTweet) {
@@ -174,3 +294,45 @@ func (tm *ToolsManager) HandleToolSchedulePublish(ctx context.Context, request m
 
 	return mcp.NewToolResultText(`{"success": true, "message": "Tweet published successfully"}`), nil
 }
+
+// scheduledTweetValidation represents the result of checking a scheduled tweet before publishing it
+type scheduledTweetValidation struct {
+	ID       string                       `json:"id"`
+	Status   api.ScheduledTweetStatus     `json:"status"`
+	Valid    bool                         `json:"valid"`
+	Segments []scheduledSegmentValidation `json:"segments"`
+}
+
+// scheduledSegmentValidation represents the check of one tweet of a scheduled thread
+type scheduledSegmentValidation struct {
+	Index          int    `json:"index"`
+	WeightedLength int    `json:"weighted_length"`
+	Valid          bool   `json:"valid"`
+	Error          string `json:"error,omitempty"`
+}
+
+// validateScheduledTweet checks every content item of a scheduled tweet, without calling the API
+func validateScheduledTweet(tweet *api.ScheduledTweet) scheduledTweetValidation {
+	validation := scheduledTweetValidation{
+		ID:       tweet.ID,
+		Status:   tweet.Status,
+		Valid:    true,
+		Segments: make([]scheduledSegmentValidation, 0, len(tweet.Content)),
+	}
+
+	for i, text := range tweet.Content {
+		segment := scheduledSegmentValidation{
+			Index:          i,
+			WeightedLength: twitter.WeightedTweetLength(text),
+			Valid:          true,
+		}
+		if err := twitter.ValidateTweetText(text); err != nil {
+			segment.Valid = false
+			segment.Error = err.Error()
+			validation.Valid = false
+		}
+		validation.Segments = append(validation.Segments, segment)
+	}
+
+	return validation
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// searchLangPattern matches the language codes accepted by the 'lang:' search operator
+var searchLangPattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// searchFilters are the search_tweets arguments that map to search operators
+type searchFilters struct {
+	lang            string
+	excludeRetweets bool
+	excludeReplies  bool
+}
+
+// getSearchFilters extracts the optional 'lang', 'exclude_retweets' and 'exclude_replies' arguments
+func getSearchFilters(args map[string]any) searchFilters {
+	filters := searchFilters{
+		lang: strings.ToLower(strings.TrimSpace(getString(args, "lang", ""))),
+	}
+	filters.excludeRetweets, _ = args["exclude_retweets"].(bool)
+	filters.excludeReplies, _ = args["exclude_replies"].(bool)
+	return filters
+}
+
+// apply composes the filters into the search query as operators, like 'lang:en -is:retweet'.
+// Queries with a top-level OR are grouped first, so operators apply to the whole query
+func (f searchFilters) apply(query string) (string, error) {
+	query = strings.TrimSpace(query)
+
+	var operators []string
+	if f.lang != "" {
+		if !searchLangPattern.MatchString(f.lang) {
+			return "", fmt.Errorf("invalid lang '%s': use a language code like 'en' or 'es'", f.lang)
+		}
+		operators = append(operators, "lang:"+f.lang)
+	}
+	if f.excludeRetweets {
+		operators = append(operators, "-is:retweet")
+	}
+	if f.excludeReplies {
+		operators = append(operators, "-is:reply")
+	}
+
+	if len(operators) == 0 {
+		return query, nil
+	}
+
+	// Operators already in the query are not repeated
+	var missing []string
+	for _, operator := range operators {
+		if !strings.Contains(" "+query+" ", " "+operator+" ") {
+			missing = append(missing, operator)
+		}
+	}
+	if len(missing) == 0 {
+		return query, nil
+	}
+
+	if strings.Contains(query, " OR ") && !isGroupedQuery(query) {
+		query = "(" + query + ")"
+	}
+	return query + " " + strings.Join(missing, " "), nil
+}
+
+// isGroupedQuery checks whether the whole query is a single parenthesized group, like '(a OR b)'
+func isGroupedQuery(query string) bool {
+	if !strings.HasPrefix(query, "(") {
+		return false
+	}
+
+	depth := 0
+	for i, r := range query {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(query)-1
+			}
+		}
+	}
+	return false
+}
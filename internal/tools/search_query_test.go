@@ -0,0 +1,57 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+)
+
+func TestSearchFiltersApply(t *testing.T) {
+	tests := []struct {
+		query       string
+		filters     searchFilters
+		expected    string
+		expectError bool
+	}{
+		{"kubernetes", searchFilters{}, "kubernetes", false},
+		{"kubernetes", searchFilters{lang: "en"}, "kubernetes lang:en", false},
+		{"kubernetes", searchFilters{lang: "en", excludeRetweets: true, excludeReplies: true}, "kubernetes lang:en -is:retweet -is:reply", false},
+		{"golang OR rust", searchFilters{excludeRetweets: true}, "(golang OR rust) -is:retweet", false},
+		{"(golang OR rust)", searchFilters{excludeRetweets: true}, "(golang OR rust) -is:retweet", false},
+		{"(golang) OR (rust)", searchFilters{lang: "en"}, "((golang) OR (rust)) lang:en", false},
+		{"golang -is:retweet", searchFilters{excludeRetweets: true, excludeReplies: true}, "golang -is:retweet -is:reply", false},
+		{"golang lang:en", searchFilters{lang: "en"}, "golang lang:en", false},
+		{"golang", searchFilters{lang: "en) OR (spam"}, "", true},
+		{"golang", searchFilters{lang: "english"}, "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.filters.apply(tt.query)
+		if (err != nil) != tt.expectError {
+			t.Errorf("apply(%q, %+v) error = %v, expectError %v", tt.query, tt.filters, err, tt.expectError)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("apply(%q, %+v) = '%s', expected '%s'", tt.query, tt.filters, result, tt.expected)
+		}
+	}
+}
+
+func TestGetSearchFilters(t *testing.T) {
+	filters := getSearchFilters(map[string]any{"lang": " EN ", "exclude_retweets": true})
+	if filters.lang != "en" || !filters.excludeRetweets || filters.excludeReplies {
+		t.Errorf("unexpected filters: %+v", filters)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HandleToolGetSpaces handles the get_spaces tool
+func (tm *ToolsManager) HandleToolGetSpaces(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	query := getString(args, "query", "")
+	state := getString(args, "state", "all")
+
+	if query == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+
+	spaces, err := tm.dependencies.TwitterClient.SearchSpaces(ctx, query, state)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(spaces)
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+// HandleToolGetSpace handles the get_space tool
+func (tm *ToolsManager) HandleToolGetSpace(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := getArgs(request)
+	spaceID := getString(args, "space_id", "")
+
+	if spaceID == "" {
+		return mcp.NewToolResultError("space_id is required"), nil
+	}
+
+	space, err := tm.dependencies.TwitterClient.GetSpace(ctx, spaceID)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	result, _ := json.Marshal(space)
+	return mcp.NewToolResultText(string(result)), nil
+}
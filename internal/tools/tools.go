@@ -15,6 +15,9 @@
 package tools
 
 import (
+	"fmt"
+	"slices"
+
 	"twitter-mcp/internal/globals"
 	"twitter-mcp/internal/middlewares"
 	"twitter-mcp/internal/schedule"
@@ -35,11 +38,36 @@ type ToolsManagerDependencies struct {
 
 type ToolsManager struct {
 	dependencies ToolsManagerDependencies
+
+	// Carried stuff
+	idempotency *idempotencyCache
+	maxResults  maxResultsLimits
+	webhook     *publishWebhook
+
+	responseCache  *responseCache
+	argumentLimits argumentLimits
+	writeBudget    *writeBudget
+	trendSnapshots *trendSnapshotStore
+
+	// toolDefinitions keeps every tool defined by AddTools, even the disabled ones, by name.
+	// registeredTools keeps the enabled ones in registration order, for list_capabilities
+	toolDefinitions map[string]mcp.Tool
+	registeredTools []mcp.Tool
 }
 
 func NewToolsManager(deps ToolsManagerDependencies) *ToolsManager {
 	return &ToolsManager{
 		dependencies: deps,
+		idempotency:  newIdempotencyCache(deps.AppCtx.Config.Tools.IdempotencyWindow),
+		maxResults:   newMaxResultsLimits(deps.AppCtx.Config.Tools),
+		webhook:      newPublishWebhook(deps.AppCtx.Config.Schedule, deps.AppCtx.Logger),
+
+		responseCache:  newResponseCache(deps.AppCtx.Config.Tools.CacheTTL),
+		argumentLimits: newArgumentLimits(deps.AppCtx.Config.Tools.Limits),
+		writeBudget:    newWriteBudget(deps.AppCtx.Config.Tools.DailyWriteBudget, deps.AppCtx.Config.ScheduleFile, deps.AppCtx.Logger),
+		trendSnapshots: newTrendSnapshotStore(deps.AppCtx.Config.ScheduleFile, deps.AppCtx.Logger),
+
+		toolDefinitions: make(map[string]mcp.Tool),
 	}
 }
 
@@ -52,7 +80,68 @@ func (tm *ToolsManager) wrapWithMiddlewares(handler server.ToolHandlerFunc) serv
 	return handler
 }
 
+// addTool keeps the tool definition and registers it in the MCP server, unless it is disabled by config
+func (tm *ToolsManager) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	tm.toolDefinitions[tool.Name] = tool
+
+	if !tm.isToolEnabled(tool.Name) {
+		tm.dependencies.AppCtx.Logger.Debug("tool disabled by config", "tool", tool.Name)
+		return
+	}
+	// The limits and the cache go inside the middlewares, so those calls are still logged and policies still apply
+	handler = tm.withResponseCache(tool.Name, handler)
+	if tm.writeBudget != nil && budgetedTools[tool.Name] {
+		handler = tm.withWriteBudget(tool.Name, handler)
+	}
+	if formattedTools[tool.Name] {
+		handler = withOutputFormat(handler)
+	}
+	if hasFieldsArgument(tool) {
+		handler = withFieldSelection(handler)
+	}
+	handler = tm.withArgumentLimits(handler)
+	tm.registeredTools = append(tm.registeredTools, tool)
+	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(handler))
+}
+
+// isToolEnabled checks a tool against the 'tools.enabled' and 'tools.disabled' config lists
+func (tm *ToolsManager) isToolEnabled(toolName string) bool {
+	config := tm.dependencies.AppCtx.Config.Tools
+
+	for _, selector := range config.Disabled {
+		if matchesToolSelector(toolName, selector) {
+			return false
+		}
+	}
+
+	if len(config.Enabled) == 0 {
+		return true
+	}
+
+	for _, selector := range config.Enabled {
+		if matchesToolSelector(toolName, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// warnUnknownToolSelectors warns about entries of the enabled/disabled lists matching nothing,
+// as they are most likely typos
+func (tm *ToolsManager) warnUnknownToolSelectors() {
+	config := tm.dependencies.AppCtx.Config.Tools
+
+	for _, selector := range slices.Concat(config.Enabled, config.Disabled) {
+		if !isValidToolSelector(selector) {
+			tm.dependencies.AppCtx.Logger.Warn("unknown tool or category in tools config", "entry", selector)
+		}
+	}
+}
+
 func (tm *ToolsManager) AddTools() {
+	tm.warnUnknownToolSelectors()
+	tm.warnUncacheableTools()
+
 	// post_tweet - Post a new tweet
 	tool := mcp.NewTool("post_tweet",
 		mcp.WithDescription("Post a new tweet to Twitter/X"),
@@ -61,38 +150,136 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("The text content of the tweet (max 280 characters)"),
 		),
 		mcp.WithString("reply_to_id",
-			mcp.Description("Optional: Tweet ID to reply to"),
+			mcp.Description("Optional: Tweet ID or URL to reply to"),
+		),
+		mcp.WithString("place_id",
+			mcp.Description("Optional: place ID to tag the tweet with a location. Use search_places to find one"),
+		),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Optional: unique key for this post. Retrying with the same key returns the original result instead of posting twice. If a call failed without knowing whether X posted, the key stays in use: check your tweets and post again with a new key"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolPostTweet))
+	tm.addTool(tool, tm.HandleToolPostTweet)
+
+	// preview_tweet - Preview a tweet without posting it
+	tool = mcp.NewTool("preview_tweet",
+		mcp.WithDescription("Check a tweet before posting it, without calling the API. Returns the weighted character count (links count as 23, CJK and emoji as 2), whether it exceeds 280, the detected links, mentions, hashtags and cashtags, and a plain rendering with shortened links"),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("The text content of the tweet"),
+		),
+		mcp.WithArray("media_paths",
+			mcp.Description("Optional: paths of media files on the server to check for upload (max 4)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolPreviewTweet)
 
 	// delete_tweet - Delete a tweet
 	tool = mcp.NewTool("delete_tweet",
 		mcp.WithDescription("Delete a tweet by its ID"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to delete"),
+			mcp.Description("The ID or URL of the tweet to delete"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolDeleteTweet)
+
+	// delete_thread - Delete a whole thread
+	tool = mcp.NewTool("delete_thread",
+		mcp.WithDescription("Delete a whole thread of the authenticated user, from the last tweet to the first. "+
+			"Failed tweets don't stop the rest, and every tweet gets its own result. Pass either tweet_ids or root_tweet_id"),
+		mcp.WithArray("tweet_ids",
+			mcp.Description(fmt.Sprintf("IDs or URLs of the thread tweets, first to last, e.g. as returned by post_thread (max %d)", twitter.MaxBatchSize)),
+		),
+		mcp.WithString("root_tweet_id",
+			mcp.Description("ID or URL of the first tweet of the thread. Your replies in its conversation are found with the recent search, so only those of the last 7 days are deleted"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolDeleteTweet))
+	tm.addTool(tool, tm.HandleToolDeleteThread)
+
+	// pin_tweet - Pin a tweet to the profile
+	tool = mcp.NewTool("pin_tweet",
+		mcp.WithDescription("Pin a tweet to the authenticated user's profile. Best-effort: it relies on a legacy endpoint which is not available on every API tier, in which case an unsupported error is returned."),
+		mcp.WithString("tweet_id",
+			mcp.Required(),
+			mcp.Description("The ID or URL of the tweet to pin"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolPinTweet)
 
 	// get_timeline - Get home timeline
 	tool = mcp.NewTool("get_timeline",
 		mcp.WithDescription("Get the authenticated user's home timeline (recent tweets from followed accounts)"),
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of tweets to return (default: 10, max: 100)"),
+			mcp.Description(tm.maxResults.description("tweets")),
+		),
+		mcp.WithArray("exclude",
+			mcp.Description("Optional: tweet types to leave out, any of ['retweets', 'replies']. Use both to get only original tweets"),
+		),
+		mcp.WithString("since_id",
+			mcp.Description("Optional: return only tweets newer than this tweet ID. Store 'meta.newest_id' from the previous call to poll incrementally"),
+		),
+		mcp.WithString("until_id",
+			mcp.Description("Optional: return only tweets older than this tweet ID"),
 		),
+		mcp.WithArray("from_usernames",
+			mcp.Description("Optional: keep only tweets from these usernames. Filtered here over the fetched tweets, so fewer than max_results may come back"),
+		),
+		mcp.WithArray("exclude_usernames",
+			mcp.Description("Optional: leave out tweets from these usernames. Filtered here over the fetched tweets, so fewer than max_results may come back"),
+		),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description(fetchAllDescription("tweets")),
+		),
+		mcp.WithNumber("max_total",
+			mcp.Description(maxTotalDescription("tweets")),
+		),
+		withFieldsArgument("tweets"),
+		withFormatArgument(),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetTimeline))
+	tm.addTool(tool, tm.HandleToolGetTimeline)
 
 	// get_mentions - Get mentions
 	tool = mcp.NewTool("get_mentions",
 		mcp.WithDescription("Get tweets that mention the authenticated user"),
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of mentions to return (default: 10, max: 100)"),
+			mcp.Description(tm.maxResults.description("mentions")),
+		),
+		mcp.WithString("since_id",
+			mcp.Description("Optional: return only mentions newer than this tweet ID. Store 'meta.newest_id' from the previous call to poll incrementally"),
+		),
+		withFieldsArgument("mentions"),
+		withFormatArgument(),
+	)
+	tm.addTool(tool, tm.HandleToolGetMentions)
+
+	// get_mentions_of - Get mentions of any account
+	tool = mcp.NewTool("get_mentions_of",
+		mcp.WithDescription("Get tweets mentioning or replying to any public account, e.g. to monitor a competitor or a public figure. "+
+			"Their own tweets are left out, and authors are included. Uses the recent search, so only the last 7 days are reachable"),
+		mcp.WithString("username",
+			mcp.Required(),
+			mcp.Description("The username of the account, or its profile URL"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(tm.maxResults.description("mentions")),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Optional: oldest date to search from, in RFC3339 format. Must be within the last 7 days (default: 24 hours ago)"),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Optional: newest date to search up to, in RFC3339 format"),
+		),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description(fetchAllDescription("mentions")),
 		),
+		mcp.WithNumber("max_total",
+			mcp.Description(maxTotalDescription("mentions")),
+		),
+		withFieldsArgument("mentions"),
+		withFormatArgument(),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetMentions))
+	tm.addTool(tool, tm.HandleToolGetMentionsOf)
 
 	// search_tweets - Search for tweets
 	tool = mcp.NewTool("search_tweets",
@@ -102,19 +289,117 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("Search query (e.g., 'kubernetes', 'from:user', '#hashtag')"),
 		),
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of tweets to return (default: 10, max: 100)"),
+			mcp.Description(tm.maxResults.description("tweets")),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Optional: oldest date to search from, in RFC3339 format. Must be within the last 7 days (default: 24 hours ago)"),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Optional: newest date to search up to, in RFC3339 format"),
+		),
+		mcp.WithString("lang",
+			mcp.Description("Optional: only tweets in this language, as a language code (e.g. 'en', 'es')"),
+		),
+		mcp.WithBoolean("exclude_retweets",
+			mcp.Description("Optional: leave retweets out (default: false)"),
+		),
+		mcp.WithBoolean("exclude_replies",
+			mcp.Description("Optional: leave replies out (default: false)"),
+		),
+		mcp.WithString("sort_order",
+			mcp.Description("Optional: 'recency' (newest first, default) or 'relevancy' (top tweets for the query)"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Optional: sort the returned tweets by 'likes', 'retweets', 'replies', 'quotes' or 'engagement' (all of them added up), highest first"),
 		),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description(fetchAllDescription("tweets")),
+		),
+		mcp.WithNumber("max_total",
+			mcp.Description(maxTotalDescription("tweets")),
+		),
+		withFieldsArgument("tweets"),
+		withFormatArgument(),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolSearchTweets))
+	tm.addTool(tool, tm.HandleToolSearchTweets)
+
+	// search_all - Search the full archive of tweets (only for accounts with elevated access)
+	if tm.dependencies.AppCtx.Config.Twitter.FullArchiveAccess {
+		tool = mcp.NewTool("search_all",
+			mcp.WithDescription("Search the full archive of tweets (beyond the last 7 days), optionally bounded by time. Requires Academic or Enterprise API access."),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("Search query (e.g., 'kubernetes', 'from:user', '#hashtag')"),
+			),
+			mcp.WithString("start_time",
+				mcp.Description("Optional: oldest date to search from, in RFC3339 format (e.g. 2020-01-01T00:00:00Z)"),
+			),
+			mcp.WithString("end_time",
+				mcp.Description("Optional: newest date to search up to, in RFC3339 format (e.g. 2021-01-01T00:00:00Z)"),
+			),
+			mcp.WithNumber("max_results",
+				mcp.Description("Maximum number of tweets to return (default: 10, max: 500)"),
+			),
+			withFieldsArgument("tweets"),
+			withFormatArgument(),
+		)
+		tm.addTool(tool, tm.HandleToolSearchAll)
+	}
 
 	// get_trends - Get trending topics
 	tool = mcp.NewTool("get_trends",
-		mcp.WithDescription("Get trending topics for a location. Use WOEID: 1=Worldwide, 23424950=Spain, 23424977=USA, 766273=Madrid"),
+		mcp.WithDescription("Get trending topics for a location, given by name, coordinates or WOEID. WOEID examples: 1=Worldwide, 23424950=Spain, 23424977=USA, 766273=Madrid"),
 		mcp.WithNumber("woeid",
 			mcp.Description("Where On Earth ID for location (default: 1 = Worldwide)"),
 		),
+		mcp.WithString("location_name",
+			mcp.Description("Location name (e.g. 'Madrid', 'United Kingdom') or alias (e.g. 'worldwide', 'usa', 'uk', 'spain', 'nyc'). Resolved to a WOEID, takes precedence over 'woeid'"),
+		),
+		mcp.WithNumber("lat",
+			mcp.Description("Latitude (-90 to 90). Together with 'long', trends are fetched for the closest location. Takes precedence over 'woeid'"),
+		),
+		mcp.WithNumber("long",
+			mcp.Description("Longitude (-180 to 180). Used together with 'lat'"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetTrends)
+
+	// get_trend_changes - Compare trends with the last time they were checked
+	tool = mcp.NewTool("get_trend_changes",
+		mcp.WithDescription("Report the trends that entered or dropped out for a location since the last call of this tool for it. The first call only stores the current trends"),
+		mcp.WithNumber("woeid",
+			mcp.Description("Where On Earth ID for location (default: 1 = Worldwide)"),
+		),
+		mcp.WithString("location_name",
+			mcp.Description("Location name or alias (e.g. 'Madrid', 'usa'). Resolved to a WOEID, takes precedence over 'woeid'"),
+		),
+		mcp.WithNumber("lat",
+			mcp.Description("Latitude (-90 to 90). Together with 'long', the closest location is used. Takes precedence over 'woeid'"),
+		),
+		mcp.WithNumber("long",
+			mcp.Description("Longitude (-180 to 180). Used together with 'lat'"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetTrendChanges)
+
+	// list_trend_locations - List the locations with trending topics
+	tool = mcp.NewTool("list_trend_locations",
+		mcp.WithDescription("List the locations that have trending topics, with their WOEIDs"),
+		mcp.WithString("query",
+			mcp.Description("Only return locations whose name or country contains this text"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolListTrendLocations)
+
+	// search_places - Search places to tag tweets with
+	tool = mcp.NewTool("search_places",
+		mcp.WithDescription("Search places by name, to get the place ID used by post_tweet to tag a tweet with a location"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Place name (e.g. 'Madrid', 'Golden Gate Park')"),
+		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetTrends))
+	tm.addTool(tool, tm.HandleToolSearchPlaces)
 
 	// search_topics - Search for content across multiple topics
 	tool = mcp.NewTool("search_topics",
@@ -127,7 +412,7 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("Maximum number of tweets per topic (default: 5, max: 20)"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolSearchTopics))
+	tm.addTool(tool, tm.HandleToolSearchTopics)
 
 	// get_topics_heat - Get heat/popularity score for topics
 	tool = mcp.NewTool("get_topics_heat",
@@ -139,136 +424,418 @@ func (tm *ToolsManager) AddTools() {
 		mcp.WithNumber("sample_size",
 			mcp.Description("Number of tweets to sample per topic for analysis (default: 20, max: 100)"),
 		),
+		mcp.WithString("scoring",
+			mcp.Description("How heat is rated: 'engagement' (default) weighs volume and average likes, retweets, replies and quotes per tweet; 'reach' weighs volume and total impressions, or total engagement when the API returns no impressions"),
+			mcp.Enum(twitter.HeatScorings...),
+		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetTopicsHeat))
+	tm.addTool(tool, tm.HandleToolGetTopicsHeat)
+
+	// get_tweet_topics - Get topic labels of the tweets matching a query
+	tool = mcp.NewTool("get_tweet_topics",
+		mcp.WithDescription("Search recent tweets (last 24h) and aggregate the topic domains Twitter classified them into (e.g. 'Brand', 'Technology'), sorted by number of tweets. Useful to learn what a query is really about."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Search query (e.g., 'kubernetes', 'from:user', '#hashtag')"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description("Number of tweets to sample (default: 50, max: 100)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetTweetTopics)
 
 	// get_me - Get authenticated user info
 	tool = mcp.NewTool("get_me",
 		mcp.WithDescription("Get information about the authenticated Twitter user"),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetMe))
+	tm.addTool(tool, tm.HandleToolGetMe)
+
+	// get_me_full - Get authenticated user full profile
+	tool = mcp.NewTool("get_me_full",
+		mcp.WithDescription("Get the full profile of the authenticated Twitter user: bio, avatar, creation date, verification and public metrics (followers, following, tweets)"),
+		withFieldsArgument("profile"),
+	)
+	tm.addTool(tool, tm.HandleToolGetMeFull)
 
 	// like_tweet - Like a tweet
 	tool = mcp.NewTool("like_tweet",
 		mcp.WithDescription("Like a tweet"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to like"),
+			mcp.Description("The ID or URL of the tweet to like"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolLikeTweet))
+	tm.addTool(tool, tm.HandleToolLikeTweet)
 
 	// unlike_tweet - Remove like from a tweet
 	tool = mcp.NewTool("unlike_tweet",
 		mcp.WithDescription("Remove like from a tweet"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to unlike"),
+			mcp.Description("The ID or URL of the tweet to unlike"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolUnlikeTweet))
+	tm.addTool(tool, tm.HandleToolUnlikeTweet)
 
 	// retweet - Retweet a tweet
 	tool = mcp.NewTool("retweet",
 		mcp.WithDescription("Retweet a tweet"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to retweet"),
+			mcp.Description("The ID or URL of the tweet to retweet"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolRetweet))
+	tm.addTool(tool, tm.HandleToolRetweet)
 
 	// undo_retweet - Remove a retweet
 	tool = mcp.NewTool("undo_retweet",
 		mcp.WithDescription("Remove a retweet"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to un-retweet"),
+			mcp.Description("The ID or URL of the tweet to un-retweet"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolUndoRetweet))
+	tm.addTool(tool, tm.HandleToolUndoRetweet)
 
 	// follow_user - Follow a user
 	tool = mcp.NewTool("follow_user",
 		mcp.WithDescription("Follow a Twitter user"),
 		mcp.WithString("username",
 			mcp.Required(),
-			mcp.Description("The username of the user to follow (without @)"),
+			mcp.Description("The username of the user to follow, or their profile URL"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolFollowUser))
+	tm.addTool(tool, tm.HandleToolFollowUser)
 
 	// unfollow_user - Unfollow a user
 	tool = mcp.NewTool("unfollow_user",
 		mcp.WithDescription("Unfollow a Twitter user"),
 		mcp.WithString("username",
 			mcp.Required(),
-			mcp.Description("The username of the user to unfollow (without @)"),
+			mcp.Description("The username of the user to unfollow, or their profile URL"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolUnfollowUser)
+
+	// follow_users - Follow several users
+	tool = mcp.NewTool("follow_users",
+		mcp.WithDescription("Follow several Twitter users at once. Returns how many succeeded and failed, with the reason of each failure"),
+		mcp.WithArray("usernames",
+			mcp.Required(),
+			mcp.Description("Usernames of the users to follow, or their profile URLs (max 50)"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolUnfollowUser))
+	tm.addTool(tool, tm.HandleToolFollowUsers)
+
+	// unfollow_users - Unfollow several users
+	tool = mcp.NewTool("unfollow_users",
+		mcp.WithDescription("Unfollow several Twitter users at once. Returns how many succeeded and failed, with the reason of each failure"),
+		mcp.WithArray("usernames",
+			mcp.Required(),
+			mcp.Description("Usernames of the users to unfollow, or their profile URLs (max 50)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolUnfollowUsers)
 
 	// get_user_profile - Get a user's profile
 	tool = mcp.NewTool("get_user_profile",
-		mcp.WithDescription("Get a Twitter user's profile information including bio, followers count, etc."),
+		mcp.WithDescription("Get a Twitter user's profile information including bio, followers count, verification (verified, verified_type) and whether the account is protected"),
 		mcp.WithString("username",
 			mcp.Required(),
-			mcp.Description("The username of the user (without @)"),
+			mcp.Description("The username of the user, or their profile URL"),
 		),
+		mcp.WithBoolean("include_engagement",
+			mcp.Description("Also fetch recent tweets to compute the engagement: average likes, retweets, replies and quotes per tweet, and that average divided by the followers (default: false). Costs an extra request"),
+		),
+		mcp.WithNumber("recent_tweets",
+			mcp.Description("Number of recent tweets the engagement is computed from (default: 20, min: 5, max: 100)"),
+		),
+		withFieldsArgument("profile"),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetUserProfile))
+	tm.addTool(tool, tm.HandleToolGetUserProfile)
+
+	// search_users - Search users by name or keyword
+	tool = mcp.NewTool("search_users",
+		mcp.WithDescription("Search Twitter accounts by name or keyword, e.g. to find the official account of a company without guessing its handle. Returns basic profiles with follower counts. Uses the legacy v1.1 users search, which may not be available on every API tier"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Name or keywords to search accounts for"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of users to return (default: 20, max: %d)", twitter.MaxUserSearchResults)),
+		),
+		withFieldsArgument("users"),
+	)
+	tm.addTool(tool, tm.HandleToolSearchUsers)
 
 	// get_user_tweets - Get a user's recent tweets
 	tool = mcp.NewTool("get_user_tweets",
 		mcp.WithDescription("Get recent tweets from a specific user"),
 		mcp.WithString("username",
 			mcp.Required(),
-			mcp.Description("The username of the user (without @)"),
+			mcp.Description("The username of the user, or their profile URL"),
 		),
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of tweets to return (default: 10, max: 100)"),
+			mcp.Description(tm.maxResults.description("tweets")),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Optional: oldest date to get tweets from, in RFC3339 format"),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Optional: newest date to get tweets up to, in RFC3339 format"),
+		),
+		mcp.WithBoolean("include_private_metrics",
+			mcp.Description("Add impressions, link and profile clicks (non_public_metrics and organic_metrics). Only for your own tweets from the last 30 days"),
+		),
+		withFieldsArgument("tweets"),
+		withFormatArgument(),
+	)
+	tm.addTool(tool, tm.HandleToolGetUserTweets)
+
+	// find_mutuals - Find followers in common
+	tool = mcp.NewTool("find_mutuals",
+		mcp.WithDescription("Find the followers of a user that the authenticated user follows too or, given 'other_username', the users following both. Follower lists are fetched up to a configured cap, and 'truncated' tells when the result may be incomplete"),
+		mcp.WithString("username",
+			mcp.Required(),
+			mcp.Description("The username of the user, or their profile URL"),
+		),
+		mcp.WithString("other_username",
+			mcp.Description("Optional: a second user. When set, returns the users following both"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolFindMutuals)
+
+	// get_tweet_metrics - Get the detailed metrics of a single tweet
+	tool = mcp.NewTool("get_tweet_metrics",
+		mcp.WithDescription("Get every available metric of a single tweet: public ones (likes, retweets, replies, quotes, impressions) and, for your own tweets from the last 30 days, organic and non-public ones (profile clicks, link clicks). Returns only the metrics."),
+		mcp.WithString("tweet_id",
+			mcp.Required(),
+			mcp.Description("The ID or URL of the tweet"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetTweetMetrics)
+
+	// find_inactive_following - Find followed accounts without recent tweets
+	tool = mcp.NewTool("find_inactive_following",
+		mcp.WithDescription("Find the accounts you follow that have not tweeted for a number of days, with the date of their last tweet. "+
+			"Checks the most recently followed accounts first, one request each, so only a bounded sample is checked per call. Useful to clean up your follows"),
+		mcp.WithNumber("inactive_days",
+			mcp.Description("Days without tweets to consider an account inactive (default: 90)"),
+		),
+		mcp.WithNumber("max_users",
+			mcp.Description(fmt.Sprintf("Maximum number of followed accounts to check (default: %d, max: %d)",
+				min(defaultActivityChecks, tm.activityChecksLimit()), tm.activityChecksLimit())),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetUserTweets))
+	tm.addTool(tool, tm.HandleToolFindInactiveFollowing)
+
+	// get_liking_users - Get users who liked a tweet
+	tool = mcp.NewTool("get_liking_users",
+		mcp.WithDescription("Get the users who liked a tweet, including their names and follower counts. Useful to analyze engagement on a specific tweet."),
+		mcp.WithString("tweet_id",
+			mcp.Required(),
+			mcp.Description("The ID or URL of the tweet"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description("Maximum number of users to return (default: 100, max: 1000)"),
+		),
+		withFieldsArgument("users"),
+	)
+	tm.addTool(tool, tm.HandleToolGetLikingUsers)
+
+	// get_retweeters - Get users who retweeted a tweet
+	tool = mcp.NewTool("get_retweeters",
+		mcp.WithDescription("Get the users who retweeted a tweet, including their names and follower counts. Useful to identify amplifiers of a post."),
+		mcp.WithString("tweet_id",
+			mcp.Required(),
+			mcp.Description("The ID or URL of the tweet"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description("Maximum number of users to return (default: 100, max: 1000)"),
+		),
+		withFieldsArgument("users"),
+	)
+	tm.addTool(tool, tm.HandleToolGetRetweeters)
+
+	// get_quote_tweets - Get tweets quoting a tweet
+	tool = mcp.NewTool("get_quote_tweets",
+		mcp.WithDescription("Get the tweets quoting a tweet, with their authors and engagement metrics. Useful to see how a post is being discussed."),
+		mcp.WithString("tweet_id",
+			mcp.Required(),
+			mcp.Description("The ID or URL of the tweet"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description("Maximum number of quote tweets to return (default: 100, max: 500)"),
+		),
+		withFieldsArgument("quote tweets"),
+		withFormatArgument(),
+	)
+	tm.addTool(tool, tm.HandleToolGetQuoteTweets)
 
 	// bookmark_tweet - Bookmark a tweet
 	tool = mcp.NewTool("bookmark_tweet",
 		mcp.WithDescription("Bookmark a tweet for later"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to bookmark"),
+			mcp.Description("The ID or URL of the tweet to bookmark"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolBookmarkTweet))
+	tm.addTool(tool, tm.HandleToolBookmarkTweet)
 
 	// remove_bookmark - Remove a bookmark
 	tool = mcp.NewTool("remove_bookmark",
 		mcp.WithDescription("Remove a bookmark from a tweet"),
 		mcp.WithString("tweet_id",
 			mcp.Required(),
-			mcp.Description("The ID of the tweet to remove from bookmarks"),
+			mcp.Description("The ID or URL of the tweet to remove from bookmarks"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolRemoveBookmark))
+	tm.addTool(tool, tm.HandleToolRemoveBookmark)
 
 	// get_bookmarks - Get bookmarked tweets
 	tool = mcp.NewTool("get_bookmarks",
 		mcp.WithDescription("Get your bookmarked tweets"),
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of bookmarks to return (default: 10, max: 100)"),
+			mcp.Description(tm.maxResults.description("bookmarks")),
+		),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description(fetchAllDescription("bookmarks")),
+		),
+		mcp.WithNumber("max_total",
+			mcp.Description(maxTotalDescription("bookmarks")),
 		),
+		withFieldsArgument("bookmarks"),
+		withFormatArgument(),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolGetBookmarks))
+	tm.addTool(tool, tm.HandleToolGetBookmarks)
+
+	// get_my_likes - Get the tweets liked by the authenticated user
+	tool = mcp.NewTool("get_my_likes",
+		mcp.WithDescription("Get the tweets you liked, most recent first, with their authors. Useful to audit your likes before undoing some with unlike_tweet"),
+		mcp.WithNumber("max_results",
+			mcp.Description(tm.maxResults.description("liked tweets")),
+		),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description(fetchAllDescription("liked tweets")),
+		),
+		mcp.WithNumber("max_total",
+			mcp.Description(maxTotalDescription("liked tweets")),
+		),
+		withFieldsArgument("liked tweets"),
+		withFormatArgument(),
+	)
+	tm.addTool(tool, tm.HandleToolGetMyLikes)
 
 	// post_thread - Post a thread of tweets
 	tool = mcp.NewTool("post_thread",
-		mcp.WithDescription("Post a thread (multiple connected tweets)"),
+		mcp.WithDescription("Post a thread (multiple connected tweets). Returns every posted tweet with its position, the tweet it replies to and its URL. "+
+			"If a tweet fails midway, the error lists the tweets already posted and 'failed_at', so the rest can be posted with continue_thread, or the thread deleted with delete_thread"),
 		mcp.WithArray("tweets",
 			mcp.Required(),
 			mcp.Description("Array of tweet texts to post as a thread (first tweet is the head)"),
 		),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Optional: unique key for this thread. Retrying with the same key returns the original result instead of posting twice. If a call failed without knowing whether X posted, the key stays in use: check your tweets and post again with a new key"),
+		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolPostThread))
+	tm.addTool(tool, tm.HandleToolPostThread)
+
+	// continue_thread - Continue an existing thread
+	tool = mcp.NewTool("continue_thread",
+		mcp.WithDescription("Post more tweets under an existing tweet, continuing its thread. Use it to resume a post_thread that failed midway, "+
+			"replying to the last posted tweet with the texts from 'failed_at' on. Same output as post_thread"),
+		mcp.WithString("reply_to_id",
+			mcp.Required(),
+			mcp.Description("ID or URL of the tweet to continue from, usually the last tweet of the thread. It must exist"),
+		),
+		mcp.WithArray("tweets",
+			mcp.Required(),
+			mcp.Description("Array of tweet texts to post, in order"),
+		),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Optional: unique key for this continuation. Retrying with the same key returns the original result instead of posting twice. If a call failed without knowing whether X posted, the key stays in use: check your tweets and post again with a new key"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolContinueThread)
+
+	// create_list - Create a list
+	tool = mcp.NewTool("create_list",
+		mcp.WithDescription("Create a new Twitter list owned by the authenticated user"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the list (max 25 characters)"),
+		),
+		mcp.WithBoolean("private",
+			mcp.Description("Whether the list is private (default: false)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolCreateList)
+
+	// add_list_member - Add a user to a list
+	tool = mcp.NewTool("add_list_member",
+		mcp.WithDescription("Add a user to a Twitter list"),
+		mcp.WithString("list_id",
+			mcp.Required(),
+			mcp.Description("The ID of the list"),
+		),
+		mcp.WithString("username",
+			mcp.Required(),
+			mcp.Description("The username of the user to add, or their profile URL"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolAddListMember)
+
+	// remove_list_member - Remove a user from a list
+	tool = mcp.NewTool("remove_list_member",
+		mcp.WithDescription("Remove a user from a Twitter list"),
+		mcp.WithString("list_id",
+			mcp.Required(),
+			mcp.Description("The ID of the list"),
+		),
+		mcp.WithString("username",
+			mcp.Required(),
+			mcp.Description("The username of the user to remove, or their profile URL"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolRemoveListMember)
+
+	// get_list_tweets - Get tweets from a list
+	tool = mcp.NewTool("get_list_tweets",
+		mcp.WithDescription("Get recent tweets posted by the members of a Twitter list"),
+		mcp.WithString("list_id",
+			mcp.Required(),
+			mcp.Description("The ID of the list"),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(tm.maxResults.description("tweets")),
+		),
+		withFieldsArgument("tweets"),
+		withFormatArgument(),
+	)
+	tm.addTool(tool, tm.HandleToolGetListTweets)
+
+	// get_spaces - Search Spaces
+	tool = mcp.NewTool("get_spaces",
+		mcp.WithDescription("Search live and scheduled Twitter Spaces (audio conversations) by title. Returns title, state, host IDs and participant count"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Text to search for in Space titles (e.g., 'kubernetes')"),
+		),
+		mcp.WithString("state",
+			mcp.Description("Filter by state: 'all', 'live' or 'scheduled' (default: all)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetSpaces)
+
+	// get_space - Get a Space
+	tool = mcp.NewTool("get_space",
+		mcp.WithDescription("Get a Twitter Space by its ID. Returns title, state (live, scheduled or ended), host IDs and participant count"),
+		mcp.WithString("space_id",
+			mcp.Required(),
+			mcp.Description("The ID of the Space"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolGetSpace)
 
 	// schedule_tweet - Schedule a tweet or thread
 	tool = mcp.NewTool("schedule_tweet",
@@ -286,7 +853,7 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("Date and time to publish, in RFC3339 format (e.g. 2026-02-25T10:00:00Z)"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolScheduleTweet))
+	tm.addTool(tool, tm.HandleToolScheduleTweet)
 
 	// schedule_update - Update a scheduled tweet
 	tool = mcp.NewTool("schedule_update",
@@ -308,7 +875,23 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("Mark as reviewed (true) or back to pending (false)"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolScheduleUpdate))
+	tm.addTool(tool, tm.HandleToolScheduleUpdate)
+
+	// schedule_requeue - Retry a failed scheduled tweet
+	tool = mcp.NewTool("schedule_requeue",
+		mcp.WithDescription("Put a failed scheduled tweet or thread back in the queue, clearing its fail_reason. Only tweets with status 'failed' can be requeued."),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("ID of the failed scheduled tweet"),
+		),
+		mcp.WithBoolean("reviewed",
+			mcp.Description("Requeue as reviewed (true), ready to publish, or as pending (false) to review it again (default: false)"),
+		),
+		mcp.WithString("scheduled_at",
+			mcp.Description("New scheduled date in RFC3339 format. Keeps the current one when empty"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolScheduleRequeue)
 
 	// schedule_delete - Delete a scheduled tweet
 	tool = mcp.NewTool("schedule_delete",
@@ -318,16 +901,57 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("ID of the scheduled tweet to delete"),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolScheduleDelete))
+	tm.addTool(tool, tm.HandleToolScheduleDelete)
 
 	// schedule_list - List scheduled tweets
 	tool = mcp.NewTool("schedule_list",
-		mcp.WithDescription("List scheduled tweets, optionally filtered by status"),
+		mcp.WithDescription("List scheduled tweets, optionally filtered by status. Failed tweets include the fail_reason of the last publishing attempt"),
 		mcp.WithString("status",
 			mcp.Description("Filter by status: 'pending', 'reviewed', 'published', 'failed'. Leave empty for all."),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolScheduleList))
+	tm.addTool(tool, tm.HandleToolScheduleList)
+
+	// schedule_get - Get a scheduled tweet
+	tool = mcp.NewTool("schedule_get",
+		mcp.WithDescription("Get a scheduled tweet or thread by ID, including its status and fail_reason when publishing failed"),
+		mcp.WithString("id",
+			mcp.Required(),
+			mcp.Description("ID of the scheduled tweet"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolScheduleGet)
+
+	// schedule_status - Summarize the scheduling queue
+	tool = mcp.NewTool("schedule_status",
+		mcp.WithDescription("Summarize the scheduling queue: tweets per status, the next pending or reviewed tweet and when it is due, the last published time, and whether the cooldown since it still blocks publishing"),
+		mcp.WithNumber("min_hours_since_last",
+			mcp.Description("Minimum hours since last published tweet (default: 1). Use 0 to ignore."),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolScheduleStatus)
+
+	// schedule_export - Export the scheduling queue
+	tool = mcp.NewTool("schedule_export",
+		mcp.WithDescription("Export every scheduled tweet, in any status, for backups or to move the queue to another instance. The output can be given to schedule_import"),
+		mcp.WithString("format",
+			mcp.Description("Output format: 'json' or 'yaml' (default: yaml, the same layout as the schedule file)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolScheduleExport)
+
+	// schedule_import - Import scheduled tweets
+	tool = mcp.NewTool("schedule_import",
+		mcp.WithDescription("Import scheduled tweets exported with schedule_export, in JSON or YAML. In merge mode, tweets whose ID is taken get a new one, returned in regenerated_ids. The min gap and duplicates checks are not applied"),
+		mcp.WithString("data",
+			mcp.Required(),
+			mcp.Description("The exported data, as returned by schedule_export"),
+		),
+		mcp.WithString("mode",
+			mcp.Description("'merge' adds the tweets to the current queue, 'replace' removes the current tweets first (default: merge)"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolScheduleImport)
 
 	// schedule_get_publishable - Get tweets ready to publish
 	tool = mcp.NewTool("schedule_get_publishable",
@@ -336,15 +960,27 @@ func (tm *ToolsManager) AddTools() {
 			mcp.Description("Minimum hours since last published tweet (default: 1). Use 0 to ignore."),
 		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolScheduleGetPublishable))
+	tm.addTool(tool, tm.HandleToolScheduleGetPublishable)
 
 	// schedule_publish - Publish a scheduled tweet
 	tool = mcp.NewTool("schedule_publish",
-		mcp.WithDescription("Publish a specific scheduled tweet or thread by ID"),
+		mcp.WithDescription("Publish a specific scheduled tweet or thread by ID. If a thread fails midway, the error lists the tweets already posted, like post_thread"),
 		mcp.WithString("id",
 			mcp.Required(),
 			mcp.Description("ID of the scheduled tweet to publish"),
 		),
+		mcp.WithBoolean("validate_only",
+			mcp.Description("Only check every tweet of it (not empty, within the length limit) and return the result per tweet, without publishing or changing its status"),
+		),
+	)
+	tm.addTool(tool, tm.HandleToolSchedulePublish)
+
+	// list_capabilities - List the available tools
+	tool = mcp.NewTool("list_capabilities",
+		mcp.WithDescription("List the tools available in this server, with their descriptions, categories and argument schemas"),
+		mcp.WithString("category",
+			mcp.Description("Only list tools of this category: read, write, engagement, schedule or admin"),
+		),
 	)
-	tm.dependencies.McpServer.AddTool(tool, tm.wrapWithMiddlewares(tm.HandleToolSchedulePublish))
+	tm.addTool(tool, tm.HandleToolListCapabilities)
 }
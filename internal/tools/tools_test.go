@@ -0,0 +1,84 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"slices"
+	"testing"
+
+	"twitter-mcp/api"
+)
+
+func TestEveryExpectedToolIsDefined(t *testing.T) {
+	// Disabled tools are not registered, but their definitions are kept
+	tm, mcpServer := newTestToolsManager(api.ToolsConfig{Disabled: []string{"category:read", "category:write"}})
+
+	if len(mcpServer.ListTools()) != 0 || len(tm.registeredTools) != 0 {
+		t.Errorf("expected no registered tools, got %d", len(tm.registeredTools))
+	}
+
+	for _, members := range ToolCategories {
+		for _, toolName := range members {
+			if _, found := tm.toolDefinitions[toolName]; !found {
+				t.Errorf("tool '%s' is in a category but is not defined", toolName)
+			}
+		}
+	}
+
+	for toolName := range tm.toolDefinitions {
+		if !isKnownTool(toolName) {
+			t.Errorf("tool '%s' is defined but belongs to no category", toolName)
+		}
+	}
+}
+
+func TestToolSchemas(t *testing.T) {
+	tm, _ := newTestToolsManager(api.ToolsConfig{})
+
+	tests := []struct {
+		tool     string
+		required []string
+		types    map[string]string
+	}{
+		{"post_tweet", []string{"text"}, map[string]string{"text": "string", "idempotency_key": "string"}},
+		{"post_thread", []string{"tweets"}, map[string]string{"tweets": "array"}},
+		{"continue_thread", []string{"reply_to_id", "tweets"}, map[string]string{"reply_to_id": "string", "tweets": "array"}},
+		{"delete_tweet", []string{"tweet_id"}, map[string]string{"tweet_id": "string"}},
+		{"get_timeline", nil, map[string]string{"max_results": "number", "format": "string", "fields": "array"}},
+		{"schedule_publish", []string{"id"}, map[string]string{"id": "string", "validate_only": "boolean"}},
+		{"list_capabilities", nil, map[string]string{"category": "string"}},
+	}
+
+	for _, test := range tests {
+		tool, found := tm.toolDefinitions[test.tool]
+		if !found {
+			t.Errorf("tool '%s' is not defined", test.tool)
+			continue
+		}
+
+		for _, argument := range test.required {
+			if !slices.Contains(tool.InputSchema.Required, argument) {
+				t.Errorf("tool '%s': expected '%s' to be required", test.tool, argument)
+			}
+		}
+
+		for argument, expectedType := range test.types {
+			property, _ := tool.InputSchema.Properties[argument].(map[string]any)
+			if property["type"] != expectedType {
+				t.Errorf("tool '%s': expected '%s' to be a %s, got %v", test.tool, argument, expectedType, property["type"])
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"twitter-mcp/internal/twitter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// trendSnapshotsFileName is the file keeping the last trends seen per location, next to the schedule file
+	trendSnapshotsFileName = "trend_snapshots.yaml"
+)
+
+// trendSnapshot represents the trend names of a location at a point in time, in rank order
+type trendSnapshot struct {
+	FetchedAt time.Time `yaml:"fetched_at"`
+	Names     []string  `yaml:"names"`
+}
+
+// trendSnapshotStore keeps the last trends get_trend_changes saw per WOEID. They are saved on every
+// change, so comparisons survive restarts
+type trendSnapshotStore struct {
+	mutex     sync.Mutex
+	filepath  string
+	snapshots map[int]trendSnapshot
+	logger    *slog.Logger
+}
+
+// newTrendSnapshotStore loads the snapshots stored in the directory of the schedule file
+func newTrendSnapshotStore(scheduleFile string, logger *slog.Logger) *trendSnapshotStore {
+	s := &trendSnapshotStore{
+		filepath:  filepath.Join(filepath.Dir(scheduleFile), trendSnapshotsFileName),
+		snapshots: make(map[int]trendSnapshot),
+		logger:    logger,
+	}
+
+	fileBytes, err := os.ReadFile(s.filepath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("failed reading trend snapshots file, starting empty", "file", s.filepath, "error", err.Error())
+		}
+		return s
+	}
+
+	if err := yaml.Unmarshal(fileBytes, &s.snapshots); err != nil {
+		logger.Warn("failed parsing trend snapshots file, starting empty", "file", s.filepath, "error", err.Error())
+		s.snapshots = make(map[int]trendSnapshot)
+	}
+	return s
+}
+
+// Swap stores the current snapshot of a location and returns the previous one, if any.
+// Snapshots older than the stored one, like cached trends, do not replace it
+func (s *trendSnapshotStore) Swap(woeid int, current trendSnapshot) (trendSnapshot, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, found := s.snapshots[woeid]
+	if found && !current.FetchedAt.After(previous.FetchedAt) {
+		return previous, true
+	}
+
+	s.snapshots[woeid] = current
+	s.save()
+	return previous, found
+}
+
+// save writes the snapshots to disk. Failures are only logged, as they are still kept in memory
+func (s *trendSnapshotStore) save() {
+	fileBytes, err := yaml.Marshal(s.snapshots)
+	if err == nil {
+		err = os.WriteFile(s.filepath, fileBytes, 0644)
+	}
+	if err != nil {
+		s.logger.Warn("failed saving trend snapshots file", "file", s.filepath, "error", err.Error())
+	}
+}
+
+// compareTrends returns the current trends not in the previous names, and the previous names no longer trending
+func compareTrends(previous []string, current []twitter.Trend) (entered []twitter.Trend, dropped []string) {
+	entered, dropped = []twitter.Trend{}, []string{}
+
+	currentNames := make([]string, 0, len(current))
+	for _, trend := range current {
+		currentNames = append(currentNames, trend.Name)
+		if !slices.Contains(previous, trend.Name) {
+			entered = append(entered, trend)
+		}
+	}
+
+	for _, name := range previous {
+		if !slices.Contains(currentNames, name) {
+			dropped = append(dropped, name)
+		}
+	}
+	return entered, dropped
+}
+
+// HandleToolGetTrendChanges handles the get_trend_changes tool
+func (tm *ToolsManager) HandleToolGetTrendChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	woeid, errResult := tm.resolveTrendsWOEID(ctx, getArgs(request))
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	current, err := tm.dependencies.TwitterClient.GetTrendsSnapshot(ctx, woeid)
+	if err != nil {
+		return tm.toolError(err), nil
+	}
+
+	names := make([]string, 0, len(current.Trends))
+	for _, trend := range current.Trends {
+		names = append(names, trend.Name)
+	}
+
+	previous, found := tm.trendSnapshots.Swap(woeid, trendSnapshot{FetchedAt: current.FetchedAt, Names: names})
+	if !found {
+		result, _ := json.Marshal(map[string]any{
+			"woeid":          woeid,
+			"first_snapshot": true,
+			"current_at":     current.FetchedAt.UTC().Format(time.RFC3339),
+			"trends_count":   len(current.Trends),
+			"message":        "No previous trends for this location yet. The current ones were stored: call again later to see what changed",
+		})
+		return mcp.NewToolResultText(string(result)), nil
+	}
+
+	entered, dropped := compareTrends(previous.Names, current.Trends)
+	result, _ := json.Marshal(map[string]any{
+		"woeid":          woeid,
+		"first_snapshot": false,
+		"previous_at":    previous.FetchedAt.UTC().Format(time.RFC3339),
+		"current_at":     current.FetchedAt.UTC().Format(time.RFC3339),
+		"new":            entered,
+		"dropped":        dropped,
+		"still_trending": len(current.Trends) - len(entered),
+	})
+	return mcp.NewToolResultText(string(result)), nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"twitter-mcp/internal/twitter"
+)
+
+func TestCompareTrends(t *testing.T) {
+	previous := []string{"#golang", "#rust", "Madrid"}
+	current := []twitter.Trend{{Name: "#golang"}, {Name: "#zig"}, {Name: "Madrid"}, {Name: "#mcp"}}
+
+	entered, dropped := compareTrends(previous, current)
+
+	var enteredNames []string
+	for _, trend := range entered {
+		enteredNames = append(enteredNames, trend.Name)
+	}
+	if !slices.Equal(enteredNames, []string{"#zig", "#mcp"}) {
+		t.Errorf("expected '#zig' and '#mcp' to be new, got %v", enteredNames)
+	}
+	if !slices.Equal(dropped, []string{"#rust"}) {
+		t.Errorf("expected '#rust' to drop out, got %v", dropped)
+	}
+}
+
+func TestTrendSnapshotStore(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	scheduleFile := filepath.Join(t.TempDir(), "schedule.yaml")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	store := newTrendSnapshotStore(scheduleFile, logger)
+	if _, found := store.Swap(1, trendSnapshot{FetchedAt: now, Names: []string{"#golang"}}); found {
+		t.Fatalf("expected no previous snapshot on the first call")
+	}
+
+	// Snapshots survive restarts
+	reloaded := newTrendSnapshotStore(scheduleFile, logger)
+	previous, found := reloaded.Swap(1, trendSnapshot{FetchedAt: now.Add(time.Minute), Names: []string{"#zig"}})
+	if !found || !previous.FetchedAt.Equal(now) || !slices.Equal(previous.Names, []string{"#golang"}) {
+		t.Fatalf("expected the stored snapshot back, got %+v (found: %v)", previous, found)
+	}
+
+	// Cached trends, not newer than the stored ones, do not replace them
+	previous, _ = reloaded.Swap(1, trendSnapshot{FetchedAt: now.Add(time.Minute), Names: []string{"#zig"}})
+	if !slices.Equal(previous.Names, []string{"#zig"}) {
+		t.Errorf("expected the latest snapshot to be kept, got %v", previous.Names)
+	}
+}
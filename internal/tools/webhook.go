@@ -0,0 +1,147 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/twitter"
+)
+
+const (
+	// defaultWebhookTimeout bounds every webhook call when not configured
+	defaultWebhookTimeout = 5 * time.Second
+
+	// webhookSignatureHeader carries the HMAC-SHA256 of the body, as 'sha256=<hex>', when a secret is set
+	webhookSignatureHeader = "X-Twitter-MCP-Signature-256"
+)
+
+// publishEvent represents the payload sent to the webhook after publishing a scheduled tweet
+type publishEvent struct {
+	Event            string                   `json:"event"`
+	ScheduledTweetID string                   `json:"scheduled_tweet_id"`
+	Status           api.ScheduledTweetStatus `json:"status"`
+	TweetID          string                   `json:"tweet_id,omitempty"`
+	URL              string                   `json:"url,omitempty"`
+	Error            string                   `json:"error,omitempty"`
+	Timestamp        time.Time                `json:"timestamp"`
+}
+
+// publishWebhook notifies an external URL about publishing results.
+// Failures are only logged, so they never fail the publish itself
+type publishWebhook struct {
+	url    string
+	secret string
+	client *http.Client
+	logger *slog.Logger
+}
+
+// newPublishWebhook returns nil when no webhook URL is configured
+func newPublishWebhook(config api.ScheduleConfig, logger *slog.Logger) *publishWebhook {
+	if config.WebhookURL == "" {
+		return nil
+	}
+
+	timeout := config.WebhookTimeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &publishWebhook{
+		url:    config.WebhookURL,
+		secret: config.WebhookSecret,
+		client: &http.Client{Timeout: timeout},
+		logger: logger,
+	}
+}
+
+// newPublishEvent builds the event of a publish attempt. A nil error means it was published as tweetID
+func newPublishEvent(scheduledTweetID, tweetID string, err error) publishEvent {
+	event := publishEvent{
+		Event:            "published",
+		ScheduledTweetID: scheduledTweetID,
+		Status:           api.ScheduledTweetStatusPublished,
+		Timestamp:        time.Now().UTC(),
+	}
+
+	if err != nil {
+		event.Event = "failed"
+		event.Status = api.ScheduledTweetStatusFailed
+		event.Error = err.Error()
+		return event
+	}
+
+	event.TweetID = tweetID
+	event.URL = twitter.TweetURL(tweetID)
+	return event
+}
+
+// Notify sends the event in the background. It does nothing on a nil webhook
+func (w *publishWebhook) Notify(event publishEvent) {
+	if w == nil {
+		return
+	}
+
+	go func() {
+		if err := w.send(event); err != nil {
+			w.logger.Warn("failed notifying publish webhook", "scheduled_tweet_id", event.ScheduledTweetID, "error", err.Error())
+		}
+	}()
+}
+
+// send posts the event as JSON, signed when a secret is configured
+func (w *publishWebhook) send(event publishEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookBody(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signWebhookBody returns the hex HMAC-SHA256 of the body with the secret
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
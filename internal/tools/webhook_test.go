@@ -0,0 +1,91 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+)
+
+func TestPublishWebhookDisabled(t *testing.T) {
+	webhook := newPublishWebhook(api.ScheduleConfig{}, slog.Default())
+	if webhook != nil {
+		t.Fatalf("expected no webhook without URL")
+	}
+
+	// Notifying a nil webhook is a no-op
+	webhook.Notify(newPublishEvent("abc", "123", nil))
+}
+
+func TestPublishWebhookSend(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhookSignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := newPublishWebhook(api.ScheduleConfig{WebhookURL: server.URL, WebhookSecret: "secret"}, slog.Default())
+
+	if err := webhook.send(newPublishEvent("abc", "123", nil)); err != nil {
+		t.Fatalf("unexpected error sending: %v", err)
+	}
+
+	var event publishEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("expected a JSON body: %v", err)
+	}
+	if event.Event != "published" || event.ScheduledTweetID != "abc" || event.TweetID != "123" || event.URL != "https://x.com/i/status/123" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if signature != "sha256="+signWebhookBody("secret", body) {
+		t.Errorf("expected body signature, got '%s'", signature)
+	}
+
+	failed := newPublishEvent("abc", "", errors.New("duplicate content"))
+	if failed.Event != "failed" || failed.Status != api.ScheduledTweetStatusFailed || failed.Error != "duplicate content" || failed.URL != "" {
+		t.Errorf("unexpected failed event: %+v", failed)
+	}
+}
+
+func TestPublishWebhookErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := newPublishWebhook(api.ScheduleConfig{WebhookURL: server.URL}, slog.Default())
+	if err := webhook.send(newPublishEvent("abc", "123", nil)); err == nil {
+		t.Errorf("expected non-2xx answers to be reported")
+	}
+
+	webhook = newPublishWebhook(api.ScheduleConfig{WebhookURL: server.URL + "/slow", WebhookTimeout: 10 * time.Millisecond}, slog.Default())
+	if err := webhook.send(newPublishEvent("abc", "123", nil)); err == nil {
+		t.Errorf("expected the timeout to be applied")
+	}
+}
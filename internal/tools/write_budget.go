@@ -0,0 +1,218 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// writeBudgetWindow is the rolling window the daily write budget is counted on
+	writeBudgetWindow = 24 * time.Hour
+
+	// writeBudgetFileName is the file keeping the counts, in the directory of the schedule file
+	writeBudgetFileName = "write_budget.yaml"
+)
+
+// budgetedTools are the write tools counted against the daily write budget: the ones posting tweets,
+// retweeting or liking, which are the actions X caps per account
+var budgetedTools = map[string]bool{
+	"post_tweet":       true,
+	"post_thread":      true,
+	"continue_thread":  true,
+	"retweet":          true,
+	"like_tweet":       true,
+	"schedule_publish": true,
+}
+
+// writeBudgetFile represents the persisted counts: one timestamp per write
+type writeBudgetFile struct {
+	Writes []time.Time `yaml:"writes"`
+}
+
+// writeBudget caps the writes done in a rolling 24h window. Counts are saved on every change,
+// so restarting the server does not reset them
+type writeBudget struct {
+	mutex    sync.Mutex
+	limit    int
+	filepath string
+	writes   []time.Time
+	logger   *slog.Logger
+}
+
+// newWriteBudget loads the counts stored in the directory of the schedule file.
+// It returns nil when the budget is disabled
+func newWriteBudget(limit int, scheduleFile string, logger *slog.Logger) *writeBudget {
+	if limit <= 0 {
+		return nil
+	}
+
+	b := &writeBudget{
+		limit:    limit,
+		filepath: filepath.Join(filepath.Dir(scheduleFile), writeBudgetFileName),
+		logger:   logger,
+	}
+
+	fileBytes, err := os.ReadFile(b.filepath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("failed reading write budget file, starting from zero", "file", b.filepath, "error", err.Error())
+		}
+		return b
+	}
+
+	var stored writeBudgetFile
+	if err := yaml.Unmarshal(fileBytes, &stored); err != nil {
+		logger.Warn("failed parsing write budget file, starting from zero", "file", b.filepath, "error", err.Error())
+		return b
+	}
+	b.writes = stored.Writes
+	return b
+}
+
+// Reserve counts n writes, failing when they do not fit in the budget. Writes that finally
+// did not happen are given back with Release
+func (b *writeBudget) Reserve(n int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now().UTC()
+	b.purge(now)
+
+	if n > b.limit {
+		return fmt.Errorf("daily write budget reached: the call needs %d writes, over the budget of %d", n, b.limit)
+	}
+	if len(b.writes)+n > b.limit {
+		// Room comes back as the oldest writes leave the window
+		nextAvailable := b.writes[len(b.writes)+n-b.limit-1].Add(writeBudgetWindow)
+		return fmt.Errorf("daily write budget reached: %d of %d writes used in the last 24h, needing %d more. Next write available at %s",
+			len(b.writes), b.limit, n, nextAvailable.Format(time.RFC3339))
+	}
+
+	for range n {
+		b.writes = append(b.writes, now)
+	}
+	b.save()
+	return nil
+}
+
+// Release gives back n reserved writes that did not happen
+func (b *writeBudget) Release(n int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	n = min(n, len(b.writes))
+	if n <= 0 {
+		return
+	}
+	b.writes = b.writes[:len(b.writes)-n]
+	b.save()
+}
+
+// purge drops the writes out of the window. Writes are kept in order, so they are at the head
+func (b *writeBudget) purge(now time.Time) {
+	expired := 0
+	for expired < len(b.writes) && now.Sub(b.writes[expired]) >= writeBudgetWindow {
+		expired++
+	}
+	b.writes = b.writes[expired:]
+}
+
+// save writes the counts to disk. Failures are only logged, as the counts are still enforced in memory
+func (b *writeBudget) save() {
+	fileBytes, err := yaml.Marshal(writeBudgetFile{Writes: b.writes})
+	if err == nil {
+		err = os.WriteFile(b.filepath, fileBytes, 0644)
+	}
+	if err != nil {
+		b.logger.Warn("failed saving write budget file", "file", b.filepath, "error", err.Error())
+	}
+}
+
+// withWriteBudget reserves the writes a tool call is going to do before running it,
+// and gives back the ones that did not happen when it fails or is answered from the idempotency cache
+func (tm *ToolsManager) withWriteBudget(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		writes := tm.plannedWrites(toolName, getArgs(request))
+		if writes == 0 {
+			return handler(ctx, request)
+		}
+
+		if err := tm.writeBudget.Reserve(writes); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		ctx, replayed := trackIdempotencyReplay(ctx)
+		result, err := handler(ctx, request)
+		if *replayed {
+			tm.writeBudget.Release(writes)
+			return result, err
+		}
+		if err != nil || result == nil || result.IsError {
+			tm.writeBudget.Release(writes - completedWrites(result))
+		}
+		return result, err
+	}
+}
+
+// plannedWrites returns how many writes a call of a budgeted tool is going to do
+func (tm *ToolsManager) plannedWrites(toolName string, args map[string]any) int {
+	switch toolName {
+	case "post_thread", "continue_thread":
+		tweets, _ := args["tweets"].([]any)
+		return len(tweets)
+	case "schedule_publish":
+		if validateOnly, _ := args["validate_only"].(bool); validateOnly {
+			return 0
+		}
+		tweet, err := tm.dependencies.ScheduleStore.GetByID(getString(args, "id", ""))
+		if err != nil {
+			return 1
+		}
+		return len(tweet.Content)
+	default:
+		return 1
+	}
+}
+
+// completedWrites returns the writes done by a failed call: only partially posted threads report them
+func completedWrites(result *mcp.CallToolResult) int {
+	if result == nil || len(result.Content) == 0 {
+		return 0
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return 0
+	}
+
+	var partial struct {
+		Posted int `json:"posted"`
+	}
+	if err := json.Unmarshal([]byte(text.Text), &partial); err != nil {
+		return 0
+	}
+	return partial.Posted
+}
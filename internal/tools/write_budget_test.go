@@ -0,0 +1,135 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"twitter-mcp/api"
+	"twitter-mcp/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWriteBudget(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	scheduleFile := filepath.Join(t.TempDir(), "schedule.yaml")
+
+	budget := newWriteBudget(3, scheduleFile, logger)
+	if err := budget.Reserve(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := budget.Reserve(2); err == nil || !strings.Contains(err.Error(), "daily write budget reached") {
+		t.Fatalf("expected the budget to be reached, got %v", err)
+	}
+	if err := budget.Reserve(4); err == nil {
+		t.Fatalf("expected calls over the whole budget to be rejected")
+	}
+
+	// Counts survive restarts
+	reloaded := newWriteBudget(3, scheduleFile, logger)
+	if len(reloaded.writes) != 2 {
+		t.Fatalf("expected 2 persisted writes, got %d", len(reloaded.writes))
+	}
+
+	reloaded.Release(1)
+	if err := reloaded.Reserve(2); err != nil {
+		t.Errorf("expected released writes to be available again, got %v", err)
+	}
+
+	if newWriteBudget(0, scheduleFile, logger) != nil {
+		t.Errorf("expected a zero budget to be disabled")
+	}
+}
+
+func TestWithWriteBudgetReleasesFailedWrites(t *testing.T) {
+	tm, _ := newTestToolsManager(api.ToolsConfig{})
+	tm.writeBudget = newWriteBudget(5, filepath.Join(t.TempDir(), "schedule.yaml"), tm.dependencies.AppCtx.Logger)
+
+	partialThread := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError(`{"complete": false, "posted": 1, "total": 3}`), nil
+	}
+	handler := tm.withWriteBudget("post_thread", partialThread)
+
+	request := newCallToolRequest(map[string]any{"tweets": []any{"a", "b", "c"}})
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tm.writeBudget.writes) != 1 {
+		t.Errorf("expected only the posted tweet to be counted, got %d", len(tm.writeBudget.writes))
+	}
+}
+
+func TestWithWriteBudgetSkipsIdempotentReplays(t *testing.T) {
+	tm, _ := newTestToolsManager(api.ToolsConfig{})
+	tm.writeBudget = newWriteBudget(5, filepath.Join(t.TempDir(), "schedule.yaml"), tm.dependencies.AppCtx.Logger)
+	tm.idempotency.Reserve("post_tweet/abc")
+	tm.idempotency.Complete("post_tweet/abc", `{"id": "1"}`)
+
+	handler := tm.withWriteBudget("post_tweet", tm.HandleToolPostTweet)
+
+	request := newCallToolRequest(map[string]any{"text": "hello", "idempotency_key": "abc"})
+	result, err := handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("expected the stored result, got %+v, %v", result, err)
+	}
+	if len(tm.writeBudget.writes) != 0 {
+		t.Errorf("expected a replayed post not to use budget, got %d writes", len(tm.writeBudget.writes))
+	}
+}
+
+func TestWithWriteBudgetCountsPartialScheduledThreads(t *testing.T) {
+	var posts atomic.Int32
+	tm := newPostingToolsManager(t, func(w http.ResponseWriter, r *http.Request) {
+		if posts.Add(1) == 2 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"title": "Forbidden"}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"id": "1", "text": "a"}}`))
+	})
+	scheduleFile := filepath.Join(t.TempDir(), "schedule.yaml")
+	tm.writeBudget = newWriteBudget(5, scheduleFile, tm.dependencies.AppCtx.Logger)
+
+	store, err := schedule.NewStore(scheduleFile, schedule.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tm.dependencies.ScheduleStore = store
+	tweet, err := store.Add(api.ScheduledTweetTypeThread, []string{"a", "b", "c"}, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := tm.withWriteBudget("schedule_publish", tm.HandleToolSchedulePublish)
+	result, err := handler(context.Background(), newCallToolRequest(map[string]any{"id": tweet.ID}))
+	if err != nil || !result.IsError {
+		t.Fatalf("expected the thread to fail, got %+v, %v", result, err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `"posted":1`) {
+		t.Errorf("expected the posted tweets to be reported, got %s", text)
+	}
+	if len(tm.writeBudget.writes) != 1 {
+		t.Errorf("expected the posted tweet to be counted, got %d writes", len(tm.writeBudget.writes))
+	}
+}
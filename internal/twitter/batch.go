@@ -0,0 +1,129 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// MaxBatchSize is the max number of items accepted by batch methods
+	MaxBatchSize = 50
+
+	// batchConcurrency is how many items of a batch are processed at the same time
+	batchConcurrency = 4
+)
+
+// BatchItemResult is the outcome of processing one item of a batch
+type BatchItemResult struct {
+	Item    string        `json:"item"`
+	Success bool          `json:"success"`
+	Error   *ErrorDetails `json:"error,omitempty"`
+}
+
+// BatchSummary is the outcome of a batch, with results in the same order as the items
+type BatchSummary struct {
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// FollowUsers follows several users by username (v2 API with OAuth 1.0a user context)
+func (c *Client) FollowUsers(ctx context.Context, sourceUserID string, usernames []string) (*BatchSummary, error) {
+	return c.runUsersBatch(ctx, usernames, func(targetUserID string) error {
+		_, err := c.FollowUser(ctx, sourceUserID, targetUserID)
+		return err
+	})
+}
+
+// UnfollowUsers unfollows several users by username (v2 API with OAuth 1.0a user context)
+func (c *Client) UnfollowUsers(ctx context.Context, sourceUserID string, usernames []string) (*BatchSummary, error) {
+	return c.runUsersBatch(ctx, usernames, func(targetUserID string) error {
+		return c.UnfollowUser(ctx, sourceUserID, targetUserID)
+	})
+}
+
+// runUsersBatch resolves every username to its ID and runs the action on it
+func (c *Client) runUsersBatch(ctx context.Context, usernames []string, action func(userID string) error) (*BatchSummary, error) {
+	if len(usernames) == 0 {
+		return nil, fmt.Errorf("at least one username is required")
+	}
+	if len(usernames) > MaxBatchSize {
+		return nil, fmt.Errorf("too many usernames: max %d per call", MaxBatchSize)
+	}
+
+	summary := runBatch(usernames, batchConcurrency, func(username string) error {
+		user, err := c.GetUserByUsername(ctx, username)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		return action(user.ID)
+	})
+	return &summary, nil
+}
+
+// runBatch runs the action for every item with bounded concurrency.
+// Once the API answers with a rate limit error, pending items are not attempted and fail with that error
+func runBatch(items []string, concurrency int, action func(item string) error) BatchSummary {
+	results := make([]BatchItemResult, len(items))
+
+	var rateLimited atomic.Pointer[ErrorDetails]
+	var waitGroup sync.WaitGroup
+	semaphore := make(chan struct{}, max(concurrency, 1))
+
+	for i, item := range items {
+		semaphore <- struct{}{}
+		waitGroup.Add(1)
+
+		go func() {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+
+			results[i] = BatchItemResult{Item: item}
+
+			if details := rateLimited.Load(); details != nil {
+				results[i].Error = details
+				return
+			}
+
+			if err := action(item); err != nil {
+				details := GetErrorDetails(err)
+				if hasStatusCode(err, http.StatusTooManyRequests) {
+					rateLimited.CompareAndSwap(nil, &details)
+				}
+				results[i].Error = &details
+				return
+			}
+			results[i].Success = true
+		}()
+	}
+	waitGroup.Wait()
+
+	summary := BatchSummary{Results: results}
+	for _, result := range results {
+		if result.Success {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
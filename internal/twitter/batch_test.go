@@ -0,0 +1,94 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunBatch(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	items := []string{"a", "b", "fail", "c", "d", "e"}
+	summary := runBatch(items, 2, func(item string) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		if item == "fail" {
+			return fmt.Errorf("boom")
+		}
+		return nil
+	})
+
+	if summary.Succeeded != 5 || summary.Failed != 1 {
+		t.Errorf("expected 5 succeeded and 1 failed, got %d and %d", summary.Succeeded, summary.Failed)
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent actions, got %d", maxRunning.Load())
+	}
+
+	for i, result := range summary.Results {
+		if result.Item != items[i] {
+			t.Errorf("expected results in items order, got '%s' at %d", result.Item, i)
+		}
+	}
+	if failed := summary.Results[2]; failed.Success || failed.Error == nil || failed.Error.Message != "boom" {
+		t.Errorf("expected failure with reason, got %+v", failed)
+	}
+}
+
+func TestRunBatchStopsOnRateLimit(t *testing.T) {
+	var attempted []string
+
+	// Sequential, so every item after the rate limited one is skipped
+	summary := runBatch([]string{"a", "b", "c"}, 1, func(item string) error {
+		attempted = append(attempted, item)
+		if item == "b" {
+			return newAPIError(429, `{"title": "Too Many Requests"}`)
+		}
+		return nil
+	})
+
+	if !slices.Equal(attempted, []string{"a", "b"}) {
+		t.Errorf("expected no attempts after the rate limit, got %v", attempted)
+	}
+	if summary.Succeeded != 1 || summary.Failed != 2 {
+		t.Errorf("expected 1 succeeded and 2 failed, got %d and %d", summary.Succeeded, summary.Failed)
+	}
+	if skipped := summary.Results[2]; skipped.Error == nil || skipped.Error.StatusCode != 429 {
+		t.Errorf("expected skipped item to carry the rate limit error, got %+v", skipped)
+	}
+}
+
+func TestRunUsersBatchValidation(t *testing.T) {
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+
+	if _, err := client.FollowUsers(context.Background(), "1", nil); err == nil {
+		t.Errorf("expected error without usernames")
+	}
+	if _, err := client.UnfollowUsers(context.Background(), "1", make([]string, MaxBatchSize+1)); err == nil {
+		t.Errorf("expected error above the batch size")
+	}
+}
@@ -16,21 +16,40 @@ package twitter
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/dghubble/oauth1"
 )
 
 const (
-	baseURLv1 = "https://api.twitter.com/1.1"
-	baseURLv2 = "https://api.twitter.com/2"
+	// defaultBaseURL is the root of the v1.1 ('/1.1') and v2 ('/2') APIs
+	defaultBaseURL = "https://api.twitter.com"
+
+	// defaultTimeout bounds the requests done with the bearer token when no timeout is set
+	defaultTimeout = 30 * time.Second
+
+	// recentSearchWindow is how far back the v2 recent search endpoint can look
+	recentSearchWindow = 7 * 24 * time.Hour
+
+	// userAgentProduct identifies this server in the User-Agent of every request
+	userAgentProduct = "twitter-mcp"
+)
+
+var (
+	// searchSortOrders are the orders accepted by the v2 recent search endpoint
+	searchSortOrders = []string{"recency", "relevancy"}
+
+	// TweetSortMetrics are the public metrics tweets can be sorted by, see SortTweetsByMetric
+	TweetSortMetrics = []string{"likes", "retweets", "replies", "quotes", "engagement"}
 )
 
 // Client represents a Twitter/X API client
@@ -40,143 +59,163 @@ type Client struct {
 	// Bearer token for v2 API (read operations)
 	bearerToken string
 	httpClient  *http.Client
+
+	// Roots of the v1.1 and v2 APIs, see WithBaseURL and WithBaseURLs
+	baseURLv1 string
+	baseURLv2 string
+
+	// User-Agent sent on every request, see SetUserAgent
+	userAgent string
+
+	// OAuth 2.0 user context for v2 API, used instead of OAuth 1.0a when enabled
+	oauth2         *oauth2Session
+	oauth2TokenURL string
+
+	// Cached list of locations with trends available, see GetAvailableTrendLocations
+	trendLocations trendLocationsCache
+
+	// Cached trends per location, see GetTrendsSnapshot
+	trends trendsCache
+
+	// Cached authenticated user, see GetMe
+	me meCache
+
+	// How requests failing with 429 or 5xx are retried, see doWithRetry
+	retry RetryPolicy
+}
+
+// meCache holds the authenticated user, which never changes for a client
+type meCache struct {
+	mutex sync.Mutex
+	user  *User
 }
 
 // NewClient creates a new Twitter client
 func NewClient(apiKey, apiKeySecret, accessToken, accessTokenSecret, bearerToken string) *Client {
-	// Setup OAuth 1.0a for v1.1 API
-	config := oauth1.NewConfig(apiKey, apiKeySecret)
-	token := oauth1.NewToken(accessToken, accessTokenSecret)
-	oauth1Client := config.Client(oauth1.NoContext, token)
+	return NewClientWithOptions(
+		WithCredentials(apiKey, apiKeySecret, accessToken, accessTokenSecret),
+		WithBearerToken(bearerToken),
+	)
+}
 
-	return &Client{
-		oauth1Client: oauth1Client,
-		bearerToken:  bearerToken,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// DefaultUserAgent returns the User-Agent used when none is configured, e.g. 'twitter-mcp/0.1.0'
+func DefaultUserAgent(version string) string {
+	if version == "" {
+		return userAgentProduct
 	}
+	return userAgentProduct + "/" + version
 }
 
-// doRequestV2OAuth1 performs an HTTP request to the Twitter v2 API using OAuth 1.0a user context
-func (c *Client) doRequestV2OAuth1(method, endpoint string, body interface{}) ([]byte, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonBody)
-	}
+// SetUserAgent changes the User-Agent sent on every request, so they can be identified in X dashboards and logs
+func (c *Client) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
 
-	req, err := http.NewRequest(method, baseURLv2+endpoint, reqBody)
+// doRequestV2OAuth1 performs an HTTP request to the Twitter v2 API using OAuth 1.0a user context.
+// When OAuth 2.0 is enabled, the request is done with the OAuth 2.0 user token instead
+func (c *Client) doRequestV2OAuth1(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	jsonBody, err := marshalRequestBody(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.oauth1Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	if c.oauth2 != nil {
+		return c.doRequestV2OAuth2(ctx, method, endpoint, jsonBody)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+	return c.doWithRetry(ctx, method, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURLv2+endpoint, newRequestBody(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
 
-	return respBody, nil
+		return doHTTPRequest(c.oauth1Client, req)
+	})
 }
 
 // doRequestV2 performs an HTTP request to the Twitter v2 API using Bearer token
-func (c *Client) doRequestV2(method, endpoint string, body interface{}) ([]byte, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
+func (c *Client) doRequestV2(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	jsonBody, err := marshalRequestBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doWithRetry(ctx, method, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURLv2+endpoint, newRequestBody(jsonBody))
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
-	}
 
-	req, err := http.NewRequest(method, baseURLv2+endpoint, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
 
-	req.Header.Set("Authorization", "Bearer "+c.bearerToken)
-	req.Header.Set("Content-Type", "application/json")
+		return doHTTPRequest(c.httpClient, req)
+	})
+}
 
-	resp, err := c.httpClient.Do(req)
+// doRequestV1 performs an HTTP request to the Twitter v1.1 API using OAuth 1.0a
+func (c *Client) doRequestV1(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	jsonBody, err := marshalRequestBody(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+	return c.doWithRetry(ctx, method, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURLv1+endpoint, newRequestBody(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
 
-	return respBody, nil
+		return doHTTPRequest(c.oauth1Client, req)
+	})
 }
 
-// doRequestV1 performs an HTTP request to the Twitter v1.1 API using OAuth 1.0a
-func (c *Client) doRequestV1(method, endpoint string, body interface{}) ([]byte, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
+// doRequestV1Form performs a form-encoded POST request to the Twitter v1.1 API
+func (c *Client) doRequestV1Form(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	return c.doWithRetry(ctx, http.MethodPost, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURLv1+endpoint, strings.NewReader(params.Encode()))
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
-	}
 
-	req, err := http.NewRequest(method, baseURLv1+endpoint, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", c.userAgent)
 
-	req.Header.Set("Content-Type", "application/json")
+		return doHTTPRequest(c.oauth1Client, req)
+	})
+}
 
-	resp, err := c.oauth1Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// marshalRequestBody encodes a request body as JSON. Nil bodies stay nil
+func marshalRequestBody(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	return respBody, nil
+	return jsonBody, nil
 }
 
-// doRequestV1Form performs a form-encoded POST request to the Twitter v1.1 API
-func (c *Client) doRequestV1Form(endpoint string, params url.Values) ([]byte, error) {
-	req, err := http.NewRequest("POST", baseURLv1+endpoint, strings.NewReader(params.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// newRequestBody returns a fresh reader over a JSON body for every attempt of a request
+func newRequestBody(jsonBody []byte) io.Reader {
+	if jsonBody == nil {
+		return nil
 	}
+	return bytes.NewReader(jsonBody)
+}
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := c.oauth1Client.Do(req)
+// doHTTPRequest sends the request and reads the response. Non-2xx responses become an *APIError
+func doHTTPRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -188,27 +227,106 @@ func (c *Client) doRequestV1Form(endpoint string, params url.Values) ([]byte, er
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, newAPIError(resp.StatusCode, string(respBody))
 	}
 
 	return respBody, nil
 }
 
+// decodeResponse parses a JSON response body. Empty bodies, e.g. of 204 responses, leave the target untouched
+// instead of failing, so callers expecting data must check it is there and return ErrEmptyResponse otherwise
+func decodeResponse(body []byte, target any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, target)
+}
+
 // PublicMetrics represents engagement metrics for a tweet
 type PublicMetrics struct {
 	LikeCount    int `json:"like_count"`
 	RetweetCount int `json:"retweet_count"`
 	ReplyCount   int `json:"reply_count"`
 	QuoteCount   int `json:"quote_count"`
+
+	// ImpressionCount is how many times the tweet was seen. The API only returns it for recent tweets
+	ImpressionCount int `json:"impression_count,omitempty"`
+}
+
+// NonPublicMetrics represents the private metrics of a tweet, only available to its author
+// for tweets from the last 30 days (v2 API with user context)
+type NonPublicMetrics struct {
+	ImpressionCount   int `json:"impression_count"`
+	URLLinkClicks     int `json:"url_link_clicks"`
+	UserProfileClicks int `json:"user_profile_clicks"`
+}
+
+// OrganicMetrics represents the metrics of a tweet outside of promoted contexts, only available to its author
+type OrganicMetrics struct {
+	ImpressionCount   int `json:"impression_count"`
+	LikeCount         int `json:"like_count"`
+	ReplyCount        int `json:"reply_count"`
+	RetweetCount      int `json:"retweet_count"`
+	URLLinkClicks     int `json:"url_link_clicks"`
+	UserProfileClicks int `json:"user_profile_clicks"`
+}
+
+// TweetTagEntity represents a hashtag or cashtag found in a tweet
+type TweetTagEntity struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+// TweetMentionEntity represents a user mention found in a tweet
+type TweetMentionEntity struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Username string `json:"username"`
+	ID       string `json:"id,omitempty"`
+}
+
+// TweetURLEntity represents a link found in a tweet
+type TweetURLEntity struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	URL         string `json:"url"`
+	ExpandedURL string `json:"expanded_url,omitempty"`
+	DisplayURL  string `json:"display_url,omitempty"`
+}
+
+// TweetEntities represents the entities parsed by Twitter from a tweet text
+type TweetEntities struct {
+	Hashtags []TweetTagEntity     `json:"hashtags,omitempty"`
+	Cashtags []TweetTagEntity     `json:"cashtags,omitempty"`
+	Mentions []TweetMentionEntity `json:"mentions,omitempty"`
+	URLs     []TweetURLEntity     `json:"urls,omitempty"`
+}
+
+// ContextAnnotationItem represents a domain or an entity of a context annotation
+type ContextAnnotationItem struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ContextAnnotation represents a topic classification made by Twitter for a tweet
+type ContextAnnotation struct {
+	Domain ContextAnnotationItem `json:"domain"`
+	Entity ContextAnnotationItem `json:"entity"`
 }
 
 // Tweet represents a tweet object
 type Tweet struct {
-	ID            string         `json:"id"`
-	Text          string         `json:"text"`
-	AuthorID      string         `json:"author_id,omitempty"`
-	CreatedAt     string         `json:"created_at,omitempty"`
-	PublicMetrics *PublicMetrics `json:"public_metrics,omitempty"`
+	ID                 string              `json:"id"`
+	Text               string              `json:"text"`
+	AuthorID           string              `json:"author_id,omitempty"`
+	CreatedAt          string              `json:"created_at,omitempty"`
+	PublicMetrics      *PublicMetrics      `json:"public_metrics,omitempty"`
+	NonPublicMetrics   *NonPublicMetrics   `json:"non_public_metrics,omitempty"`
+	OrganicMetrics     *OrganicMetrics     `json:"organic_metrics,omitempty"`
+	Entities           *TweetEntities      `json:"entities,omitempty"`
+	ContextAnnotations []ContextAnnotation `json:"context_annotations,omitempty"`
 }
 
 // User represents a Twitter user
@@ -234,6 +352,8 @@ type TweetsResponse struct {
 	} `json:"includes,omitempty"`
 	Meta struct {
 		ResultCount int    `json:"result_count"`
+		NewestID    string `json:"newest_id,omitempty"`
+		OldestID    string `json:"oldest_id,omitempty"`
 		NextToken   string `json:"next_token,omitempty"`
 	} `json:"meta,omitempty"`
 }
@@ -257,8 +377,14 @@ type TrendsResponse []struct {
 	} `json:"locations"`
 }
 
+// PostTweetOptions represents optional settings for a new tweet
+type PostTweetOptions struct {
+	// PlaceID tags the tweet with a location, see SearchPlaces
+	PlaceID string
+}
+
 // PostTweet posts a new tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) PostTweet(text string, replyToID string) (*Tweet, error) {
+func (c *Client) PostTweet(ctx context.Context, text string, replyToID string, opts PostTweetOptions) (*Tweet, error) {
 	payload := map[string]interface{}{
 		"text": text,
 	}
@@ -269,27 +395,136 @@ func (c *Client) PostTweet(text string, replyToID string) (*Tweet, error) {
 		}
 	}
 
-	body, err := c.doRequestV2OAuth1("POST", "/tweets", payload)
+	if opts.PlaceID != "" {
+		payload["geo"] = map[string]string{
+			"place_id": opts.PlaceID,
+		}
+	}
+
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/tweets", payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse tweet response: %w", err)
 	}
+	if response.Data == nil {
+		return nil, ErrEmptyResponse
+	}
 
 	return response.Data, nil
 }
 
-// DeleteTweet deletes a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) DeleteTweet(tweetID string) error {
-	_, err := c.doRequestV2OAuth1("DELETE", "/tweets/"+tweetID, nil)
+// DeleteTweet deletes a tweet (v2 API with OAuth 1.0a user context).
+// It is idempotent: a 404 means the tweet is already gone, so retries after a lost response succeed
+func (c *Client) DeleteTweet(ctx context.Context, tweetID string) error {
+	body, err := c.doRequestV2OAuth1(ctx, "DELETE", "/tweets/"+tweetID, nil)
+	if hasStatusCode(err, http.StatusNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Data *struct {
+			Deleted *bool `json:"deleted"`
+		} `json:"data"`
+	}
+	if err := decodeResponse(body, &response); err != nil {
+		return fmt.Errorf("failed to parse delete response: %w", err)
+	}
+
+	// Empty bodies (e.g. a 204) carry nothing to verify, only an explicit false is a failure
+	if response.Data != nil && response.Data.Deleted != nil && !*response.Data.Deleted {
+		return fmt.Errorf("tweet '%s' was not deleted", tweetID)
+	}
+	return nil
+}
+
+// GetTweetByID gets a single tweet (v2 API with OAuth 1.0a user context, so protected tweets of followed accounts are visible)
+func (c *Client) GetTweetByID(ctx context.Context, tweetID string) (*Tweet, error) {
+	return c.getTweetByID(ctx, tweetID, "created_at,author_id,public_metrics,entities")
+}
+
+// getTweetByID gets a single tweet with the given tweet fields
+func (c *Client) getTweetByID(ctx context.Context, tweetID string, tweetFields string) (*Tweet, error) {
+	if tweetID == "" {
+		return nil, fmt.Errorf("tweet ID is required")
+	}
+
+	body, err := c.doRequestV2OAuth1(ctx, "GET", "/tweets/"+tweetID+"?tweet.fields="+tweetFields+"&expansions=author_id", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TweetResponse
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse tweet response: %w", err)
+	}
+
+	// Deleted or unavailable tweets come back as a 200 with errors instead of data
+	if response.Data == nil {
+		return nil, fmt.Errorf("tweet '%s' not found or not available", tweetID)
+	}
+
+	return response.Data, nil
+}
+
+// PinTweet pins a tweet to the authenticated user's profile (legacy v1.1 API with OAuth 1.0a).
+// This is best-effort: the endpoint is not part of the public API and is not available on every account tier
+func (c *Client) PinTweet(ctx context.Context, tweetID string) error {
+	params := url.Values{}
+	params.Set("id", tweetID)
+
+	_, err := c.doRequestV1Form(ctx, "/account/pin_tweet.json", params)
+	if hasStatusCode(err, http.StatusForbidden, http.StatusNotFound, http.StatusGone) {
+		return fmt.Errorf("pinning tweets is not supported for this account or API tier: %w", err)
+	}
 	return err
 }
 
+// TimelineOptions represents optional filters for the timeline endpoints
+type TimelineOptions struct {
+	// Exclude is a list of tweet types to leave out: 'retweets', 'replies'
+	Exclude []string
+	// SinceID returns only tweets newer than this tweet ID
+	SinceID string
+	// UntilID returns only tweets older than this tweet ID
+	UntilID string
+	// PaginationToken asks for the page after the one that returned it as 'next_token'
+	PaginationToken string
+}
+
+// queryParams converts the options into query params ready to be appended to an endpoint
+func (o TimelineOptions) queryParams() (string, error) {
+	var params string
+
+	for _, exclude := range o.Exclude {
+		if exclude != "retweets" && exclude != "replies" {
+			return "", fmt.Errorf("invalid exclude value '%s': use 'retweets' or 'replies'", exclude)
+		}
+	}
+	if len(o.Exclude) > 0 {
+		params += "&exclude=" + strings.Join(o.Exclude, ",")
+	}
+	if o.SinceID != "" {
+		params += "&since_id=" + url.QueryEscape(o.SinceID)
+	}
+	if o.UntilID != "" {
+		params += "&until_id=" + url.QueryEscape(o.UntilID)
+	}
+	if o.PaginationToken != "" {
+		params += "&pagination_token=" + url.QueryEscape(o.PaginationToken)
+	}
+
+	return params, nil
+}
+
 // GetTimeline gets the authenticated user's home timeline (v2 API with OAuth 1.0a user context)
-func (c *Client) GetTimeline(userID string, maxResults int) (*TweetsResponse, error) {
+func (c *Client) GetTimeline(ctx context.Context, userID string, maxResults int, opts TimelineOptions) (*TweetsResponse, error) {
 	if maxResults <= 0 {
 		maxResults = 10
 	}
@@ -297,23 +532,39 @@ func (c *Client) GetTimeline(userID string, maxResults int) (*TweetsResponse, er
 		maxResults = 100
 	}
 
-	endpoint := fmt.Sprintf("/users/%s/timelines/reverse_chronological?max_results=%d&tweet.fields=created_at,author_id&expansions=author_id", userID, maxResults)
+	params, err := opts.queryParams()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/users/%s/timelines/reverse_chronological?max_results=%d&tweet.fields=created_at,author_id,entities,context_annotations&expansions=author_id", userID, maxResults) + params
 
-	body, err := c.doRequestV2OAuth1("GET", endpoint, nil)
+	body, err := c.doRequestV2OAuth1(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse timeline response: %w", err)
 	}
 
 	return &response, nil
 }
 
-// GetMentions gets mentions of the authenticated user (v2 API with OAuth 1.0a user context)
-func (c *Client) GetMentions(userID string, maxResults int) (*TweetsResponse, error) {
+// GetTimelineAll gets up to maxTotal tweets of the home timeline, following pagination tokens.
+// maxTotal is capped to MaxPaginatedTweets
+func (c *Client) GetTimelineAll(ctx context.Context, userID string, maxTotal int, opts TimelineOptions) (*PaginatedTweets, error) {
+	return collectTweetPages(maxTotal, 1, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		pageOpts := opts
+		pageOpts.PaginationToken = paginationToken
+		return c.GetTimeline(ctx, userID, pageSize, pageOpts)
+	})
+}
+
+// GetMentions gets mentions of the authenticated user (v2 API with OAuth 1.0a user context).
+// When sinceID is set, only mentions newer than that tweet ID are returned
+func (c *Client) GetMentions(ctx context.Context, userID string, maxResults int, sinceID string) (*TweetsResponse, error) {
 	if maxResults <= 0 {
 		maxResults = 10
 	}
@@ -321,23 +572,74 @@ func (c *Client) GetMentions(userID string, maxResults int) (*TweetsResponse, er
 		maxResults = 100
 	}
 
-	endpoint := fmt.Sprintf("/users/%s/mentions?max_results=%d&tweet.fields=created_at,author_id&expansions=author_id", userID, maxResults)
+	endpoint := fmt.Sprintf("/users/%s/mentions?max_results=%d&tweet.fields=created_at,author_id,entities&expansions=author_id", userID, maxResults)
+	if sinceID != "" {
+		endpoint += "&since_id=" + url.QueryEscape(sinceID)
+	}
 
-	body, err := c.doRequestV2OAuth1("GET", endpoint, nil)
+	body, err := c.doRequestV2OAuth1(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse mentions response: %w", err)
 	}
 
 	return &response, nil
 }
 
+// mentionsOfQuery builds the recent search query for tweets mentioning or replying to a user, leaving out their own tweets
+func mentionsOfQuery(username string) string {
+	return fmt.Sprintf("(@%s OR to:%s) -from:%s", username, username, username)
+}
+
+// GetMentionsOf gets tweets mentioning any public account, through the recent search (v2 API).
+// Like SearchTweetsInRange, it only reaches the last 7 days and defaults to the last 24 hours
+func (c *Client) GetMentionsOf(ctx context.Context, username string, startTime, endTime time.Time, maxResults int) (*TweetsResponse, error) {
+	return c.SearchTweetsInRange(ctx, mentionsOfQuery(username), startTime, endTime, maxResults, "")
+}
+
+// GetMentionsOfAll is GetMentionsOf following pagination until maxTotal tweets are collected
+func (c *Client) GetMentionsOfAll(ctx context.Context, username string, startTime, endTime time.Time, maxTotal int) (*PaginatedTweets, error) {
+	return c.SearchTweetsInRangeAll(ctx, mentionsOfQuery(username), startTime, endTime, maxTotal, "")
+}
+
 // SearchTweets searches for tweets from the last 24 hours (v2 API)
-func (c *Client) SearchTweets(query string, maxResults int) (*TweetsResponse, error) {
+func (c *Client) SearchTweets(ctx context.Context, query string, maxResults int) (*TweetsResponse, error) {
+	return c.SearchTweetsInRange(ctx, query, time.Time{}, time.Time{}, maxResults, "")
+}
+
+// SearchTweetsInRange searches for recent tweets bounded by time (v2 API).
+// Start time defaults to the last 24 hours and must be within the 7-day recent search window.
+// Zero end time is ignored. Sort order is 'recency' (default) or 'relevancy'
+func (c *Client) SearchTweetsInRange(ctx context.Context, query string, startTime, endTime time.Time, maxResults int, sortOrder string) (*TweetsResponse, error) {
+	return c.searchRecent(ctx, query, startTime, endTime, maxResults, sortOrder, "")
+}
+
+// SearchTweetsInRangeAll is SearchTweetsInRange collecting up to maxTotal tweets, following pagination tokens.
+// maxTotal is capped to MaxPaginatedTweets
+func (c *Client) SearchTweetsInRangeAll(ctx context.Context, query string, startTime, endTime time.Time, maxTotal int, sortOrder string) (*PaginatedTweets, error) {
+	// Fix the default start time, so every page asks for the same window
+	if startTime.IsZero() {
+		startTime = time.Now().UTC().Add(-24 * time.Hour)
+	}
+
+	return collectTweetPages(maxTotal, 10, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		return c.searchRecent(ctx, query, startTime, endTime, pageSize, sortOrder, paginationToken)
+	})
+}
+
+// searchRecent gets a page of the recent search. An empty next token asks for the first page
+func (c *Client) searchRecent(ctx context.Context, query string, startTime, endTime time.Time, maxResults int, sortOrder, nextToken string) (*TweetsResponse, error) {
+	if sortOrder == "" {
+		sortOrder = "recency"
+	}
+	if !slices.Contains(searchSortOrders, sortOrder) {
+		return nil, fmt.Errorf("invalid sort_order '%s': must be one of %v", sortOrder, searchSortOrders)
+	}
+
 	if maxResults <= 0 {
 		maxResults = 10
 	}
@@ -345,41 +647,87 @@ func (c *Client) SearchTweets(query string, maxResults int) (*TweetsResponse, er
 		maxResults = 100
 	}
 
-	// Only search tweets from the last 24 hours
-	startTime := time.Now().UTC().Add(-24 * time.Hour).Format(time.RFC3339)
+	now := time.Now().UTC()
+
+	// Only search tweets from the last 24 hours by default
+	if startTime.IsZero() {
+		startTime = now.Add(-24 * time.Hour)
+	}
+	if startTime.Before(now.Add(-recentSearchWindow)) {
+		return nil, fmt.Errorf("start_time must be within the last 7 days for recent search")
+	}
+	if !endTime.IsZero() && endTime.After(now) {
+		return nil, fmt.Errorf("end_time must not be in the future")
+	}
 
 	encodedQuery := url.QueryEscape(query)
-	endpoint := fmt.Sprintf("/tweets/search/recent?query=%s&max_results=%d&tweet.fields=created_at,author_id,public_metrics&expansions=author_id&sort_order=recency&start_time=%s", encodedQuery, maxResults, startTime)
+	endpoint := fmt.Sprintf("/tweets/search/recent?query=%s&max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities,context_annotations&expansions=author_id&sort_order=%s&start_time=%s", encodedQuery, maxResults, sortOrder, startTime.UTC().Format(time.RFC3339))
+	if !endTime.IsZero() {
+		endpoint += "&end_time=" + endTime.UTC().Format(time.RFC3339)
+	}
+	if nextToken != "" {
+		endpoint += "&next_token=" + url.QueryEscape(nextToken)
+	}
 
-	body, err := c.doRequestV2("GET", endpoint, nil)
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse search response: %w", err)
 	}
 
 	return &response, nil
 }
 
-// GetTrends gets trending topics for a location (v1.1 API)
-// WOEID: 1 = Worldwide, 23424950 = Spain, 766273 = Madrid
-func (c *Client) GetTrends(woeid int) ([]Trend, error) {
-	if woeid <= 0 {
-		woeid = 1 // Worldwide
+// SearchTweetsAll searches the full archive of tweets, optionally bounded by time (v2 API).
+// Zero times are ignored. It requires Academic/Enterprise access
+func (c *Client) SearchTweetsAll(ctx context.Context, query string, startTime, endTime time.Time, maxResults int) (*TweetsResponse, error) {
+	if maxResults < 10 {
+		maxResults = 10
+	}
+	if maxResults > 500 {
+		maxResults = 500
+	}
+
+	encodedQuery := url.QueryEscape(query)
+	endpoint := fmt.Sprintf("/tweets/search/all?query=%s&max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities,context_annotations&expansions=author_id", encodedQuery, maxResults)
+	if !startTime.IsZero() {
+		endpoint += "&start_time=" + startTime.UTC().Format(time.RFC3339)
+	}
+	if !endTime.IsZero() {
+		endpoint += "&end_time=" + endTime.UTC().Format(time.RFC3339)
+	}
+
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
+	if hasStatusCode(err, http.StatusForbidden) {
+		return nil, fmt.Errorf("not authorized for full-archive search: it requires Academic or Enterprise access: %w", err)
+	}
+	if err != nil {
+		return nil, err
 	}
 
+	var response TweetsResponse
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// fetchTrends gets trending topics for a location, skipping the cache (v1.1 API)
+func (c *Client) fetchTrends(ctx context.Context, woeid int) ([]Trend, error) {
 	endpoint := fmt.Sprintf("/trends/place.json?id=%d", woeid)
 
-	body, err := c.doRequestV1("GET", endpoint, nil)
+	body, err := c.doRequestV1(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TrendsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse trends response: %w", err)
 	}
 
@@ -390,18 +738,26 @@ func (c *Client) GetTrends(woeid int) ([]Trend, error) {
 	return []Trend{}, nil
 }
 
+// TopicResult holds the outcome of searching a single topic.
+// Exactly one of Tweets or Err is set
+type TopicResult struct {
+	Tweets *TweetsResponse
+	Err    error
+}
+
 // GetTrendsByTopic searches tweets and returns them filtered by topics
 // This is a workaround since Twitter API doesn't have topic-based trends directly
-func (c *Client) GetTrendsByTopic(topics []string, maxResults int) (map[string]*TweetsResponse, error) {
-	results := make(map[string]*TweetsResponse)
+func (c *Client) GetTrendsByTopic(ctx context.Context, topics []string, maxResults int) (map[string]TopicResult, error) {
+	results := make(map[string]TopicResult)
 
 	for _, topic := range topics {
-		tweets, err := c.SearchTweets(topic, maxResults)
+		tweets, err := c.SearchTweets(ctx, topic, maxResults)
 		if err != nil {
-			// Continue with other topics even if one fails
+			// Keep going with other topics, but let the caller know this one failed
+			results[topic] = TopicResult{Err: err}
 			continue
 		}
-		results[topic] = tweets
+		results[topic] = TopicResult{Tweets: tweets}
 	}
 
 	return results, nil
@@ -417,19 +773,49 @@ type TopicHeat struct {
 	TotalQuotes   int     `json:"total_quotes"`
 	AvgEngagement float64 `json:"avg_engagement"`
 	HeatScore     float64 `json:"heat_score"` // 0-100 calculated score
+
+	// Scoring is the formula used for HeatScore, see HeatScorings
+	Scoring string `json:"scoring"`
+
+	// TotalImpressions and ReachSource are only set by the 'reach' scoring. ReachSource tells whether
+	// the reach came from 'impressions' or, when the API returned none, from total 'engagement'
+	TotalImpressions int    `json:"total_impressions,omitempty"`
+	ReachSource      string `json:"reach_source,omitempty"`
 }
 
-// GetTopicsHeat searches topics and calculates a heat score for each
-func (c *Client) GetTopicsHeat(topics []string, maxResults int) ([]TopicHeat, error) {
+const (
+	// HeatScoringEngagement rates topics by sampled volume (up to 40 points) and average engagement
+	// per tweet on a log scale (up to 60 points)
+	HeatScoringEngagement = "engagement"
+
+	// HeatScoringReach rates topics by sampled volume (up to 30 points) and total reach on a log scale
+	// (up to 70 points, at 100k). Reach is the sum of impressions or, without them, of engagement
+	HeatScoringReach = "reach"
+)
+
+// HeatScorings are the formulas GetTopicsHeat can rate topics with
+var HeatScorings = []string{HeatScoringEngagement, HeatScoringReach}
+
+// GetTopicsHeat searches topics and calculates a heat score for each, with one of HeatScorings.
+// Empty scoring means 'engagement'
+func (c *Client) GetTopicsHeat(ctx context.Context, topics []string, maxResults int, scoring string) ([]TopicHeat, error) {
+	if scoring == "" {
+		scoring = HeatScoringEngagement
+	}
+	if !slices.Contains(HeatScorings, scoring) {
+		return nil, fmt.Errorf("invalid scoring '%s': must be one of %s", scoring, strings.Join(HeatScorings, ", "))
+	}
+
 	var results []TopicHeat
 
 	for _, topic := range topics {
-		tweets, err := c.SearchTweets(topic, maxResults)
+		tweets, err := c.SearchTweets(ctx, topic, maxResults)
 		if err != nil {
 			// Add topic with zero heat if search fails
 			results = append(results, TopicHeat{
 				Topic:     topic,
 				HeatScore: 0,
+				Scoring:   scoring,
 			})
 			continue
 		}
@@ -437,6 +823,7 @@ func (c *Client) GetTopicsHeat(topics []string, maxResults int) ([]TopicHeat, er
 		heat := TopicHeat{
 			Topic:      topic,
 			TweetCount: len(tweets.Data),
+			Scoring:    scoring,
 		}
 
 		// Sum up all metrics
@@ -446,6 +833,7 @@ func (c *Client) GetTopicsHeat(topics []string, maxResults int) ([]TopicHeat, er
 				heat.TotalRetweets += tweet.PublicMetrics.RetweetCount
 				heat.TotalReplies += tweet.PublicMetrics.ReplyCount
 				heat.TotalQuotes += tweet.PublicMetrics.QuoteCount
+				heat.TotalImpressions += tweet.PublicMetrics.ImpressionCount
 			}
 		}
 
@@ -455,27 +843,13 @@ func (c *Client) GetTopicsHeat(topics []string, maxResults int) ([]TopicHeat, er
 			heat.AvgEngagement = float64(totalEngagement) / float64(heat.TweetCount)
 		}
 
-		// Calculate heat score (0-100)
-		// Formula: combines tweet count and engagement
-		// - Tweet count contributes up to 40 points (maxed at 100 tweets)
-		// - Avg engagement contributes up to 60 points (logarithmic scale)
-		tweetScore := float64(heat.TweetCount) / float64(maxResults) * 40
-		if tweetScore > 40 {
-			tweetScore = 40
-		}
-
-		// Logarithmic scale for engagement (1 engagement = ~10 points, 100 = ~40 points, 1000 = ~60 points)
-		engagementScore := 0.0
-		if heat.AvgEngagement > 0 {
-			import_math := heat.AvgEngagement + 1 // avoid log(0)
-			engagementScore = 20 * (1 + logBase10(import_math))
-			if engagementScore > 60 {
-				engagementScore = 60
-			}
+		if scoring == HeatScoringReach {
+			heat.HeatScore = reachHeatScore(&heat, maxResults)
+		} else {
+			heat.HeatScore = engagementHeatScore(heat, maxResults)
+			heat.TotalImpressions = 0
 		}
 
-		heat.HeatScore = tweetScore + engagementScore
-
 		results = append(results, heat)
 	}
 
@@ -485,6 +859,54 @@ func (c *Client) GetTopicsHeat(topics []string, maxResults int) ([]TopicHeat, er
 	return results, nil
 }
 
+// engagementHeatScore rates a topic from 0 to 100 with the 'engagement' scoring
+func engagementHeatScore(heat TopicHeat, maxResults int) float64 {
+	// Calculate heat score (0-100)
+	// Formula: combines tweet count and engagement
+	// - Tweet count contributes up to 40 points (maxed at 100 tweets)
+	// - Avg engagement contributes up to 60 points (logarithmic scale)
+	tweetScore := float64(heat.TweetCount) / float64(maxResults) * 40
+	if tweetScore > 40 {
+		tweetScore = 40
+	}
+
+	// Logarithmic scale for engagement (1 engagement = ~10 points, 100 = ~40 points, 1000 = ~60 points)
+	engagementScore := 0.0
+	if heat.AvgEngagement > 0 {
+		import_math := heat.AvgEngagement + 1 // avoid log(0)
+		engagementScore = 20 * (1 + logBase10(import_math))
+		if engagementScore > 60 {
+			engagementScore = 60
+		}
+	}
+
+	return tweetScore + engagementScore
+}
+
+// reachHeatScore rates a topic from 0 to 100 with the 'reach' scoring, setting where its reach came from
+func reachHeatScore(heat *TopicHeat, maxResults int) float64 {
+	// Volume weighs less than in the 'engagement' scoring, as reach already grows with it
+	tweetScore := float64(heat.TweetCount) / float64(maxResults) * 30
+	if tweetScore > 30 {
+		tweetScore = 30
+	}
+
+	reach := heat.TotalImpressions
+	heat.ReachSource = "impressions"
+	if reach == 0 {
+		reach = heat.TotalLikes + heat.TotalRetweets + heat.TotalReplies + heat.TotalQuotes
+		heat.ReachSource = "engagement"
+	}
+
+	// Logarithmic scale: 10 = 14 points, 1000 = 42 points, 100k or more = 70 points
+	reachScore := 14 * logBase10(float64(reach)+1)
+	if reachScore > 70 {
+		reachScore = 70
+	}
+
+	return tweetScore + reachScore
+}
+
 // logBase10 calculates log base 10
 func logBase10(x float64) float64 {
 	if x <= 0 {
@@ -515,9 +937,132 @@ func sortTopicsByHeat(topics []TopicHeat) {
 	}
 }
 
-// GetMe gets the authenticated user's info (v2 API with OAuth 1.0a user context)
-func (c *Client) GetMe() (*User, error) {
-	body, err := c.doRequestV2OAuth1("GET", "/users/me", nil)
+// SortTweetsByMetric sorts tweets by a public metric, highest first. Metric is one of TweetSortMetrics,
+// where 'engagement' adds them all up. Tweets without metrics go last, and ties keep their order
+func SortTweetsByMetric(tweets []Tweet, metric string) error {
+	if !slices.Contains(TweetSortMetrics, metric) {
+		return fmt.Errorf("invalid sort_by '%s': must be one of %v", metric, TweetSortMetrics)
+	}
+
+	value := func(tweet Tweet) int {
+		m := tweet.PublicMetrics
+		if m == nil {
+			return -1
+		}
+		switch metric {
+		case "likes":
+			return m.LikeCount
+		case "retweets":
+			return m.RetweetCount
+		case "replies":
+			return m.ReplyCount
+		case "quotes":
+			return m.QuoteCount
+		default:
+			return m.LikeCount + m.RetweetCount + m.ReplyCount + m.QuoteCount
+		}
+	}
+
+	slices.SortStableFunc(tweets, func(a, b Tweet) int {
+		return value(b) - value(a)
+	})
+	return nil
+}
+
+// FilterTweetsByAuthor keeps the tweets written by any of the from usernames, when given, and none of the excluded ones.
+// Authors are matched case-insensitively through the included users, so tweets of authors not included only pass
+// when there are no from usernames. Meta.ResultCount is updated to the tweets kept
+func FilterTweetsByAuthor(response *TweetsResponse, from, exclude []string) {
+	if len(from) == 0 && len(exclude) == 0 {
+		return
+	}
+
+	authors := make(map[string]string)
+	for _, user := range response.Includes.Users {
+		authors[user.ID] = strings.ToLower(user.Username)
+	}
+	matches := func(usernames []string, author string) bool {
+		return slices.ContainsFunc(usernames, func(username string) bool {
+			return strings.ToLower(username) == author
+		})
+	}
+
+	kept := make([]Tweet, 0, len(response.Data))
+	for _, tweet := range response.Data {
+		author, known := authors[tweet.AuthorID]
+		if len(from) > 0 && (!known || !matches(from, author)) {
+			continue
+		}
+		if known && matches(exclude, author) {
+			continue
+		}
+		kept = append(kept, tweet)
+	}
+
+	response.Data = kept
+	response.Meta.ResultCount = len(kept)
+}
+
+// TweetTopic represents how many tweets were classified by Twitter under the same domain
+type TweetTopic struct {
+	Domain     string   `json:"domain"`
+	TweetCount int      `json:"tweet_count"`
+	Entities   []string `json:"entities,omitempty"`
+}
+
+// GetTweetTopics aggregates the context annotation domains across a set of tweets,
+// sorted by the number of tweets in each domain. Tweets lacking annotations are skipped
+func GetTweetTopics(tweets []Tweet) []TweetTopic {
+	var topics []TweetTopic
+	topicIndex := make(map[string]int)
+
+	for _, tweet := range tweets {
+		seenDomains := make(map[string]bool)
+
+		for _, annotation := range tweet.ContextAnnotations {
+			domain := annotation.Domain.Name
+			if domain == "" {
+				continue
+			}
+
+			index, found := topicIndex[domain]
+			if !found {
+				index = len(topics)
+				topicIndex[domain] = index
+				topics = append(topics, TweetTopic{Domain: domain})
+			}
+
+			// Count each tweet once per domain
+			if !seenDomains[domain] {
+				seenDomains[domain] = true
+				topics[index].TweetCount++
+			}
+
+			if annotation.Entity.Name != "" && !slices.Contains(topics[index].Entities, annotation.Entity.Name) {
+				topics[index].Entities = append(topics[index].Entities, annotation.Entity.Name)
+			}
+		}
+	}
+
+	sort.SliceStable(topics, func(i, j int) bool {
+		return topics[i].TweetCount > topics[j].TweetCount
+	})
+
+	return topics
+}
+
+// GetMe gets the authenticated user's info (v2 API with OAuth 1.0a user context).
+// The user is fetched once and cached for the lifetime of the client
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	c.me.mutex.Lock()
+	defer c.me.mutex.Unlock()
+
+	if c.me.user != nil {
+		user := *c.me.user
+		return &user, nil
+	}
+
+	body, err := c.doRequestV2OAuth1(ctx, "GET", "/users/me", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -525,66 +1070,164 @@ func (c *Client) GetMe() (*User, error) {
 	var response struct {
 		Data User `json:"data"`
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse user response: %w", err)
 	}
+	if response.Data.ID == "" {
+		return nil, ErrEmptyResponse
+	}
 
-	return &response.Data, nil
+	c.me.user = &response.Data
+	user := response.Data
+	return &user, nil
 }
 
 // LikeTweet likes a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) LikeTweet(userID, tweetID string) error {
+func (c *Client) LikeTweet(ctx context.Context, userID, tweetID string) (*LikeResult, error) {
 	payload := map[string]string{
 		"tweet_id": tweetID,
 	}
 
-	_, err := c.doRequestV2OAuth1("POST", "/users/"+userID+"/likes", payload)
-	return err
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/users/"+userID+"/likes", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeActionResult(body, &LikeResult{Liked: true})
 }
 
-// UnlikeTweet removes a like from a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) UnlikeTweet(userID, tweetID string) error {
-	_, err := c.doRequestV2OAuth1("DELETE", "/users/"+userID+"/likes/"+tweetID, nil)
-	return err
+// UnlikeTweet removes a like from a tweet (v2 API with OAuth 1.0a user context).
+// ErrNothingToUndo is returned when the tweet was not liked
+func (c *Client) UnlikeTweet(ctx context.Context, userID, tweetID string) error {
+	_, err := c.doRequestV2OAuth1(ctx, "DELETE", "/users/"+userID+"/likes/"+tweetID, nil)
+	return undoError(err)
+}
+
+// GetLikedTweets gets the tweets a user liked, with their authors in includes (v2 API with OAuth 1.0a user context)
+func (c *Client) GetLikedTweets(ctx context.Context, userID string, maxResults int) (*TweetsResponse, error) {
+	return c.getLikedTweetsPage(ctx, userID, maxResults, "")
+}
+
+// GetLikedTweetsAll gets up to maxTotal liked tweets, following pagination tokens.
+// maxTotal is capped to MaxPaginatedTweets
+func (c *Client) GetLikedTweetsAll(ctx context.Context, userID string, maxTotal int) (*PaginatedTweets, error) {
+	return collectTweetPages(maxTotal, 5, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		return c.getLikedTweetsPage(ctx, userID, pageSize, paginationToken)
+	})
+}
+
+// getLikedTweetsPage gets a page of liked tweets. The endpoint accepts between 5 and 100 results per page
+func (c *Client) getLikedTweetsPage(ctx context.Context, userID string, maxResults int, paginationToken string) (*TweetsResponse, error) {
+	if maxResults < 5 {
+		maxResults = 5
+	}
+	if maxResults > 100 {
+		maxResults = 100
+	}
+
+	endpoint := fmt.Sprintf("/users/%s/liked_tweets?max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities&expansions=author_id", userID, maxResults)
+	if paginationToken != "" {
+		endpoint += "&pagination_token=" + url.QueryEscape(paginationToken)
+	}
+
+	body, err := c.doRequestV2OAuth1(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TweetsResponse
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse liked tweets: %w", err)
+	}
+
+	return &response, nil
 }
 
 // Retweet retweets a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) Retweet(userID, tweetID string) error {
+func (c *Client) Retweet(ctx context.Context, userID, tweetID string) (*RetweetResult, error) {
 	payload := map[string]string{
 		"tweet_id": tweetID,
 	}
 
-	_, err := c.doRequestV2OAuth1("POST", "/users/"+userID+"/retweets", payload)
-	return err
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/users/"+userID+"/retweets", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeActionResult(body, &RetweetResult{Retweeted: true})
+}
+
+// UndoRetweet removes a retweet (v2 API with OAuth 1.0a user context).
+// ErrNothingToUndo is returned when the tweet was not retweeted
+func (c *Client) UndoRetweet(ctx context.Context, userID, tweetID string) error {
+	_, err := c.doRequestV2OAuth1(ctx, "DELETE", "/users/"+userID+"/retweets/"+tweetID, nil)
+	return undoError(err)
+}
+
+// LikeResult represents the state reported by the API after liking a tweet
+type LikeResult struct {
+	Liked bool `json:"liked"`
+}
+
+// RetweetResult represents the state reported by the API after retweeting a tweet
+type RetweetResult struct {
+	Retweeted bool `json:"retweeted"`
 }
 
-// UndoRetweet removes a retweet (v2 API with OAuth 1.0a user context)
-func (c *Client) UndoRetweet(userID, tweetID string) error {
-	_, err := c.doRequestV2OAuth1("DELETE", "/users/"+userID+"/retweets/"+tweetID, nil)
+// FollowResult represents the state reported by the API after following a user
+type FollowResult struct {
+	Following     bool `json:"following"`
+	PendingFollow bool `json:"pending_follow"`
+}
+
+// BookmarkResult represents the state reported by the API after bookmarking a tweet
+type BookmarkResult struct {
+	Bookmarked bool `json:"bookmarked"`
+}
+
+// decodeActionResult parses the data of a like, retweet, follow or bookmark response into result.
+// result comes filled with the expected state, which is kept when the API answers without a body
+func decodeActionResult[T any](body []byte, result *T) (*T, error) {
+	response := struct {
+		Data *T `json:"data"`
+	}{Data: result}
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result, nil
+}
+
+// undoError turns the 404 answered when undoing a missing like, retweet or bookmark into ErrNothingToUndo
+func undoError(err error) error {
+	if hasStatusCode(err, http.StatusNotFound) {
+		return fmt.Errorf("%w: %w", ErrNothingToUndo, err)
+	}
 	return err
 }
 
-// FollowUser follows a user (v2 API with OAuth 1.0a user context)
-func (c *Client) FollowUser(sourceUserID, targetUserID string) error {
+// FollowUser follows a user (v2 API with OAuth 1.0a user context).
+// Following a protected account only sends a request, reported as PendingFollow
+func (c *Client) FollowUser(ctx context.Context, sourceUserID, targetUserID string) (*FollowResult, error) {
 	payload := map[string]string{
 		"target_user_id": targetUserID,
 	}
 
-	_, err := c.doRequestV2OAuth1("POST", "/users/"+sourceUserID+"/following", payload)
-	return err
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/users/"+sourceUserID+"/following", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeActionResult(body, &FollowResult{Following: true})
 }
 
 // UnfollowUser unfollows a user (v2 API with OAuth 1.0a user context)
-func (c *Client) UnfollowUser(sourceUserID, targetUserID string) error {
-	_, err := c.doRequestV2OAuth1("DELETE", "/users/"+sourceUserID+"/following/"+targetUserID, nil)
+func (c *Client) UnfollowUser(ctx context.Context, sourceUserID, targetUserID string) error {
+	_, err := c.doRequestV2OAuth1(ctx, "DELETE", "/users/"+sourceUserID+"/following/"+targetUserID, nil)
 	return err
 }
 
 // GetUserByUsername gets a user's profile by username (v2 API)
-func (c *Client) GetUserByUsername(username string) (*User, error) {
-	endpoint := fmt.Sprintf("/users/by/username/%s?user.fields=description,public_metrics,created_at,profile_image_url", username)
+func (c *Client) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	endpoint := fmt.Sprintf("/users/by/username/%s?user.fields=%s", username, userProfileFields)
 
-	body, err := c.doRequestV2("GET", endpoint, nil)
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -592,9 +1235,12 @@ func (c *Client) GetUserByUsername(username string) (*User, error) {
 	var response struct {
 		Data UserProfile `json:"data"`
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse user response: %w", err)
 	}
+	if response.Data.ID == "" {
+		return nil, ErrEmptyResponse
+	}
 
 	return &User{
 		ID:       response.Data.ID,
@@ -605,15 +1251,21 @@ func (c *Client) GetUserByUsername(username string) (*User, error) {
 
 // UserProfile represents a detailed user profile
 type UserProfile struct {
-	ID              string        `json:"id"`
-	Name            string        `json:"name"`
-	Username        string        `json:"username"`
-	Description     string        `json:"description,omitempty"`
-	ProfileImageURL string        `json:"profile_image_url,omitempty"`
-	CreatedAt       string        `json:"created_at,omitempty"`
-	PublicMetrics   *UserMetrics  `json:"public_metrics,omitempty"`
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	Username        string       `json:"username"`
+	Description     string       `json:"description,omitempty"`
+	ProfileImageURL string       `json:"profile_image_url,omitempty"`
+	CreatedAt       string       `json:"created_at,omitempty"`
+	PublicMetrics   *UserMetrics `json:"public_metrics,omitempty"`
+	Verified        bool         `json:"verified,omitempty"`
+	VerifiedType    string       `json:"verified_type,omitempty"` // e.g. 'blue', 'business' or 'government'
+	Protected       bool         `json:"protected,omitempty"`     // Tweets only visible to approved followers
 }
 
+// userProfileFields are the user fields requested for a UserProfile
+const userProfileFields = "description,public_metrics,created_at,profile_image_url,verified,verified_type,protected"
+
 // UserMetrics represents user engagement metrics
 type UserMetrics struct {
 	FollowersCount int `json:"followers_count"`
@@ -623,10 +1275,10 @@ type UserMetrics struct {
 }
 
 // GetUserProfile gets a user's full profile by username (v2 API)
-func (c *Client) GetUserProfile(username string) (*UserProfile, error) {
-	endpoint := fmt.Sprintf("/users/by/username/%s?user.fields=description,public_metrics,created_at,profile_image_url", username)
+func (c *Client) GetUserProfile(ctx context.Context, username string) (*UserProfile, error) {
+	endpoint := fmt.Sprintf("/users/by/username/%s?user.fields=%s", username, userProfileFields)
 
-	body, err := c.doRequestV2("GET", endpoint, nil)
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -634,15 +1286,60 @@ func (c *Client) GetUserProfile(username string) (*UserProfile, error) {
 	var response struct {
 		Data UserProfile `json:"data"`
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse user profile: %w", err)
 	}
+	if response.Data.ID == "" {
+		return nil, ErrEmptyResponse
+	}
+
+	return &response.Data, nil
+}
+
+// GetMeProfile gets the full profile of the authenticated user (v2 API with OAuth 1.0a user context).
+// Unlike GetMe it is not cached, as it brings the current metrics
+func (c *Client) GetMeProfile(ctx context.Context) (*UserProfile, error) {
+	body, err := c.doRequestV2OAuth1(ctx, "GET", "/users/me?user.fields="+userProfileFields, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data UserProfile `json:"data"`
+	}
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse user profile: %w", err)
+	}
+	if response.Data.ID == "" {
+		return nil, ErrEmptyResponse
+	}
 
 	return &response.Data, nil
 }
 
 // GetUserTweets gets recent tweets from a specific user (v2 API)
-func (c *Client) GetUserTweets(userID string, maxResults int) (*TweetsResponse, error) {
+func (c *Client) GetUserTweets(ctx context.Context, userID string, maxResults int) (*TweetsResponse, error) {
+	return c.GetUserTweetsInRange(ctx, userID, time.Time{}, time.Time{}, maxResults)
+}
+
+// GetUserTweetsInRange gets tweets from a specific user bounded by time (v2 API).
+// Zero times are ignored
+func (c *Client) GetUserTweetsInRange(ctx context.Context, userID string, startTime, endTime time.Time, maxResults int) (*TweetsResponse, error) {
+	return c.getUserTweets(ctx, userID, startTime, endTime, maxResults, false)
+}
+
+// GetOwnTweetsInRange gets tweets of the authenticated user bounded by time, with their non-public and
+// organic metrics (v2 API with user context). X only returns those metrics for tweets from the last 30 days
+func (c *Client) GetOwnTweetsInRange(ctx context.Context, startTime, endTime time.Time, maxResults int) (*TweetsResponse, error) {
+	me, err := c.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	return c.getUserTweets(ctx, me.ID, startTime, endTime, maxResults, true)
+}
+
+// getUserTweets gets tweets from a user. Private metrics need the user context, so they are asked with it
+func (c *Client) getUserTweets(ctx context.Context, userID string, startTime, endTime time.Time, maxResults int, privateMetrics bool) (*TweetsResponse, error) {
 	if maxResults <= 0 {
 		maxResults = 10
 	}
@@ -650,39 +1347,193 @@ func (c *Client) GetUserTweets(userID string, maxResults int) (*TweetsResponse,
 		maxResults = 100
 	}
 
-	endpoint := fmt.Sprintf("/users/%s/tweets?max_results=%d&tweet.fields=created_at,author_id,public_metrics&expansions=author_id", userID, maxResults)
+	tweetFields := "created_at,author_id,public_metrics,entities"
+	if privateMetrics {
+		tweetFields += ",non_public_metrics,organic_metrics"
+	}
 
-	body, err := c.doRequestV2("GET", endpoint, nil)
+	endpoint := fmt.Sprintf("/users/%s/tweets?max_results=%d&tweet.fields=%s&expansions=author_id", userID, maxResults, tweetFields)
+	if !startTime.IsZero() {
+		endpoint += "&start_time=" + startTime.UTC().Format(time.RFC3339)
+	}
+	if !endTime.IsZero() {
+		endpoint += "&end_time=" + endTime.UTC().Format(time.RFC3339)
+	}
+
+	doRequest := c.doRequestV2
+	if privateMetrics {
+		doRequest = c.doRequestV2OAuth1
+	}
+
+	body, err := doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse user tweets: %w", err)
 	}
 
 	return &response, nil
 }
 
+// UsersResponse represents multiple users
+type UsersResponse struct {
+	Data []UserProfile `json:"data,omitempty"`
+	Meta struct {
+		ResultCount int    `json:"result_count"`
+		NextToken   string `json:"next_token,omitempty"`
+	} `json:"meta,omitempty"`
+}
+
+// getUsersPaginated fetches users from a v2 users-lookup endpoint, following pagination tokens
+// until maxResults users are collected or there are no more pages (v2 API)
+func (c *Client) getUsersPaginated(ctx context.Context, endpoint string, maxResults int) (*UsersResponse, error) {
+	if maxResults <= 0 {
+		maxResults = 100
+	}
+	if maxResults > 1000 {
+		maxResults = 1000
+	}
+
+	var result UsersResponse
+	var paginationToken string
+
+	for len(result.Data) < maxResults {
+		pageSize := maxResults - len(result.Data)
+		if pageSize > 100 {
+			pageSize = 100
+		}
+
+		pageEndpoint := fmt.Sprintf("%s?max_results=%d&user.fields=%s", endpoint, pageSize, userProfileFields)
+		if paginationToken != "" {
+			pageEndpoint += "&pagination_token=" + url.QueryEscape(paginationToken)
+		}
+
+		body, err := c.doRequestV2(ctx, "GET", pageEndpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page UsersResponse
+		if err := decodeResponse(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse users response: %w", err)
+		}
+
+		result.Data = append(result.Data, page.Data...)
+		result.Meta.NextToken = page.Meta.NextToken
+
+		paginationToken = page.Meta.NextToken
+		if paginationToken == "" || len(page.Data) == 0 {
+			break
+		}
+	}
+
+	result.Meta.ResultCount = len(result.Data)
+	return &result, nil
+}
+
+// GetLikingUsers gets the users who liked a tweet (v2 API)
+func (c *Client) GetLikingUsers(ctx context.Context, tweetID string, maxResults int) (*UsersResponse, error) {
+	return c.getUsersPaginated(ctx, "/tweets/"+tweetID+"/liking_users", maxResults)
+}
+
+// GetRetweeters gets the users who retweeted a tweet (v2 API)
+func (c *Client) GetRetweeters(ctx context.Context, tweetID string, maxResults int) (*UsersResponse, error) {
+	return c.getUsersPaginated(ctx, "/tweets/"+tweetID+"/retweeted_by", maxResults)
+}
+
+// GetQuoteTweets gets the tweets quoting a tweet, with their authors, following pagination tokens
+// until maxResults tweets are collected or there are no more pages (v2 API)
+func (c *Client) GetQuoteTweets(ctx context.Context, tweetID string, maxResults int) (*TweetsResponse, error) {
+	if maxResults <= 0 {
+		maxResults = 100
+	}
+	if maxResults > 500 {
+		maxResults = 500
+	}
+
+	var result TweetsResponse
+	var paginationToken string
+	knownAuthors := make(map[string]bool)
+
+	for len(result.Data) < maxResults {
+		// The endpoint accepts between 10 and 100 tweets per page
+		pageSize := min(max(maxResults-len(result.Data), 10), 100)
+
+		endpoint := fmt.Sprintf("/tweets/%s/quote_tweets?max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities&expansions=author_id", tweetID, pageSize)
+		if paginationToken != "" {
+			endpoint += "&pagination_token=" + url.QueryEscape(paginationToken)
+		}
+
+		body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page TweetsResponse
+		if err := decodeResponse(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse quote tweets response: %w", err)
+		}
+
+		result.Data = append(result.Data, page.Data...)
+		for _, author := range page.Includes.Users {
+			if !knownAuthors[author.ID] {
+				knownAuthors[author.ID] = true
+				result.Includes.Users = append(result.Includes.Users, author)
+			}
+		}
+		result.Meta.NextToken = page.Meta.NextToken
+
+		paginationToken = page.Meta.NextToken
+		if paginationToken == "" || len(page.Data) == 0 {
+			break
+		}
+	}
+
+	if len(result.Data) > maxResults {
+		result.Data = result.Data[:maxResults]
+	}
+	result.Meta.ResultCount = len(result.Data)
+	return &result, nil
+}
+
 // BookmarkTweet bookmarks a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) BookmarkTweet(userID, tweetID string) error {
+func (c *Client) BookmarkTweet(ctx context.Context, userID, tweetID string) (*BookmarkResult, error) {
 	payload := map[string]string{
 		"tweet_id": tweetID,
 	}
 
-	_, err := c.doRequestV2OAuth1("POST", "/users/"+userID+"/bookmarks", payload)
-	return err
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/users/"+userID+"/bookmarks", payload)
+	if err != nil {
+		return nil, err
+	}
+	return decodeActionResult(body, &BookmarkResult{Bookmarked: true})
 }
 
-// RemoveBookmark removes a bookmark from a tweet (v2 API with OAuth 1.0a user context)
-func (c *Client) RemoveBookmark(userID, tweetID string) error {
-	_, err := c.doRequestV2OAuth1("DELETE", "/users/"+userID+"/bookmarks/"+tweetID, nil)
-	return err
+// RemoveBookmark removes a bookmark from a tweet (v2 API with OAuth 1.0a user context).
+// ErrNothingToUndo is returned when the tweet was not bookmarked
+func (c *Client) RemoveBookmark(ctx context.Context, userID, tweetID string) error {
+	_, err := c.doRequestV2OAuth1(ctx, "DELETE", "/users/"+userID+"/bookmarks/"+tweetID, nil)
+	return undoError(err)
 }
 
 // GetBookmarks gets the authenticated user's bookmarks (v2 API with OAuth 1.0a user context)
-func (c *Client) GetBookmarks(userID string, maxResults int) (*TweetsResponse, error) {
+func (c *Client) GetBookmarks(ctx context.Context, userID string, maxResults int) (*TweetsResponse, error) {
+	return c.getBookmarksPage(ctx, userID, maxResults, "")
+}
+
+// GetBookmarksAll gets up to maxTotal bookmarks, following pagination tokens.
+// maxTotal is capped to MaxPaginatedTweets
+func (c *Client) GetBookmarksAll(ctx context.Context, userID string, maxTotal int) (*PaginatedTweets, error) {
+	return collectTweetPages(maxTotal, 1, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		return c.getBookmarksPage(ctx, userID, pageSize, paginationToken)
+	})
+}
+
+// getBookmarksPage gets a page of bookmarks. An empty pagination token asks for the first page
+func (c *Client) getBookmarksPage(ctx context.Context, userID string, maxResults int, paginationToken string) (*TweetsResponse, error) {
 	if maxResults <= 0 {
 		maxResults = 10
 	}
@@ -690,36 +1541,73 @@ func (c *Client) GetBookmarks(userID string, maxResults int) (*TweetsResponse, e
 		maxResults = 100
 	}
 
-	endpoint := fmt.Sprintf("/users/%s/bookmarks?max_results=%d&tweet.fields=created_at,author_id,public_metrics&expansions=author_id", userID, maxResults)
+	endpoint := fmt.Sprintf("/users/%s/bookmarks?max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities&expansions=author_id", userID, maxResults)
+	if paginationToken != "" {
+		endpoint += "&pagination_token=" + url.QueryEscape(paginationToken)
+	}
 
-	body, err := c.doRequestV2OAuth1("GET", endpoint, nil)
+	body, err := c.doRequestV2OAuth1(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse bookmarks: %w", err)
 	}
 
 	return &response, nil
 }
 
-// PostThread posts a thread of tweets (v2 API)
-func (c *Client) PostThread(tweets []string) ([]*Tweet, error) {
-	var postedTweets []*Tweet
-	var replyToID string
+// ThreadTweet is a posted tweet of a thread, with the tweet it replies to
+type ThreadTweet struct {
+	Position  int    `json:"position"` // 0 for the head of the thread
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	InReplyTo string `json:"in_reply_to,omitempty"`
+	URL       string `json:"url"`
+}
 
-	for _, text := range tweets {
-		tweet, err := c.PostTweet(text, replyToID)
+// ThreadResult is the outcome of posting a thread. When it fails midway, Tweets holds the ones already posted,
+// so the thread can be resumed replying to the last of them, or cleaned up
+type ThreadResult struct {
+	Complete bool          `json:"complete"`
+	Posted   int           `json:"posted"`
+	Total    int           `json:"total"`
+	Tweets   []ThreadTweet `json:"tweets"`
+
+	// FailedAt is the position of the tweet that could not be posted
+	FailedAt *int          `json:"failed_at,omitempty"`
+	Error    *ErrorDetails `json:"error,omitempty"`
+}
+
+// PostThread posts a thread of tweets, optionally replying to an existing tweet with the head (v2 API).
+// It stops at the first failure, returning the result so far along with the error
+func (c *Client) PostThread(ctx context.Context, tweets []string, replyToID string) (*ThreadResult, error) {
+	result := &ThreadResult{Total: len(tweets), Tweets: []ThreadTweet{}}
+
+	for i, text := range tweets {
+		tweet, err := c.PostTweet(ctx, text, replyToID, PostTweetOptions{})
 		if err != nil {
-			return postedTweets, fmt.Errorf("failed to post tweet in thread: %w", err)
+			details := GetErrorDetails(err)
+			result.FailedAt = &i
+			result.Error = &details
+			return result, fmt.Errorf("failed to post tweet %d of the thread: %w", i, err)
 		}
-		postedTweets = append(postedTweets, tweet)
+
+		result.Tweets = append(result.Tweets, ThreadTweet{
+			Position:  i,
+			ID:        tweet.ID,
+			Text:      tweet.Text,
+			InReplyTo: replyToID,
+			URL:       TweetURL(tweet.ID),
+		})
+		result.Posted++
 		replyToID = tweet.ID
 	}
 
-	return postedTweets, nil
+	result.Complete = true
+	return result, nil
 }
 
 // MediaUploadResponse represents the response from media upload
@@ -728,29 +1616,39 @@ type MediaUploadResponse struct {
 	MediaIDString string `json:"media_id_string"`
 }
 
-// UploadMedia uploads media (image) to Twitter (v1.1 API)
-func (c *Client) UploadMedia(imageData []byte) (*MediaUploadResponse, error) {
+// UploadMedia uploads media (image) to Twitter (v1.1 API).
+// The media type is detected from the data, and unsupported types or sizes are rejected before uploading
+func (c *Client) UploadMedia(ctx context.Context, imageData []byte) (*MediaUploadResponse, error) {
+	_, mediaCategory, err := detectMediaType(imageData)
+	if err != nil {
+		return nil, err
+	}
+
 	// Base64 encode the image
 	encoded := base64.StdEncoding.EncodeToString(imageData)
 
 	params := url.Values{}
 	params.Set("media_data", encoded)
+	params.Set("media_category", mediaCategory)
 
-	body, err := c.doRequestV1Form("/media/upload.json", params)
+	body, err := c.doRequestV1Form(ctx, "/media/upload.json", params)
 	if err != nil {
 		return nil, err
 	}
 
 	var response MediaUploadResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse media upload response: %w", err)
 	}
+	if response.MediaIDString == "" {
+		return nil, ErrEmptyResponse
+	}
 
 	return &response, nil
 }
 
 // PostTweetWithMedia posts a tweet with media attachments (v2 API with OAuth 1.0a user context)
-func (c *Client) PostTweetWithMedia(text string, mediaIDs []string) (*Tweet, error) {
+func (c *Client) PostTweetWithMedia(ctx context.Context, text string, mediaIDs []string) (*Tweet, error) {
 	payload := map[string]interface{}{
 		"text": text,
 	}
@@ -761,15 +1659,18 @@ func (c *Client) PostTweetWithMedia(text string, mediaIDs []string) (*Tweet, err
 		}
 	}
 
-	body, err := c.doRequestV2OAuth1("POST", "/tweets", payload)
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/tweets", payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response TweetResponse
-	if err := json.Unmarshal(body, &response); err != nil {
+	if err := decodeResponse(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse tweet response: %w", err)
 	}
+	if response.Data == nil {
+		return nil, ErrEmptyResponse
+	}
 
 	return response.Data, nil
 }
@@ -0,0 +1,578 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client sending every request, for all the authentication methods, to the handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClientWithOptions(
+		WithCredentials("key", "secret", "token", "tokenSecret"),
+		WithBearerToken("bearer"),
+		WithBaseURL(server.URL),
+		WithRetry(RetryPolicy{BaseDelay: time.Millisecond}),
+	)
+}
+
+func TestPostTweetRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		replyToID string
+		opts      PostTweetOptions
+		expected  map[string]any
+	}{
+		{
+			name:     "plain tweet",
+			text:     "hello",
+			expected: map[string]any{"text": "hello"},
+		},
+		{
+			name:      "reply",
+			text:      "hi back",
+			replyToID: "42",
+			expected:  map[string]any{"text": "hi back", "reply": map[string]any{"in_reply_to_tweet_id": "42"}},
+		},
+		{
+			name:     "with place",
+			text:     "here",
+			opts:     PostTweetOptions{PlaceID: "abc"},
+			expected: map[string]any{"text": "here", "geo": map[string]any{"place_id": "abc"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var payload map[string]any
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/2/tweets" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("Authorization") == "" {
+					t.Errorf("expected a signed request")
+				}
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.Write([]byte(`{"data": {"id": "100", "text": "posted"}}`))
+			})
+
+			tweet, err := client.PostTweet(context.Background(), test.text, test.replyToID, test.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tweet.ID != "100" {
+				t.Errorf("expected tweet '100', got '%s'", tweet.ID)
+			}
+
+			expected, _ := json.Marshal(test.expected)
+			got, _ := json.Marshal(payload)
+			if string(expected) != string(got) {
+				t.Errorf("expected payload %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestSearchTweetsPagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxTotal      int
+		pages         []string
+		expectedIDs   int
+		expectedPages int
+	}{
+		{
+			name:     "single page",
+			maxTotal: 10,
+			pages: []string{
+				`{"data": [{"id": "1"}, {"id": "2"}], "meta": {"result_count": 2}}`,
+			},
+			expectedIDs:   2,
+			expectedPages: 1,
+		},
+		{
+			name:     "follows next tokens",
+			maxTotal: 100,
+			pages: []string{
+				`{"data": [{"id": "1"}], "meta": {"result_count": 1, "next_token": "p1"}}`,
+				`{"data": [{"id": "2"}], "meta": {"result_count": 1, "next_token": "p2"}}`,
+				`{"data": [{"id": "3"}], "meta": {"result_count": 1}}`,
+			},
+			expectedIDs:   3,
+			expectedPages: 3,
+		},
+		{
+			name:     "empty result",
+			maxTotal: 10,
+			pages: []string{
+				`{"meta": {"result_count": 0}}`,
+			},
+			expectedIDs:   0,
+			expectedPages: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/2/tweets/search/recent" {
+					t.Errorf("unexpected path '%s'", r.URL.Path)
+				}
+				if page > 0 && r.URL.Query().Get("next_token") == "" {
+					t.Errorf("expected page %d to carry the next token", page+1)
+				}
+				w.Write([]byte(test.pages[page]))
+				page++
+			})
+
+			tweets, err := client.SearchTweetsInRangeAll(context.Background(), "golang", time.Time{}, time.Time{}, test.maxTotal, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tweets.Data) != test.expectedIDs || tweets.Pages != test.expectedPages {
+				t.Errorf("expected %d tweets in %d pages, got %d in %d", test.expectedIDs, test.expectedPages, len(tweets.Data), tweets.Pages)
+			}
+		})
+	}
+}
+
+func TestErrorStatusHandling(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectedCode string
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"title": "Unauthorized", "detail": "Unauthorized", "type": "about:blank"}`, "unauthorized"},
+		{"forbidden", http.StatusForbidden, `{"title": "Forbidden", "detail": "not permitted"}`, "forbidden"},
+		{"rate limited", http.StatusTooManyRequests, `{"title": "Too Many Requests"}`, "too-many-requests"},
+		{"usage capped", http.StatusTooManyRequests, `{"title": "UsageCapExceeded", "type": "https://api.twitter.com/2/problems/usage-capped"}`, "usage-capped"},
+		{"server error without body", http.StatusInternalServerError, ``, "internal-server-error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			})
+
+			_, err := client.SearchTweets(context.Background(), "golang", 10)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != test.status {
+				t.Fatalf("expected an *APIError with status %d, got %v", test.status, err)
+			}
+			if apiErr.Body != test.body || (apiErr.Parsed != nil) != (test.body != "") {
+				t.Errorf("expected the body to be kept and parsed, got %+v", apiErr)
+			}
+			if details := GetErrorDetails(err); details.Code != test.expectedCode || details.StatusCode != test.status {
+				t.Errorf("expected code '%s', got %+v", test.expectedCode, details)
+			}
+		})
+	}
+}
+
+func TestGetTrendsParsing(t *testing.T) {
+	tests := []struct {
+		name          string
+		woeid         int
+		expectedWoeid string
+		body          string
+		expected      []Trend
+	}{
+		{
+			name:          "defaults to worldwide",
+			woeid:         0,
+			expectedWoeid: "1",
+			body:          `[{"trends": [{"name": "#golang", "url": "http://twitter.com/search?q=%23golang", "tweet_volume": 1200, "query": "%23golang"}]}]`,
+			expected:      []Trend{{Name: "#golang", URL: "http://twitter.com/search?q=%23golang", TweetVolume: 1200, Query: "%23golang"}},
+		},
+		{
+			name:          "missing volume",
+			woeid:         23424950,
+			expectedWoeid: "23424950",
+			body:          `[{"trends": [{"name": "Madrid", "tweet_volume": null}]}]`,
+			expected:      []Trend{{Name: "Madrid"}},
+		},
+		{
+			name:          "no locations",
+			woeid:         2,
+			expectedWoeid: "2",
+			body:          `[]`,
+			expected:      []Trend{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/1.1/trends/place.json" || r.URL.Query().Get("id") != test.expectedWoeid {
+					t.Errorf("unexpected request '%s'", r.URL.String())
+				}
+				w.Write([]byte(test.body))
+			})
+
+			trends, err := client.GetTrends(context.Background(), test.woeid)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected, _ := json.Marshal(test.expected)
+			got, _ := json.Marshal(trends)
+			if string(expected) != string(got) {
+				t.Errorf("expected %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestGetTrendsIsCachedPerLocation(t *testing.T) {
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Query().Get("id")]++
+		w.Write([]byte(`[{"trends": [{"name": "#golang"}]}]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(WithBaseURL(server.URL))
+	for _, woeid := range []int{1, 0, 766273, 1} {
+		if _, err := client.GetTrends(context.Background(), woeid); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls["1"] != 1 || calls["766273"] != 1 {
+		t.Errorf("expected one call per location, got %v", calls)
+	}
+
+	// Expired entries are fetched again
+	client.trends.entries[1] = TrendsSnapshot{WOEID: 1, FetchedAt: time.Now().Add(-DefaultTrendsCacheTTL)}
+	snapshot, err := client.GetTrendsSnapshot(context.Background(), 1)
+	if err != nil || calls["1"] != 2 || len(snapshot.Trends) != 1 {
+		t.Errorf("expected the expired trends to be fetched again, got %d calls (%v)", calls["1"], err)
+	}
+
+	uncached := NewClientWithOptions(WithBaseURL(server.URL), WithTrendsCacheTTL(0))
+	uncached.GetTrends(context.Background(), 2)
+	uncached.GetTrends(context.Background(), 2)
+	if calls["2"] != 2 {
+		t.Errorf("expected a zero TTL to disable the cache, got %d calls", calls["2"])
+	}
+}
+
+func TestGetOwnTweetsInRange(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2/users/me":
+			w.Write([]byte(`{"data": {"id": "42", "username": "me"}}`))
+		case "/2/users/42/tweets":
+			if !strings.Contains(r.URL.Query().Get("tweet.fields"), "non_public_metrics,organic_metrics") {
+				t.Errorf("expected private metrics to be requested, got '%s'", r.URL.RawQuery)
+			}
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "OAuth ") {
+				t.Errorf("expected a user context request, got '%s'", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"data": [{"id": "1", "text": "a",
+				"non_public_metrics": {"impression_count": 1500, "url_link_clicks": 3, "user_profile_clicks": 7},
+				"organic_metrics": {"impression_count": 1400, "like_count": 20, "reply_count": 1, "retweet_count": 2, "url_link_clicks": 3, "user_profile_clicks": 6}}]}`))
+		default:
+			t.Errorf("unexpected path '%s'", r.URL.Path)
+		}
+	})
+
+	tweets, err := client.GetOwnTweetsInRange(context.Background(), time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tweet := tweets.Data[0]
+	if tweet.NonPublicMetrics == nil || tweet.NonPublicMetrics.ImpressionCount != 1500 || tweet.NonPublicMetrics.UserProfileClicks != 7 {
+		t.Errorf("unexpected non-public metrics: %+v", tweet.NonPublicMetrics)
+	}
+	if tweet.OrganicMetrics == nil || tweet.OrganicMetrics.ImpressionCount != 1400 || tweet.OrganicMetrics.LikeCount != 20 {
+		t.Errorf("unexpected organic metrics: %+v", tweet.OrganicMetrics)
+	}
+}
+
+func TestGetTopicsHeatScoring(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "seen":
+			w.Write([]byte(`{"data": [
+				{"id": "1", "public_metrics": {"like_count": 5, "retweet_count": 1, "reply_count": 0, "quote_count": 0, "impression_count": 99999}},
+				{"id": "2", "public_metrics": {"like_count": 5, "retweet_count": 1, "reply_count": 0, "quote_count": 0}}
+			]}`))
+		default:
+			w.Write([]byte(`{"data": [{"id": "3", "public_metrics": {"like_count": 9, "retweet_count": 0, "reply_count": 0, "quote_count": 0}}]}`))
+		}
+	})
+
+	tests := []struct {
+		name        string
+		scoring     string
+		topic       string
+		expected    float64
+		reachSource string
+	}{
+		// 2 of 10 tweets = 8 points, avg engagement 6 = 20 * (1 + log10(7))
+		{"default is engagement", "", "seen", 8 + 20*(1+logBase10(7)), ""},
+		// 2 of 10 tweets = 6 points, 99999 impressions = 70 points
+		{"reach from impressions", HeatScoringReach, "seen", 6 + 70, "impressions"},
+		// 1 of 10 tweets = 3 points, 9 engagements = 14 points
+		{"reach from engagement", HeatScoringReach, "unseen", 3 + 14, "engagement"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			heats, err := client.GetTopicsHeat(context.Background(), []string{test.topic}, 10, test.scoring)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			heat := heats[0]
+			if math.Abs(heat.HeatScore-test.expected) > 0.01 || heat.ReachSource != test.reachSource {
+				t.Errorf("expected %.2f from '%s', got %.2f from '%s'", test.expected, test.reachSource, heat.HeatScore, heat.ReachSource)
+			}
+		})
+	}
+
+	if _, err := client.GetTopicsHeat(context.Background(), []string{"seen"}, 10, "virality"); err == nil {
+		t.Errorf("expected an error for an unknown scoring")
+	}
+}
+
+func TestGetTweetMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorID    string
+		privateFail bool
+		wantOwned   bool
+		wantPrivate bool
+	}{
+		{name: "own tweet", authorID: "42", wantOwned: true, wantPrivate: true},
+		{name: "tweet of someone else", authorID: "7"},
+		{name: "own tweet too old for private metrics", authorID: "42", privateFail: true, wantOwned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/2/users/me":
+					w.Write([]byte(`{"data": {"id": "42", "username": "me"}}`))
+				case "/2/tweets/1":
+					if !strings.Contains(r.URL.Query().Get("tweet.fields"), "non_public_metrics") {
+						w.Write([]byte(`{"data": {"id": "1", "text": "a", "author_id": "` + tt.authorID + `",
+							"public_metrics": {"like_count": 5, "impression_count": 900}}}`))
+						return
+					}
+					if tt.privateFail {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"title": "Invalid Request", "detail": "metrics unavailable"}`))
+						return
+					}
+					w.Write([]byte(`{"data": {"id": "1", "text": "a",
+						"non_public_metrics": {"impression_count": 900, "user_profile_clicks": 4},
+						"organic_metrics": {"impression_count": 850, "like_count": 5}}}`))
+				default:
+					t.Errorf("unexpected path '%s'", r.URL.Path)
+				}
+			})
+
+			metrics, err := client.GetTweetMetrics(context.Background(), "1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if metrics.PublicMetrics == nil || metrics.PublicMetrics.LikeCount != 5 || metrics.PublicMetrics.ImpressionCount != 900 {
+				t.Errorf("unexpected public metrics: %+v", metrics.PublicMetrics)
+			}
+			if metrics.Owned != tt.wantOwned {
+				t.Errorf("expected owned %v, got %v", tt.wantOwned, metrics.Owned)
+			}
+			hasPrivate := metrics.NonPublicMetrics != nil && metrics.OrganicMetrics != nil
+			if hasPrivate != tt.wantPrivate {
+				t.Errorf("expected private metrics %v, got %+v and %+v", tt.wantPrivate, metrics.NonPublicMetrics, metrics.OrganicMetrics)
+			}
+			if !tt.wantPrivate && metrics.PrivateMetricsUnavailable == "" {
+				t.Error("expected a reason for the missing private metrics")
+			}
+		})
+	}
+}
+
+func TestDeleteTweet(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		expectErr bool
+	}{
+		{"deleted", http.StatusOK, `{"data": {"deleted": true}}`, false},
+		{"not deleted", http.StatusOK, `{"data": {"deleted": false}}`, true},
+		{"already deleted", http.StatusNotFound, `{"title": "Not Found Error"}`, false},
+		{"no content", http.StatusNoContent, ``, false},
+		{"forbidden", http.StatusForbidden, `{"title": "Forbidden"}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/2/tweets/123" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			})
+
+			err := client.DeleteTweet(context.Background(), "123")
+			if (err != nil) != test.expectErr {
+				t.Errorf("expected error %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestActionResults(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2/users/42/following":
+			w.Write([]byte(`{"data": {"following": false, "pending_follow": true}}`))
+		case "/2/users/42/likes":
+			w.Write([]byte(`{"data": {"liked": true}}`))
+		case "/2/users/42/retweets":
+			// No body: the expected state is kept
+		default:
+			t.Errorf("unexpected path '%s'", r.URL.Path)
+		}
+	})
+
+	follow, err := client.FollowUser(context.Background(), "42", "7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if follow.Following || !follow.PendingFollow {
+		t.Errorf("expected a pending follow, got %+v", follow)
+	}
+
+	liked, err := client.LikeTweet(context.Background(), "42", "1")
+	if err != nil || !liked.Liked {
+		t.Errorf("expected the tweet to be liked, got %+v, %v", liked, err)
+	}
+
+	retweeted, err := client.Retweet(context.Background(), "42", "1")
+	if err != nil || !retweeted.Retweeted {
+		t.Errorf("expected the tweet to be retweeted, got %+v, %v", retweeted, err)
+	}
+}
+
+func TestGetLikedTweetsAll(t *testing.T) {
+	var pageSizes []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2/users/42/liked_tweets" {
+			t.Errorf("unexpected path '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("expansions") != "author_id" {
+			t.Errorf("expected authors to be expanded, got '%s'", r.URL.RawQuery)
+		}
+		pageSizes = append(pageSizes, r.URL.Query().Get("max_results"))
+
+		if r.URL.Query().Get("pagination_token") == "" {
+			w.Write([]byte(`{"data": [{"id": "1", "text": "a", "author_id": "7"}],
+				"includes": {"users": [{"id": "7", "username": "alice", "name": "Alice"}]},
+				"meta": {"result_count": 1, "next_token": "next"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "2", "text": "b", "author_id": "7"}],
+			"includes": {"users": [{"id": "7", "username": "alice", "name": "Alice"}]},
+			"meta": {"result_count": 1}}`))
+	})
+
+	likes, err := client.GetLikedTweetsAll(context.Background(), "42", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(likes.Data) != 2 || len(likes.Includes.Users) != 1 {
+		t.Errorf("expected 2 tweets of a single author, got %d tweets and %d authors", len(likes.Data), len(likes.Includes.Users))
+	}
+	if !slices.Equal(pageSizes, []string{"5", "5"}) {
+		t.Errorf("expected pages of at least 5 results, got %v", pageSizes)
+	}
+}
+
+func TestFindInactiveFollowing(t *testing.T) {
+	recent := time.Now().AddDate(0, 0, -2).UTC().Format(time.RFC3339)
+	old := time.Now().AddDate(0, 0, -200).UTC().Format(time.RFC3339)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2/users/42/following":
+			w.Write([]byte(`{"data": [
+				{"id": "1", "username": "active", "public_metrics": {"tweet_count": 10}},
+				{"id": "2", "username": "dormant", "public_metrics": {"tweet_count": 10}},
+				{"id": "3", "username": "silent", "public_metrics": {"tweet_count": 0}},
+				{"id": "4", "username": "locked", "public_metrics": {"tweet_count": 10}}
+			], "meta": {"result_count": 4, "next_token": "more"}}`))
+		case "/2/users/1/tweets":
+			w.Write([]byte(`{"data": [{"id": "10", "text": "a", "created_at": "` + recent + `"}]}`))
+		case "/2/users/2/tweets":
+			w.Write([]byte(`{"data": [{"id": "20", "text": "b", "created_at": "` + old + `"}]}`))
+		case "/2/users/4/tweets":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"title": "Authorization Error"}`))
+		default:
+			t.Errorf("unexpected path '%s'", r.URL.Path)
+		}
+	})
+
+	inactive, err := client.FindInactiveFollowing(context.Background(), "42", 90, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var usernames []string
+	for _, account := range inactive.Accounts {
+		usernames = append(usernames, account.Username)
+	}
+	if !slices.Equal(usernames, []string{"silent", "dormant"}) {
+		t.Errorf("expected the silent account first and then the dormant one, got %v", usernames)
+	}
+	if dormant := inactive.Accounts[1]; dormant.LastTweetAt != old || dormant.DaysInactive < 199 {
+		t.Errorf("unexpected dormant account: %+v", dormant)
+	}
+	if inactive.Checked != 3 || len(inactive.Failed) != 1 || inactive.Failed[0].Item != "locked" {
+		t.Errorf("expected 3 checked accounts and the locked one failed, got %+v", inactive)
+	}
+	if !inactive.Truncated {
+		t.Error("expected the result to be truncated")
+	}
+}
@@ -15,7 +15,16 @@
 package twitter
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -71,3 +80,510 @@ func TestSortTopicsByHeat(t *testing.T) {
 		t.Errorf("expected third topic to be 'low', got '%s'", topics[2].Topic)
 	}
 }
+
+func TestSearchTweetsInRangeValidation(t *testing.T) {
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+
+	_, err := client.SearchTweetsInRange(context.Background(), "golang", time.Now().Add(-8*24*time.Hour), time.Time{}, 10, "")
+	if err == nil {
+		t.Errorf("expected error for start_time outside the 7-day window")
+	}
+
+	_, err = client.SearchTweetsInRange(context.Background(), "golang", time.Time{}, time.Now().Add(time.Hour), 10, "")
+	if err == nil {
+		t.Errorf("expected error for end_time in the future")
+	}
+
+	_, err = client.SearchTweetsInRange(context.Background(), "golang", time.Time{}, time.Time{}, 10, "popularity")
+	if err == nil {
+		t.Errorf("expected error for invalid sort order")
+	}
+}
+
+func TestTweetEntitiesParsing(t *testing.T) {
+	body := `{"data": [
+		{"id": "1", "text": "Hello #golang $TWTR @gopher https://t.co/x", "entities": {
+			"hashtags": [{"start": 6, "end": 13, "tag": "golang"}],
+			"cashtags": [{"start": 14, "end": 19, "tag": "TWTR"}],
+			"mentions": [{"start": 20, "end": 27, "username": "gopher", "id": "42"}],
+			"urls": [{"start": 28, "end": 41, "url": "https://t.co/x", "expanded_url": "https://go.dev", "display_url": "go.dev"}]
+		}},
+		{"id": "2", "text": "No entities here"}
+	]}`
+
+	var response TweetsResponse
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	entities := response.Data[0].Entities
+	if entities == nil {
+		t.Fatal("expected entities to be parsed")
+	}
+	if len(entities.Hashtags) != 1 || entities.Hashtags[0].Tag != "golang" {
+		t.Errorf("unexpected hashtags: %+v", entities.Hashtags)
+	}
+	if len(entities.Cashtags) != 1 || entities.Cashtags[0].Tag != "TWTR" {
+		t.Errorf("unexpected cashtags: %+v", entities.Cashtags)
+	}
+	if len(entities.Mentions) != 1 || entities.Mentions[0].Username != "gopher" {
+		t.Errorf("unexpected mentions: %+v", entities.Mentions)
+	}
+	if len(entities.URLs) != 1 || entities.URLs[0].ExpandedURL != "https://go.dev" {
+		t.Errorf("unexpected urls: %+v", entities.URLs)
+	}
+
+	if response.Data[1].Entities != nil {
+		t.Errorf("expected no entities for the second tweet")
+	}
+}
+
+func TestGetTweetTopics(t *testing.T) {
+	tech := ContextAnnotationItem{ID: "1", Name: "Technology"}
+	brand := ContextAnnotationItem{ID: "2", Name: "Brand"}
+
+	tweets := []Tweet{
+		{ID: "1", ContextAnnotations: []ContextAnnotation{
+			{Domain: tech, Entity: ContextAnnotationItem{Name: "Kubernetes"}},
+			{Domain: tech, Entity: ContextAnnotationItem{Name: "Docker"}},
+		}},
+		{ID: "2", ContextAnnotations: []ContextAnnotation{
+			{Domain: tech, Entity: ContextAnnotationItem{Name: "Kubernetes"}},
+			{Domain: brand, Entity: ContextAnnotationItem{Name: "Google"}},
+		}},
+		{ID: "3"},
+	}
+
+	topics := GetTweetTopics(tweets)
+
+	if len(topics) != 2 {
+		t.Fatalf("expected 2 topics, got %d", len(topics))
+	}
+	if topics[0].Domain != "Technology" || topics[0].TweetCount != 2 {
+		t.Errorf("expected 'Technology' with 2 tweets first, got %+v", topics[0])
+	}
+	if len(topics[0].Entities) != 2 {
+		t.Errorf("expected 2 unique entities for 'Technology', got %v", topics[0].Entities)
+	}
+	if topics[1].Domain != "Brand" || topics[1].TweetCount != 1 {
+		t.Errorf("expected 'Brand' with 1 tweet second, got %+v", topics[1])
+	}
+
+	if len(GetTweetTopics(nil)) != 0 {
+		t.Errorf("expected no topics for no tweets")
+	}
+}
+
+func TestFilterTweetsByAuthor(t *testing.T) {
+	newResponse := func() *TweetsResponse {
+		response := &TweetsResponse{Data: []Tweet{
+			{ID: "1", AuthorID: "10"},
+			{ID: "2", AuthorID: "20"},
+			{ID: "3", AuthorID: "30"},
+			{ID: "4", AuthorID: "99"},
+		}}
+		response.Includes.Users = []User{{ID: "10", Username: "Alice"}, {ID: "20", Username: "bob"}, {ID: "30", Username: "carol"}}
+		response.Meta.ResultCount = 4
+		return response
+	}
+	ids := func(response *TweetsResponse) []string {
+		var ids []string
+		for _, tweet := range response.Data {
+			ids = append(ids, tweet.ID)
+		}
+		return ids
+	}
+
+	tests := map[string]struct {
+		from     []string
+		exclude  []string
+		expected []string
+	}{
+		"no filters":   {expected: []string{"1", "2", "3", "4"}},
+		"from":         {from: []string{"alice", "carol"}, expected: []string{"1", "3"}},
+		"exclude":      {exclude: []string{"BOB"}, expected: []string{"1", "3", "4"}},
+		"both":         {from: []string{"alice", "bob"}, exclude: []string{"bob"}, expected: []string{"1"}},
+		"nothing kept": {from: []string{"dave"}, expected: nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := newResponse()
+			FilterTweetsByAuthor(response, test.from, test.exclude)
+
+			if got := ids(response); !slices.Equal(got, test.expected) {
+				t.Errorf("expected tweets %v, got %v", test.expected, got)
+			}
+			if response.Meta.ResultCount != len(test.expected) {
+				t.Errorf("expected result count %d, got %d", len(test.expected), response.Meta.ResultCount)
+			}
+		})
+	}
+}
+
+func TestMentionsOfQuery(t *testing.T) {
+	expected := "(@golang OR to:golang) -from:golang"
+	if query := mentionsOfQuery("golang"); query != expected {
+		t.Errorf("expected '%s', got '%s'", expected, query)
+	}
+}
+
+func TestTimelineOptionsQueryParams(t *testing.T) {
+	tests := []struct {
+		opts        TimelineOptions
+		expected    string
+		expectError bool
+	}{
+		{TimelineOptions{}, "", false},
+		{TimelineOptions{Exclude: []string{"retweets", "replies"}}, "&exclude=retweets,replies", false},
+		{TimelineOptions{SinceID: "100", UntilID: "200"}, "&since_id=100&until_id=200", false},
+		{TimelineOptions{Exclude: []string{"likes"}}, "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.opts.queryParams()
+		if (err != nil) != tt.expectError {
+			t.Errorf("queryParams(%+v) error = %v, expectError %v", tt.opts, err, tt.expectError)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("queryParams(%+v) = '%s', expected '%s'", tt.opts, result, tt.expected)
+		}
+	}
+}
+
+func TestPostTweetWithPlace(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"data": {"id": "1", "text": "hello"}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	if _, err := client.PostTweet(context.Background(), "hello", "", PostTweetOptions{PlaceID: "5a110d312052166f"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	geo, _ := payload["geo"].(map[string]any)
+	if geo["place_id"] != "5a110d312052166f" {
+		t.Errorf("expected place ID nested in geo, got %v", payload)
+	}
+
+	if _, err := client.PostTweet(context.Background(), "hello", "", PostTweetOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := payload["geo"]; found {
+		t.Errorf("expected no geo without place ID, got %v", payload)
+	}
+}
+
+func TestPostThread(t *testing.T) {
+	var replies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Text  string `json:"text"`
+			Reply struct {
+				InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+			} `json:"reply"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload.Text == "fails" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"title": "Forbidden", "detail": "duplicate content"}`))
+			return
+		}
+		replies = append(replies, payload.Reply.InReplyToTweetID)
+		fmt.Fprintf(w, `{"data": {"id": "%d", "text": "%s"}}`, len(replies), payload.Text)
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	thread, err := client.PostThread(context.Background(), []string{"first", "second"}, "100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !thread.Complete || thread.Posted != 2 || thread.Total != 2 {
+		t.Errorf("expected a complete thread, got %+v", thread)
+	}
+	if !slices.Equal(replies, []string{"100", "1"}) {
+		t.Errorf("expected every tweet to reply to the previous one, got %v", replies)
+	}
+	if second := thread.Tweets[1]; second.Position != 1 || second.InReplyTo != "1" || second.URL != "https://x.com/i/status/2" {
+		t.Errorf("unexpected second tweet: %+v", second)
+	}
+
+	replies = nil
+	thread, err = client.PostThread(context.Background(), []string{"first", "second", "fails", "never"}, "")
+	if err == nil {
+		t.Fatalf("expected an error for the failed tweet")
+	}
+	if thread.Complete || thread.Posted != 2 || thread.Total != 4 || len(thread.Tweets) != 2 {
+		t.Errorf("expected the posted tweets to be kept, got %+v", thread)
+	}
+	if thread.FailedAt == nil || *thread.FailedAt != 2 || thread.Error == nil || thread.Error.StatusCode != http.StatusForbidden {
+		t.Errorf("expected failure at position 2 with its details, got %+v", thread)
+	}
+}
+
+func TestGetTweetByID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/2/tweets/1" {
+			w.Write([]byte(`{"data": {"id": "1", "text": "hello", "author_id": "9"}}`))
+			return
+		}
+		w.Write([]byte(`{"errors": [{"title": "Not Found Error", "detail": "Could not find tweet"}]}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	tweet, err := client.GetTweetByID(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tweet.Text != "hello" || tweet.AuthorID != "9" {
+		t.Errorf("unexpected tweet: %+v", tweet)
+	}
+
+	if _, err := client.GetTweetByID(context.Background(), "2"); err == nil {
+		t.Errorf("expected an error for a missing tweet")
+	}
+}
+
+func TestGetMeIsCached(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": {"id": "1", "name": "Me", "username": "me"}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	for range 3 {
+		me, err := client.GetMe(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if me.Username != "me" {
+			t.Errorf("expected username 'me', got '%s'", me.Username)
+		}
+
+		// Callers get a copy, so they can't alter the cached user
+		me.Username = "changed"
+	}
+
+	if requests != 1 {
+		t.Errorf("expected a single request, got %d", requests)
+	}
+}
+
+func TestGetMeProfile(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"data": {"id": "1", "username": "me", "description": "bio", "verified": true, "verified_type": "blue", "protected": true, "public_metrics": {"followers_count": 42}}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	profile, err := client.GetMeProfile(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Get("user.fields") != userProfileFields {
+		t.Errorf("expected user.fields '%s', got '%s'", userProfileFields, query.Get("user.fields"))
+	}
+	if profile.Description != "bio" || !profile.Verified || profile.VerifiedType != "blue" || !profile.Protected || profile.PublicMetrics == nil || profile.PublicMetrics.FollowersCount != 42 {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if userAgent := DefaultUserAgent("1.2.3"); userAgent != "twitter-mcp/1.2.3" {
+		t.Errorf("expected 'twitter-mcp/1.2.3', got '%s'", userAgent)
+	}
+	if userAgent := DefaultUserAgent(""); userAgent != "twitter-mcp" {
+		t.Errorf("expected 'twitter-mcp' without version, got '%s'", userAgent)
+	}
+
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Write([]byte(`{"data": {"id": "1", "name": "Me", "username": "me"}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+	client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+	client.SetUserAgent("custom-agent/1.0")
+
+	client.doRequestV2OAuth1(context.Background(), "GET", "/users/me", nil)
+	client.doRequestV2(context.Background(), "GET", "/users/me", nil)
+	client.doRequestV1(context.Background(), "GET", "/account/settings.json", nil)
+	client.doRequestV1Form(context.Background(), "/media/upload.json", url.Values{})
+
+	if len(userAgents) != 4 {
+		t.Fatalf("expected 4 requests, got %d", len(userAgents))
+	}
+	for i, userAgent := range userAgents {
+		if userAgent != "custom-agent/1.0" {
+			t.Errorf("expected request %d to send the configured User-Agent, got '%s'", i, userAgent)
+		}
+	}
+}
+
+func TestRequestCancelledByContext(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetTrends(ctx, 1)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected a cancelled request, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the in-flight request to be aborted")
+	}
+}
+
+func TestEmptyResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// Empty 200 for any other request
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+	client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+
+	if err := client.DeleteTweet(context.Background(), "123"); err != nil {
+		t.Errorf("expected a 204 to succeed, got: %v", err)
+	}
+
+	timeline, err := client.GetTimeline(context.Background(), "1", 10, TimelineOptions{})
+	if err != nil {
+		t.Errorf("expected an empty 200 to succeed, got: %v", err)
+	} else if len(timeline.Data) != 0 {
+		t.Errorf("expected no tweets, got %d", len(timeline.Data))
+	}
+
+	if _, err := client.PostTweet(context.Background(), "hello", "", PostTweetOptions{}); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse posting without a tweet back, got: %v", err)
+	}
+	if _, err := client.GetMe(context.Background()); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse without a user back, got: %v", err)
+	}
+}
+
+func TestSortTweetsByMetric(t *testing.T) {
+	tweets := []Tweet{
+		{ID: "none"},
+		{ID: "liked", PublicMetrics: &PublicMetrics{LikeCount: 50, RetweetCount: 1}},
+		{ID: "shared", PublicMetrics: &PublicMetrics{LikeCount: 10, RetweetCount: 30, ReplyCount: 20}},
+	}
+
+	if err := SortTweetsByMetric(tweets, "likes"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tweets[0].ID != "liked" || tweets[2].ID != "none" {
+		t.Errorf("unexpected order by likes: %s, %s, %s", tweets[0].ID, tweets[1].ID, tweets[2].ID)
+	}
+
+	SortTweetsByMetric(tweets, "engagement")
+	if tweets[0].ID != "shared" {
+		t.Errorf("expected 'shared' first by engagement, got '%s'", tweets[0].ID)
+	}
+
+	if err := SortTweetsByMetric(tweets, "views"); err == nil {
+		t.Errorf("expected error for unknown metric")
+	}
+}
+
+func TestGetQuoteTweetsPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pagination_token") == "" {
+			w.Write([]byte(`{"data": [{"id": "1", "author_id": "a"}, {"id": "2", "author_id": "b"}],
+				"includes": {"users": [{"id": "a"}, {"id": "b"}]}, "meta": {"result_count": 2, "next_token": "page2"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "3", "author_id": "a"}], "includes": {"users": [{"id": "a"}]}, "meta": {"result_count": 1}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+
+	tweets, err := client.GetQuoteTweets(context.Background(), "100", 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tweets.Meta.ResultCount != 3 || len(tweets.Data) != 3 {
+		t.Errorf("expected 3 tweets across pages, got %d", len(tweets.Data))
+	}
+	if len(tweets.Includes.Users) != 2 {
+		t.Errorf("expected authors without duplicates, got %+v", tweets.Includes.Users)
+	}
+	if tweets.Meta.NextToken != "" {
+		t.Errorf("expected no next token after the last page, got '%s'", tweets.Meta.NextToken)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+// EngagementStats is an approximate engagement rate of an account, from a sample of its recent tweets
+type EngagementStats struct {
+	TweetsAnalyzed int     `json:"tweets_analyzed"`
+	AvgEngagement  float64 `json:"avg_engagement"` // Likes, retweets, replies and quotes per tweet
+
+	// EngagementRate is AvgEngagement divided by the followers, e.g. 0.02 is 2%.
+	// Missing for accounts without followers or without tweets to analyze
+	EngagementRate *float64 `json:"engagement_rate,omitempty"`
+}
+
+// ComputeEngagement computes the engagement stats of an account with the given followers.
+// Tweets without public metrics are not counted
+func ComputeEngagement(tweets []Tweet, followers int) EngagementStats {
+	var stats EngagementStats
+	total := 0
+	for _, tweet := range tweets {
+		m := tweet.PublicMetrics
+		if m == nil {
+			continue
+		}
+		stats.TweetsAnalyzed++
+		total += m.LikeCount + m.RetweetCount + m.ReplyCount + m.QuoteCount
+	}
+
+	if stats.TweetsAnalyzed == 0 {
+		return stats
+	}
+	stats.AvgEngagement = float64(total) / float64(stats.TweetsAnalyzed)
+
+	if followers > 0 {
+		rate := stats.AvgEngagement / float64(followers)
+		stats.EngagementRate = &rate
+	}
+	return stats
+}
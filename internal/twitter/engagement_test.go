@@ -0,0 +1,43 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import "testing"
+
+func TestComputeEngagement(t *testing.T) {
+	tweets := []Tweet{
+		{ID: "1", PublicMetrics: &PublicMetrics{LikeCount: 10, RetweetCount: 5, ReplyCount: 3, QuoteCount: 2}},
+		{ID: "2", PublicMetrics: &PublicMetrics{LikeCount: 4, RetweetCount: 1}},
+		{ID: "3"},
+	}
+
+	stats := ComputeEngagement(tweets, 100)
+	if stats.TweetsAnalyzed != 2 {
+		t.Errorf("expected tweets without metrics to be skipped, got %d analyzed", stats.TweetsAnalyzed)
+	}
+	if stats.AvgEngagement != 12.5 {
+		t.Errorf("expected average engagement 12.5, got %v", stats.AvgEngagement)
+	}
+	if stats.EngagementRate == nil || *stats.EngagementRate != 0.125 {
+		t.Errorf("expected engagement rate 0.125, got %v", stats.EngagementRate)
+	}
+
+	if stats := ComputeEngagement(tweets, 0); stats.EngagementRate != nil || stats.AvgEngagement != 12.5 {
+		t.Errorf("expected no rate without followers, got %+v", stats)
+	}
+	if stats := ComputeEngagement(nil, 100); stats.TweetsAnalyzed != 0 || stats.EngagementRate != nil {
+		t.Errorf("expected empty stats without tweets, got %+v", stats)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrNothingToUndo is returned when undoing a like, retweet or bookmark that doesn't exist
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// ErrEmptyResponse is returned when the API succeeds without returning the data the request should create or fetch
+var ErrEmptyResponse = errors.New("the API succeeded but returned no data")
+
+// APIError is returned by the client when the API answers with a non-2xx status.
+// Callers can get it with errors.As to branch on the status without parsing messages
+type APIError struct {
+	StatusCode int
+	Body       string
+
+	// Parsed is the decoded error body, nil when it is not a known envelope (e.g. an HTML error page)
+	Parsed *TwitterErrorEnvelope
+}
+
+// newAPIError builds the error of a non-2xx response, decoding its body
+func newAPIError(statusCode int, body string) *APIError {
+	return &APIError{StatusCode: statusCode, Body: body, Parsed: parseErrorEnvelope(body)}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// hasStatusCode checks whether an error was caused by an API response with any of the given status codes
+func hasStatusCode(err error, statusCodes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, statusCode := range statusCodes {
+		if apiErr.StatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// TwitterErrorEnvelope represents the error body returned by the API.
+// v2 returns problem details (title/detail/type) while v1.1 returns an 'errors' list
+type TwitterErrorEnvelope struct {
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Errors []struct {
+		Title   string `json:"title,omitempty"`
+		Detail  string `json:"detail,omitempty"`
+		Type    string `json:"type,omitempty"`
+		Message string `json:"message,omitempty"`
+		Code    int    `json:"code,omitempty"`
+	} `json:"errors,omitempty"`
+}
+
+// parseErrorEnvelope decodes an API error body. It returns nil when the body is not a known envelope
+func parseErrorEnvelope(body string) *TwitterErrorEnvelope {
+	var envelope TwitterErrorEnvelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil
+	}
+	if envelope.Title == "" && envelope.Detail == "" && len(envelope.Errors) == 0 {
+		return nil
+	}
+	return &envelope
+}
+
+// ErrorDetails represents a clean view of an error, ready to be shown to the caller
+type ErrorDetails struct {
+	StatusCode int    `json:"status_code,omitempty"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// GetErrorDetails converts an error into a short code and a human message.
+// For API errors, the message is taken from the error envelope instead of the raw body
+func GetErrorDetails(err error) ErrorDetails {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return ErrorDetails{Code: "error", Message: err.Error()}
+	}
+
+	details := ErrorDetails{
+		StatusCode: apiErr.StatusCode,
+		Code:       strings.ReplaceAll(strings.ToLower(http.StatusText(apiErr.StatusCode)), " ", "-"),
+		Message:    http.StatusText(apiErr.StatusCode),
+	}
+
+	// Errors built by hand may come without the parsed body
+	envelope := apiErr.Parsed
+	if envelope == nil {
+		envelope = parseErrorEnvelope(apiErr.Body)
+	}
+	if envelope != nil {
+		problemType, message := envelope.Type, firstNonEmpty(envelope.Detail, envelope.Title)
+		if len(envelope.Errors) > 0 {
+			problemType = firstNonEmpty(problemType, envelope.Errors[0].Type)
+			message = firstNonEmpty(message, envelope.Errors[0].Detail, envelope.Errors[0].Message, envelope.Errors[0].Title)
+		}
+
+		// Types look like 'https://api.twitter.com/2/problems/usage-capped'
+		if problemType != "" && problemType != "about:blank" {
+			details.Code = path.Base(problemType)
+		}
+		if message != "" {
+			details.Message = message
+		}
+	}
+
+	// Keep the context added by wrapping errors (e.g. 'failed to get user info: ')
+	if prefix := strings.TrimSuffix(err.Error(), apiErr.Error()); prefix != err.Error() {
+		details.Message = prefix + details.Message
+	}
+
+	return details
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHasStatusCode(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", newAPIError(403, "forbidden"))
+
+	if !hasStatusCode(err, 401, 403) {
+		t.Errorf("expected wrapped API error to match status 403")
+	}
+	if hasStatusCode(err, 404) {
+		t.Errorf("expected wrapped API error not to match status 404")
+	}
+	if hasStatusCode(fmt.Errorf("network error"), 403) {
+		t.Errorf("expected non-API error not to match any status")
+	}
+}
+
+func TestGetErrorDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorDetails
+	}{
+		{
+			name: "v2 problem details",
+			err: newAPIError(429, `{"title":"UsageCapExceeded","detail":"Usage cap exceeded: Monthly product cap",`+
+				`"type":"https://api.twitter.com/2/problems/usage-capped","status":429}`),
+			expected: ErrorDetails{StatusCode: 429, Code: "usage-capped", Message: "Usage cap exceeded: Monthly product cap"},
+		},
+		{
+			name:     "v2 problem with blank type",
+			err:      newAPIError(401, `{"title":"Unauthorized","type":"about:blank","status":401,"detail":"Unauthorized"}`),
+			expected: ErrorDetails{StatusCode: 401, Code: "unauthorized", Message: "Unauthorized"},
+		},
+		{
+			name:     "v1.1 errors list",
+			err:      newAPIError(403, `{"errors":[{"code":187,"message":"Status is a duplicate."}]}`),
+			expected: ErrorDetails{StatusCode: 403, Code: "forbidden", Message: "Status is a duplicate."},
+		},
+		{
+			name:     "unparseable body",
+			err:      newAPIError(502, `<html>Bad Gateway</html>`),
+			expected: ErrorDetails{StatusCode: 502, Code: "bad-gateway", Message: "Bad Gateway"},
+		},
+		{
+			name:     "wrapped API error keeps context",
+			err:      fmt.Errorf("failed to get user info: %w", newAPIError(404, `{"title":"Not Found Error","detail":"Could not find user"}`)),
+			expected: ErrorDetails{StatusCode: 404, Code: "not-found", Message: "failed to get user info: Could not find user"},
+		},
+		{
+			name:     "non API error",
+			err:      fmt.Errorf("list name is required"),
+			expected: ErrorDetails{Code: "error", Message: "list name is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GetErrorDetails(tt.err)
+			if result != tt.expected {
+				t.Errorf("GetErrorDetails() = %+v, expected %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUndoError(t *testing.T) {
+	notFound := newAPIError(404, `{"title": "Not Found Error"}`)
+	if err := undoError(notFound); !errors.Is(err, ErrNothingToUndo) || !hasStatusCode(err, 404) {
+		t.Errorf("expected ErrNothingToUndo keeping the API error, got %v", err)
+	}
+
+	forbidden := newAPIError(403, "")
+	if err := undoError(forbidden); errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected other errors untouched, got %v", err)
+	}
+
+	if undoError(nil) != nil {
+		t.Errorf("expected nil for success")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	err := fmt.Errorf("failed to get user: %w", newAPIError(404, `{"title": "Not Found Error", "detail": "Could not find user"}`))
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected a wrapped *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Parsed == nil || apiErr.Parsed.Detail != "Could not find user" {
+		t.Errorf("expected the status and the parsed body, got %+v", apiErr)
+	}
+
+	if html := newAPIError(502, "<html>Bad Gateway</html>"); html.Parsed != nil || html.Body != "<html>Bad Gateway</html>" {
+		t.Errorf("expected an unknown body to be kept raw only, got %+v", html)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Place represents a named location tweets can be tagged with
+type Place struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	PlaceType   string `json:"place_type"` // poi, neighborhood, city, admin or country
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code"`
+}
+
+// SearchPlaces searches places by name, to get IDs for PostTweetOptions.PlaceID (v1.1 API with OAuth 1.0a).
+// This is best-effort: the geo endpoints are legacy and not available on every account tier
+func (c *Client) SearchPlaces(ctx context.Context, query string) ([]Place, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	body, err := c.doRequestV1(ctx, "GET", "/geo/search.json?query="+url.QueryEscape(query), nil)
+	if hasStatusCode(err, http.StatusForbidden, http.StatusNotFound, http.StatusGone) {
+		return nil, fmt.Errorf("searching places is not supported for this account or API tier: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Result struct {
+			Places []Place `json:"places"`
+		} `json:"result"`
+	}
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse places response: %w", err)
+	}
+
+	if response.Result.Places == nil {
+		return []Place{}, nil
+	}
+	return response.Result.Places, nil
+}
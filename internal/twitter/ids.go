@@ -0,0 +1,121 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// tweetIDPattern matches tweet IDs (snowflakes, up to 19 digits)
+	tweetIDPattern = regexp.MustCompile(`^[0-9]{1,19}$`)
+
+	// usernamePattern matches Twitter handles: letters, digits and underscores, up to 15 characters
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+	// reservedURLPaths are first path segments of twitter.com/x.com URLs that are not profiles
+	reservedURLPaths = []string{"i", "home", "explore", "search", "hashtag", "intent", "messages", "notifications", "settings", "share"}
+
+	// tweetURLHosts are the hosts serving tweet pages
+	tweetURLHosts = []string{"twitter.com", "x.com", "mobile.twitter.com", "mobile.x.com", "www.twitter.com", "www.x.com"}
+)
+
+// ParseTweetID accepts a raw tweet ID or a twitter.com/x.com status URL, like
+// 'https://x.com/user/status/123', and returns the tweet ID
+func ParseTweetID(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("tweet ID is required")
+	}
+	if tweetIDPattern.MatchString(input) {
+		return input, nil
+	}
+
+	invalidErr := fmt.Errorf("'%s' is not a tweet ID or a twitter.com/x.com status URL", input)
+
+	// URLs without scheme, like 'x.com/user/status/123', are parsed as paths otherwise
+	if !strings.Contains(input, "://") {
+		input = "https://" + input
+	}
+
+	parsedURL, err := url.Parse(input)
+	if err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") {
+		return "", invalidErr
+	}
+	if !slices.Contains(tweetURLHosts, strings.ToLower(parsedURL.Hostname())) {
+		return "", invalidErr
+	}
+
+	// Paths look like '/user/status/123', '/user/statuses/123/photo/1' or '/i/web/status/123'
+	segments := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] != "status" && segments[i] != "statuses" {
+			continue
+		}
+		if tweetIDPattern.MatchString(segments[i+1]) {
+			return segments[i+1], nil
+		}
+	}
+
+	return "", invalidErr
+}
+
+// TweetURL returns the canonical URL of a tweet, which works without knowing its author
+func TweetURL(tweetID string) string {
+	return "https://x.com/i/status/" + tweetID
+}
+
+// NormalizeUsername accepts a Twitter handle in the usual shapes, like 'user', '@user' or a
+// profile URL like 'https://x.com/user', and returns the bare handle
+func NormalizeUsername(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("username is required")
+	}
+
+	invalidErr := fmt.Errorf("'%s' is not a valid username: use the handle, like 'user', '@user' or 'https://x.com/user'", input)
+
+	username := strings.TrimPrefix(input, "@")
+
+	if strings.Contains(username, "/") {
+		profileURL := username
+		if !strings.Contains(profileURL, "://") {
+			profileURL = "https://" + profileURL
+		}
+
+		parsedURL, err := url.Parse(profileURL)
+		if err != nil || (parsedURL.Scheme != "https" && parsedURL.Scheme != "http") {
+			return "", invalidErr
+		}
+		if !slices.Contains(tweetURLHosts, strings.ToLower(parsedURL.Hostname())) {
+			return "", invalidErr
+		}
+
+		// Profile and tweet URLs both start with the handle, like '/user' or '/user/status/123'
+		username = strings.Split(strings.Trim(parsedURL.Path, "/"), "/")[0]
+		if slices.Contains(reservedURLPaths, strings.ToLower(username)) {
+			return "", invalidErr
+		}
+	}
+
+	if !usernamePattern.MatchString(username) {
+		return "", invalidErr
+	}
+	return username, nil
+}
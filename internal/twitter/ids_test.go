@@ -0,0 +1,88 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"testing"
+)
+
+func TestParseTweetID(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{"1234567890", "1234567890", false},
+		{" 1234567890 ", "1234567890", false},
+		{"https://twitter.com/user/status/1234567890", "1234567890", false},
+		{"https://x.com/user/status/1234567890?s=20", "1234567890", false},
+		{"http://mobile.twitter.com/user/status/1234567890/photo/1", "1234567890", false},
+		{"x.com/user/status/1234567890", "1234567890", false},
+		{"https://WWW.X.COM/user/statuses/1234567890", "1234567890", false},
+		{"https://x.com/i/web/status/1234567890", "1234567890", false},
+		{"", "", true},
+		{"abc", "", true},
+		{"12345678901234567890", "", true},
+		{"https://x.com/user", "", true},
+		{"https://x.com/user/status/abc", "", true},
+		{"https://example.com/user/status/1234567890", "", true},
+		{"ftp://x.com/user/status/1234567890", "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseTweetID(tt.input)
+		if (err != nil) != tt.expectError {
+			t.Errorf("ParseTweetID(%q) error = %v, expectError %v", tt.input, err, tt.expectError)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("ParseTweetID(%q) = '%s', expected '%s'", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{"achetronic", "achetronic", false},
+		{" @achetronic ", "achetronic", false},
+		{"https://twitter.com/achetronic", "achetronic", false},
+		{"https://x.com/achetronic/", "achetronic", false},
+		{"x.com/achetronic?lang=en", "achetronic", false},
+		{"@https://x.com/achetronic", "achetronic", false},
+		{"https://x.com/achetronic/status/1234567890", "achetronic", false},
+		{"", "", true},
+		{"@", "", true},
+		{"not a user", "", true},
+		{"this_handle_is_too_long", "", true},
+		{"https://example.com/achetronic", "", true},
+		{"https://x.com/home", "", true},
+		{"https://x.com/", "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := NormalizeUsername(tt.input)
+		if (err != nil) != tt.expectError {
+			t.Errorf("NormalizeUsername(%q) error = %v, expectError %v", tt.input, err, tt.expectError)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("NormalizeUsername(%q) = '%s', expected '%s'", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// InactiveAccount is a followed account without tweets in the inactivity window
+type InactiveAccount struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+
+	// LastTweetAt is the date of the most recent tweet, missing for accounts that never tweeted
+	LastTweetAt  string `json:"last_tweet_at,omitempty"`
+	DaysInactive int    `json:"days_inactive,omitempty"`
+	NeverTweeted bool   `json:"never_tweeted,omitempty"`
+}
+
+// InactiveFollowing is the outcome of checking the activity of followed accounts
+type InactiveFollowing struct {
+	Accounts     []InactiveAccount `json:"accounts"`
+	ResultCount  int               `json:"result_count"`
+	InactiveDays int               `json:"inactive_days"`
+	Checked      int               `json:"checked"`
+
+	// Failed lists the accounts whose tweets could not be read, e.g. protected ones or after a rate limit
+	Failed []BatchItemResult `json:"failed,omitempty"`
+
+	// Truncated is set when the user follows more accounts than checked
+	Truncated bool `json:"truncated"`
+}
+
+// FindInactiveFollowing checks the latest tweet of up to maxUsers accounts followed by the user, most recently
+// followed first, and returns the ones that did not tweet in the last inactiveDays, least active first.
+// Every account costs one request, except those without tweets at all (v2 API)
+func (c *Client) FindInactiveFollowing(ctx context.Context, userID string, inactiveDays, maxUsers int) (*InactiveFollowing, error) {
+	following, err := c.GetFollowing(ctx, userID, maxUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -inactiveDays)
+	result := &InactiveFollowing{
+		Accounts:     []InactiveAccount{},
+		InactiveDays: inactiveDays,
+		Truncated:    following.Meta.NextToken != "",
+	}
+
+	usersByName := make(map[string]UserProfile, len(following.Data))
+	var usernames []string
+	for _, user := range following.Data {
+		if user.PublicMetrics != nil && user.PublicMetrics.TweetCount == 0 {
+			result.Accounts = append(result.Accounts, InactiveAccount{ID: user.ID, Username: user.Username, Name: user.Name, NeverTweeted: true})
+			continue
+		}
+		usersByName[user.Username] = user
+		usernames = append(usernames, user.Username)
+	}
+
+	var mutex sync.Mutex
+	summary := runBatch(usernames, batchConcurrency, func(username string) error {
+		user := usersByName[username]
+
+		// 5 is the lowest page size of the endpoint
+		tweets, err := c.GetUserTweets(ctx, user.ID, 5)
+		if err != nil {
+			return err
+		}
+
+		account := InactiveAccount{ID: user.ID, Username: user.Username, Name: user.Name}
+		if len(tweets.Data) == 0 {
+			account.NeverTweeted = true
+		} else {
+			lastTweetAt, err := time.Parse(time.RFC3339, tweets.Data[0].CreatedAt)
+			if err != nil || lastTweetAt.After(cutoff) {
+				return nil
+			}
+			account.LastTweetAt = tweets.Data[0].CreatedAt
+			account.DaysInactive = int(now.Sub(lastTweetAt).Hours() / 24)
+		}
+
+		mutex.Lock()
+		result.Accounts = append(result.Accounts, account)
+		mutex.Unlock()
+		return nil
+	})
+
+	result.Checked = len(following.Data) - summary.Failed
+	for _, item := range summary.Results {
+		if !item.Success {
+			result.Failed = append(result.Failed, item)
+		}
+	}
+
+	// Accounts that never tweeted go first, then the longest inactive
+	slices.SortFunc(result.Accounts, func(a, b InactiveAccount) int {
+		if a.NeverTweeted != b.NeverTweeted {
+			if a.NeverTweeted {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(b.DaysInactive, a.DaysInactive), cmp.Compare(a.Username, b.Username))
+	})
+	result.ResultCount = len(result.Accounts)
+
+	return result, nil
+}
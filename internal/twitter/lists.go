@@ -0,0 +1,104 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	// maxListNameLength is the max length allowed by Twitter for list names
+	maxListNameLength = 25
+)
+
+// List represents a Twitter list
+type List struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateList creates a new list owned by the authenticated user (v2 API with OAuth 1.0a user context)
+func (c *Client) CreateList(ctx context.Context, name string, private bool) (*List, error) {
+	if name == "" {
+		return nil, fmt.Errorf("list name is required")
+	}
+	if utf8.RuneCountInString(name) > maxListNameLength {
+		return nil, fmt.Errorf("list name is too long: max %d characters", maxListNameLength)
+	}
+
+	payload := map[string]interface{}{
+		"name":    name,
+		"private": private,
+	}
+
+	body, err := c.doRequestV2OAuth1(ctx, "POST", "/lists", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data List `json:"data"`
+	}
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse list response: %w", err)
+	}
+	if response.Data.ID == "" {
+		return nil, ErrEmptyResponse
+	}
+
+	return &response.Data, nil
+}
+
+// AddListMember adds a user to a list (v2 API with OAuth 1.0a user context)
+func (c *Client) AddListMember(ctx context.Context, listID, userID string) error {
+	payload := map[string]string{
+		"user_id": userID,
+	}
+
+	_, err := c.doRequestV2OAuth1(ctx, "POST", "/lists/"+listID+"/members", payload)
+	return err
+}
+
+// RemoveListMember removes a user from a list (v2 API with OAuth 1.0a user context)
+func (c *Client) RemoveListMember(ctx context.Context, listID, userID string) error {
+	_, err := c.doRequestV2OAuth1(ctx, "DELETE", "/lists/"+listID+"/members/"+userID, nil)
+	return err
+}
+
+// GetListTweets gets recent tweets from the members of a list (v2 API)
+func (c *Client) GetListTweets(ctx context.Context, listID string, maxResults int) (*TweetsResponse, error) {
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+	if maxResults > 100 {
+		maxResults = 100
+	}
+
+	endpoint := fmt.Sprintf("/lists/%s/tweets?max_results=%d&tweet.fields=created_at,author_id,public_metrics,entities&expansions=author_id", listID, maxResults)
+
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TweetsResponse
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse list tweets: %w", err)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// mediaFormat describes a media type accepted by the media upload endpoint
+type mediaFormat struct {
+	category string
+	maxSize  int
+}
+
+// supportedMediaFormats are the media types UploadMedia can send, by MIME type.
+// Videos are left out, as they need the chunked upload
+var supportedMediaFormats = map[string]mediaFormat{
+	"image/jpeg": {category: "tweet_image", maxSize: 5 << 20},
+	"image/png":  {category: "tweet_image", maxSize: 5 << 20},
+	"image/webp": {category: "tweet_image", maxSize: 5 << 20},
+	"image/gif":  {category: "tweet_gif", maxSize: 15 << 20},
+}
+
+// detectMediaType sniffs the media type of the data and checks it can be uploaded.
+// It returns the MIME type and the media category expected by the upload endpoint
+func detectMediaType(data []byte) (string, string, error) {
+	if len(data) == 0 {
+		return "", "", fmt.Errorf("media is empty")
+	}
+
+	mediaType := http.DetectContentType(data)
+
+	format, supported := supportedMediaFormats[mediaType]
+	if !supported {
+		if mediaType == "video/mp4" || mediaType == "video/webm" || mediaType == "video/avi" {
+			return "", "", fmt.Errorf("unsupported media type '%s': videos are not supported yet, only JPEG, PNG, WEBP and GIF images", mediaType)
+		}
+		return "", "", fmt.Errorf("unsupported media type '%s': only JPEG, PNG, WEBP and GIF images are supported", mediaType)
+	}
+
+	if len(data) > format.maxSize {
+		return "", "", fmt.Errorf("media is too large for '%s': %d bytes, max %d bytes", mediaType, len(data), format.maxSize)
+	}
+
+	return mediaType, format.category, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectMediaType(t *testing.T) {
+	pngHeader := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHeader := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	gifHeader := []byte("GIF89a\x01\x00\x01\x00")
+	mp4Header := []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+
+	tests := []struct {
+		name             string
+		data             []byte
+		expectedType     string
+		expectedCategory string
+		expectError      bool
+	}{
+		{"png", pngHeader, "image/png", "tweet_image", false},
+		{"jpeg", jpegHeader, "image/jpeg", "tweet_image", false},
+		{"gif", gifHeader, "image/gif", "tweet_gif", false},
+		{"mp4 video", mp4Header, "", "", true},
+		{"text", []byte("just some text"), "", "", true},
+		{"empty", nil, "", "", true},
+		{"png too large", append(pngHeader, bytes.Repeat([]byte{0}, 5<<20)...), "", "", true},
+		{"gif under its own limit", append(gifHeader, bytes.Repeat([]byte{0}, 6<<20)...), "image/gif", "tweet_gif", false},
+	}
+
+	for _, tt := range tests {
+		mediaType, category, err := detectMediaType(tt.data)
+		if (err != nil) != tt.expectError {
+			t.Errorf("%s: error = %v, expectError %v", tt.name, err, tt.expectError)
+			continue
+		}
+		if mediaType != tt.expectedType || category != tt.expectedCategory {
+			t.Errorf("%s: got '%s' (%s), expected '%s' (%s)", tt.name, mediaType, category, tt.expectedType, tt.expectedCategory)
+		}
+	}
+}
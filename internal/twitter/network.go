@@ -0,0 +1,58 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import "context"
+
+// GetFollowers gets the followers of a user, following pagination up to maxResults (v2 API)
+func (c *Client) GetFollowers(ctx context.Context, userID string, maxResults int) (*UsersResponse, error) {
+	return c.getUsersPaginated(ctx, "/users/"+userID+"/followers", maxResults)
+}
+
+// GetFollowing gets the users followed by a user, following pagination up to maxResults (v2 API)
+func (c *Client) GetFollowing(ctx context.Context, userID string, maxResults int) (*UsersResponse, error) {
+	return c.getUsersPaginated(ctx, "/users/"+userID+"/following", maxResults)
+}
+
+// UsersOverlap represents the users found in two users lists
+type UsersOverlap struct {
+	Users       []UserProfile `json:"users"`
+	ResultCount int           `json:"result_count"`
+
+	// Truncated is set when any of the lists had more pages than fetched,
+	// so the overlap may be missing some users
+	Truncated bool `json:"truncated"`
+}
+
+// IntersectUsers returns the users present in both lists, in the order of the first one
+func IntersectUsers(a, b *UsersResponse) *UsersOverlap {
+	inB := make(map[string]bool, len(b.Data))
+	for _, user := range b.Data {
+		inB[user.ID] = true
+	}
+
+	overlap := &UsersOverlap{
+		Users:     []UserProfile{},
+		Truncated: a.Meta.NextToken != "" || b.Meta.NextToken != "",
+	}
+	for _, user := range a.Data {
+		if inB[user.ID] {
+			overlap.Users = append(overlap.Users, user)
+		}
+	}
+	overlap.ResultCount = len(overlap.Users)
+
+	return overlap
+}
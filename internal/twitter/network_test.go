@@ -0,0 +1,41 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"testing"
+)
+
+func TestIntersectUsers(t *testing.T) {
+	a := &UsersResponse{Data: []UserProfile{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	b := &UsersResponse{Data: []UserProfile{{ID: "3"}, {ID: "1"}, {ID: "4"}}}
+
+	overlap := IntersectUsers(a, b)
+	if overlap.ResultCount != 2 || overlap.Users[0].ID != "1" || overlap.Users[1].ID != "3" {
+		t.Errorf("expected users 1 and 3 in the order of the first list, got %+v", overlap.Users)
+	}
+	if overlap.Truncated {
+		t.Errorf("expected complete lists not to be truncated")
+	}
+
+	b.Meta.NextToken = "next"
+	if !IntersectUsers(a, b).Truncated {
+		t.Errorf("expected truncated overlap when a list has more pages")
+	}
+
+	if empty := IntersectUsers(a, &UsersResponse{}); empty.Users == nil || empty.ResultCount != 0 {
+		t.Errorf("expected empty, non-nil users, got %+v", empty)
+	}
+}
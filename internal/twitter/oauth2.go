@@ -0,0 +1,237 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oauth2TokenPath is where OAuth 2.0 user tokens are refreshed, under the v2 API root
+	oauth2TokenPath = "/oauth2/token"
+
+	// oauth2RefreshMargin is how long before its expiry an access token is refreshed
+	oauth2RefreshMargin = time.Minute
+)
+
+// OAuth2Credentials are the OAuth 2.0 user context credentials, obtained beforehand
+// through the Authorization Code with PKCE flow with the 'offline.access' scope
+type OAuth2Credentials struct {
+	ClientID     string
+	ClientSecret string // Only for confidential clients
+	AccessToken  string
+	RefreshToken string
+
+	// TokenFile keeps the tokens across restarts. X rotates refresh tokens on every refresh,
+	// so without it the configured refresh token stops working after the first refresh
+	TokenFile string
+}
+
+// oauth2TokenFileContent is the content of the OAuth 2.0 token file
+type oauth2TokenFileContent struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+}
+
+// oauth2TokenResponse is the response of the OAuth 2.0 token endpoint
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// oauth2Session holds the OAuth 2.0 user tokens, refreshed as needed
+type oauth2Session struct {
+	mutex       sync.Mutex
+	credentials OAuth2Credentials
+
+	// expiresAt is when the access token expires. Zero when unknown, e.g. for configured tokens
+	expiresAt time.Time
+}
+
+// EnableOAuth2 makes v2 user context requests use OAuth 2.0 user tokens instead of OAuth 1.0a.
+// When the token file exists, its tokens take precedence over the given ones
+func (c *Client) EnableOAuth2(credentials OAuth2Credentials) error {
+	if credentials.ClientID == "" {
+		return fmt.Errorf("OAuth 2.0 client ID is required")
+	}
+
+	var expiresAt time.Time
+	if credentials.TokenFile != "" {
+		fileBytes, err := os.ReadFile(credentials.TokenFile)
+		switch {
+		case err == nil:
+			var content oauth2TokenFileContent
+			if err := json.Unmarshal(fileBytes, &content); err != nil {
+				return fmt.Errorf("failed to parse OAuth 2.0 token file: %w", err)
+			}
+			credentials.AccessToken = content.AccessToken
+			credentials.RefreshToken = content.RefreshToken
+			expiresAt = content.ExpiresAt
+
+		case !os.IsNotExist(err):
+			return fmt.Errorf("failed to read OAuth 2.0 token file: %w", err)
+		}
+	}
+
+	if credentials.RefreshToken == "" {
+		return fmt.Errorf("OAuth 2.0 refresh token is required, in config or in the token file")
+	}
+
+	c.oauth2 = &oauth2Session{credentials: credentials, expiresAt: expiresAt}
+	return nil
+}
+
+// doRequestV2OAuth2 performs an HTTP request to the Twitter v2 API using an OAuth 2.0 user token.
+// Tokens about to expire are refreshed beforehand. When the token is rejected anyway,
+// e.g. because its expiry is unknown, it is refreshed and the request retried once
+func (c *Client) doRequestV2OAuth2(ctx context.Context, method, endpoint string, jsonBody []byte) ([]byte, error) {
+	c.oauth2.mutex.Lock()
+	accessToken := c.oauth2.credentials.AccessToken
+	expiresAt := c.oauth2.expiresAt
+	c.oauth2.mutex.Unlock()
+
+	if accessToken == "" || (!expiresAt.IsZero() && time.Until(expiresAt) < oauth2RefreshMargin) {
+		var err error
+		accessToken, err = c.refreshOAuth2Token(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	respBody, err := c.doRequestV2WithToken(ctx, method, endpoint, jsonBody, accessToken)
+	if !hasStatusCode(err, http.StatusUnauthorized) {
+		return respBody, err
+	}
+
+	accessToken, err = c.refreshOAuth2Token(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.doRequestV2WithToken(ctx, method, endpoint, jsonBody, accessToken)
+}
+
+// doRequestV2WithToken performs an HTTP request to the Twitter v2 API with the given bearer token
+func (c *Client) doRequestV2WithToken(ctx context.Context, method, endpoint string, jsonBody []byte, token string) ([]byte, error) {
+	return c.doWithRetry(ctx, method, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURLv2+endpoint, newRequestBody(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+
+		return doHTTPRequest(c.httpClient, req)
+	})
+}
+
+// refreshOAuth2Token exchanges the refresh token for new tokens, and returns the new access token.
+// If the stale token was already replaced by a concurrent refresh, the current one is returned instead
+func (c *Client) refreshOAuth2Token(ctx context.Context, staleAccessToken string) (string, error) {
+	c.oauth2.mutex.Lock()
+	defer c.oauth2.mutex.Unlock()
+
+	credentials := &c.oauth2.credentials
+	if credentials.AccessToken != staleAccessToken {
+		return credentials.AccessToken, nil
+	}
+
+	params := url.Values{}
+	params.Set("grant_type", "refresh_token")
+	params.Set("refresh_token", credentials.RefreshToken)
+	params.Set("client_id", credentials.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.oauth2TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.userAgent)
+	if credentials.ClientSecret != "" {
+		req.SetBasicAuth(credentials.ClientID, credentials.ClientSecret)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh OAuth 2.0 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to refresh OAuth 2.0 token: %w", newAPIError(resp.StatusCode, string(respBody)))
+	}
+
+	var tokens oauth2TokenResponse
+	if err := json.Unmarshal(respBody, &tokens); err != nil {
+		return "", fmt.Errorf("failed to parse OAuth 2.0 token response: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return "", fmt.Errorf("OAuth 2.0 token response has no access token")
+	}
+
+	credentials.AccessToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		credentials.RefreshToken = tokens.RefreshToken
+	}
+
+	c.oauth2.expiresAt = time.Time{}
+	if tokens.ExpiresIn > 0 {
+		c.oauth2.expiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	}
+
+	if credentials.TokenFile != "" {
+		if err := saveOAuth2TokenFile(credentials, c.oauth2.expiresAt); err != nil {
+			return "", err
+		}
+	}
+
+	return credentials.AccessToken, nil
+}
+
+// saveOAuth2TokenFile stores the current tokens, readable only by the owner
+func saveOAuth2TokenFile(credentials *OAuth2Credentials, expiresAt time.Time) error {
+	fileBytes, err := json.Marshal(oauth2TokenFileContent{
+		AccessToken:  credentials.AccessToken,
+		RefreshToken: credentials.RefreshToken,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal OAuth 2.0 tokens: %w", err)
+	}
+
+	if err := os.WriteFile(credentials.TokenFile, fileBytes, 0o600); err != nil {
+		return fmt.Errorf("failed to write OAuth 2.0 token file: %w", err)
+	}
+	return nil
+}
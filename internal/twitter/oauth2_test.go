@@ -0,0 +1,169 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnableOAuth2PrefersTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens.json")
+	os.WriteFile(tokenFile, []byte(`{"access_token": "file-access", "refresh_token": "file-refresh"}`), 0o600)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	err := client.EnableOAuth2(OAuth2Credentials{
+		ClientID:     "client",
+		AccessToken:  "config-access",
+		RefreshToken: "config-refresh",
+		TokenFile:    tokenFile,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.oauth2.credentials.AccessToken != "file-access" || client.oauth2.credentials.RefreshToken != "file-refresh" {
+		t.Errorf("expected tokens from file, got %+v", client.oauth2.credentials)
+	}
+
+	if err := client.EnableOAuth2(OAuth2Credentials{ClientID: "client"}); err == nil {
+		t.Errorf("expected error without refresh token")
+	}
+}
+
+func TestRefreshOAuth2Token(t *testing.T) {
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		r.ParseForm()
+
+		user, password, _ := r.BasicAuth()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old-refresh" ||
+			user != "client" || password != "client-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type": "bearer", "access_token": "new-access", "refresh_token": "new-refresh"}`))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "tokens.json")
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth2TokenURL = server.URL
+	client.EnableOAuth2(OAuth2Credentials{
+		ClientID:     "client",
+		ClientSecret: "client-secret",
+		AccessToken:  "old-access",
+		RefreshToken: "old-refresh",
+		TokenFile:    tokenFile,
+	})
+
+	accessToken, err := client.refreshOAuth2Token(context.Background(), "old-access")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accessToken != "new-access" {
+		t.Errorf("expected new access token, got '%s'", accessToken)
+	}
+
+	// A request that failed with the stale token must not refresh again
+	accessToken, err = client.refreshOAuth2Token(context.Background(), "old-access")
+	if err != nil || accessToken != "new-access" || refreshes != 1 {
+		t.Errorf("expected current token without refreshing again, got '%s' after %d refreshes (error: %v)", accessToken, refreshes, err)
+	}
+
+	// Rotated tokens are persisted for the next start
+	var saved oauth2TokenFileContent
+	fileBytes, _ := os.ReadFile(tokenFile)
+	json.Unmarshal(fileBytes, &saved)
+	if saved.AccessToken != "new-access" || saved.RefreshToken != "new-refresh" {
+		t.Errorf("expected rotated tokens in file, got %+v", saved)
+	}
+}
+
+// redirectTransport sends every request to the given test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDoRequestV2OAuth2Refresh(t *testing.T) {
+	refreshes, apiCalls := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/2/oauth2/token" {
+			refreshes++
+			w.Write([]byte(`{"access_token": "new-access", "refresh_token": "new-refresh", "expires_in": 7200}`))
+			return
+		}
+
+		apiCalls++
+		if r.Header.Get("Authorization") != "Bearer new-access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	newOAuth2Client := func() *Client {
+		client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+		client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+		client.EnableOAuth2(OAuth2Credentials{
+			ClientID:     "client",
+			AccessToken:  "old-access",
+			RefreshToken: "old-refresh",
+		})
+		return client
+	}
+
+	// Expired tokens are refreshed on 401, and the request retried once
+	client := newOAuth2Client()
+	if _, err := client.doRequestV2OAuth2(context.Background(), "GET", "/users/me", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes != 1 || apiCalls != 2 {
+		t.Errorf("expected 1 refresh and 2 API calls, got %d and %d", refreshes, apiCalls)
+	}
+	if client.oauth2.expiresAt.IsZero() {
+		t.Errorf("expected expiry to be tracked after refresh")
+	}
+
+	// Tokens about to expire are refreshed before the request
+	client = newOAuth2Client()
+	client.oauth2.expiresAt = time.Now().Add(10 * time.Second)
+	if _, err := client.doRequestV2OAuth2(context.Background(), "GET", "/users/me", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes != 2 || apiCalls != 3 {
+		t.Errorf("expected a refresh before a single API call, got %d refreshes and %d API calls", refreshes, apiCalls)
+	}
+}
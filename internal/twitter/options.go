@@ -0,0 +1,204 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dghubble/oauth1"
+)
+
+// Option customizes a client built with NewClientWithOptions
+type Option func(*clientOptions)
+
+// clientOptions collects the options before building the client
+type clientOptions struct {
+	apiKey            string
+	apiKeySecret      string
+	accessToken       string
+	accessTokenSecret string
+	bearerToken       string
+
+	baseURL   string
+	baseURLv1 string
+	baseURLv2 string
+
+	timeout    time.Duration
+	proxyURL   *url.URL
+	userAgent  string
+	httpClient *http.Client
+
+	trendsCacheTTL time.Duration
+	retry          RetryPolicy
+}
+
+// WithCredentials sets the OAuth 1.0a credentials, used for user context requests
+func WithCredentials(apiKey, apiKeySecret, accessToken, accessTokenSecret string) Option {
+	return func(o *clientOptions) {
+		o.apiKey, o.apiKeySecret = apiKey, apiKeySecret
+		o.accessToken, o.accessTokenSecret = accessToken, accessTokenSecret
+	}
+}
+
+// WithBearerToken sets the OAuth 2.0 app-only token, used for v2 read requests
+func WithBearerToken(bearerToken string) Option {
+	return func(o *clientOptions) {
+		o.bearerToken = bearerToken
+	}
+}
+
+// WithBaseURL replaces 'https://api.twitter.com', e.g. with the URL of an httptest.Server
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithBaseURLs replaces the roots of each API version ('<base URL>/1.1' and '<base URL>/2'),
+// for proxies or mirrors serving them apart. Empty values keep the default
+func WithBaseURLs(baseURLv1, baseURLv2 string) Option {
+	return func(o *clientOptions) {
+		o.baseURLv1, o.baseURLv2 = baseURLv1, baseURLv2
+	}
+}
+
+// WithTimeout bounds every request. Without it, only bearer token requests are bounded, to 30s
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithProxy sends every request through an HTTP, HTTPS or SOCKS5 proxy.
+// Custom round trippers other than *http.Transport are left untouched
+func WithProxy(proxyURL *url.URL) Option {
+	return func(o *clientOptions) {
+		o.proxyURL = proxyURL
+	}
+}
+
+// WithUserAgent sets the User-Agent sent on every request, see SetUserAgent
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithHTTPClient sets the client every request is built on, for all the authentication methods.
+// Its transport and timeout are kept unless WithProxy or WithTimeout are also set
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTrendsCacheTTL sets how long trends are cached per location. Zero disables the cache
+func WithTrendsCacheTTL(ttl time.Duration) Option {
+	return func(o *clientOptions) {
+		o.trendsCacheTTL = ttl
+	}
+}
+
+// WithRetry sets how requests answered with 429 or 5xx are retried.
+// Unset fields keep the defaults: DefaultRetryMaxAttempts and DefaultRetryBaseDelay
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// NewClientWithOptions creates a new Twitter client. Without options it talks to the real API with no credentials
+func NewClientWithOptions(opts ...Option) *Client {
+	options := clientOptions{userAgent: userAgentProduct, trendsCacheTTL: DefaultTrendsCacheTTL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.retry.MaxAttempts <= 0 {
+		options.retry.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if options.retry.BaseDelay <= 0 {
+		options.retry.BaseDelay = DefaultRetryBaseDelay
+	}
+
+	baseURL := strings.TrimSuffix(options.baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURLv1 := strings.TrimSuffix(options.baseURLv1, "/")
+	if baseURLv1 == "" {
+		baseURLv1 = baseURL + "/1.1"
+	}
+	baseURLv2 := strings.TrimSuffix(options.baseURLv2, "/")
+	if baseURLv2 == "" {
+		baseURLv2 = baseURL + "/2"
+	}
+
+	// Every request is built on a copy of the base client, so it can be tuned without touching the caller's one
+	baseClient := &http.Client{}
+	if options.httpClient != nil {
+		copied := *options.httpClient
+		baseClient = &copied
+	}
+	if options.proxyURL != nil {
+		baseClient.Transport = withProxy(baseClient.Transport, options.proxyURL)
+	}
+	if options.timeout > 0 {
+		baseClient.Timeout = options.timeout
+	}
+
+	httpClient := *baseClient
+	if httpClient.Timeout == 0 {
+		httpClient.Timeout = defaultTimeout
+	}
+
+	// Setup OAuth 1.0a for v1.1 API. The oauth1 package builds on the client found in the context
+	config := oauth1.NewConfig(options.apiKey, options.apiKeySecret)
+	token := oauth1.NewToken(options.accessToken, options.accessTokenSecret)
+	oauth1Client := config.Client(context.WithValue(oauth1.NoContext, oauth1.HTTPClient, baseClient), token)
+
+	return &Client{
+		oauth1Client:   oauth1Client,
+		bearerToken:    options.bearerToken,
+		httpClient:     &httpClient,
+		baseURLv1:      baseURLv1,
+		baseURLv2:      baseURLv2,
+		userAgent:      options.userAgent,
+		oauth2TokenURL: baseURLv2 + oauth2TokenPath,
+		trends:         trendsCache{ttl: options.trendsCacheTTL},
+		retry:          options.retry,
+	}
+}
+
+// withProxy returns a transport going through the proxy. Only *http.Transport values can be changed,
+// other round trippers are returned as they are
+func withProxy(roundTripper http.RoundTripper, proxyURL *url.URL) http.RoundTripper {
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return roundTripper
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return transport
+}
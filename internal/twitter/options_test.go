@@ -0,0 +1,128 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	client := NewClientWithOptions()
+	if client.baseURLv2 != defaultBaseURL+"/2" || client.httpClient.Timeout != defaultTimeout || client.userAgent != userAgentProduct {
+		t.Errorf("unexpected defaults: %s, %v, %s", client.baseURLv2, client.httpClient.Timeout, client.userAgent)
+	}
+
+	client = NewClientWithOptions(WithBaseURL("http://localhost:1234/"))
+	if client.baseURLv1 != "http://localhost:1234/1.1" || client.baseURLv2 != "http://localhost:1234/2" {
+		t.Errorf("unexpected base URLs: '%s', '%s'", client.baseURLv1, client.baseURLv2)
+	}
+	if client.oauth2TokenURL != "http://localhost:1234/2/oauth2/token" {
+		t.Errorf("unexpected token URL: '%s'", client.oauth2TokenURL)
+	}
+
+	client = NewClientWithOptions(
+		WithBaseURL("http://localhost:1234"),
+		WithBaseURLs("", "https://mirror.example.com/twitter/v2/"),
+		WithTimeout(5*time.Second),
+		WithUserAgent("bot/1.0"),
+	)
+	if client.baseURLv1 != "http://localhost:1234/1.1" || client.baseURLv2 != "https://mirror.example.com/twitter/v2" {
+		t.Errorf("expected per-version base URLs to win, got '%s', '%s'", client.baseURLv1, client.baseURLv2)
+	}
+	if client.oauth2TokenURL != "https://mirror.example.com/twitter/v2/oauth2/token" {
+		t.Errorf("expected tokens to be refreshed under the v2 root, got '%s'", client.oauth2TokenURL)
+	}
+	if client.httpClient.Timeout != 5*time.Second || client.userAgent != "bot/1.0" {
+		t.Errorf("expected timeout and user agent to be set, got %v and '%s'", client.httpClient.Timeout, client.userAgent)
+	}
+}
+
+// countingTransport counts the requests going through it
+type countingTransport struct {
+	requests *int
+	next     http.RoundTripper
+}
+
+func (ct countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*ct.requests++
+	return ct.next.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"data": {"id": "1", "text": "a"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "1", "text": "a"}]}`))
+	}))
+	defer server.Close()
+
+	requests := 0
+	client := NewClientWithOptions(
+		WithCredentials("key", "secret", "token", "tokenSecret"),
+		WithBearerToken("bearer"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: countingTransport{requests: &requests, next: http.DefaultTransport}}),
+	)
+
+	// One request signed with OAuth 1.0a and one with the bearer token
+	if _, err := client.PostTweet(context.Background(), "a", "", PostTweetOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.SearchTweets(context.Background(), "a", 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected both requests to go through the given client, got %d", requests)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxied requests carry the absolute URL of the target
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(`{"data": [{"id": "1", "text": "a"}]}`))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewClientWithOptions(
+		WithBearerToken("bearer"),
+		WithBaseURL("http://api.example.com"),
+		WithProxy(proxyURL),
+	)
+
+	if _, err := client.SearchTweets(context.Background(), "a", 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proxied) != 1 || !strings.HasPrefix(proxied[0], "http://api.example.com/2/") {
+		t.Errorf("expected the request to go through the proxy, got %v", proxied)
+	}
+
+	// Round trippers other than *http.Transport are not touched
+	custom := countingTransport{requests: new(int), next: http.DefaultTransport}
+	if withProxy(custom, proxyURL) != http.RoundTripper(custom) {
+		t.Errorf("expected custom round trippers to be kept")
+	}
+}
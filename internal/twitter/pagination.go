@@ -0,0 +1,101 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"net/http"
+)
+
+const (
+	// MaxPaginatedTweets is the hard cap of tweets collected across pages, to protect the quota
+	MaxPaginatedTweets = 500
+
+	// tweetsPageMaxResults is the max page size of the v2 tweet endpoints
+	tweetsPageMaxResults = 100
+)
+
+// PaginatedTweets represents tweets collected by following pagination tokens across several pages.
+// Meta.NextToken is kept when there are more tweets beyond the cap, unless tweets of the last page had to be dropped
+type PaginatedTweets struct {
+	TweetsResponse
+
+	Pages int `json:"pages"`
+
+	// RateLimited is true when a page after the first one hit the rate limit.
+	// The tweets collected so far are returned, and Meta.NextToken points to the failed page
+	RateLimited bool `json:"rate_limited,omitempty"`
+}
+
+// tweetsPageFetcher gets a page of tweets. An empty pagination token asks for the first page
+type tweetsPageFetcher func(pageSize int, paginationToken string) (*TweetsResponse, error)
+
+// collectTweetPages calls fetch page after page until maxTotal tweets are collected or there are no more pages.
+// Authors in includes are deduplicated. minPageSize is the lowest page size the endpoint accepts
+func collectTweetPages(maxTotal, minPageSize int, fetch tweetsPageFetcher) (*PaginatedTweets, error) {
+	if maxTotal <= 0 || maxTotal > MaxPaginatedTweets {
+		maxTotal = MaxPaginatedTweets
+	}
+
+	result := &PaginatedTweets{}
+	knownAuthors := make(map[string]bool)
+	var paginationToken string
+
+	for len(result.Data) < maxTotal {
+		pageSize := min(max(maxTotal-len(result.Data), minPageSize), tweetsPageMaxResults)
+
+		page, err := fetch(pageSize, paginationToken)
+		if err != nil {
+			// Do not waste the pages already paid for
+			if result.Pages > 0 && hasStatusCode(err, http.StatusTooManyRequests) {
+				result.RateLimited = true
+				result.Meta.NextToken = paginationToken
+				break
+			}
+			return nil, err
+		}
+		result.Pages++
+
+		result.Data = append(result.Data, page.Data...)
+		for _, author := range page.Includes.Users {
+			if !knownAuthors[author.ID] {
+				knownAuthors[author.ID] = true
+				result.Includes.Users = append(result.Includes.Users, author)
+			}
+		}
+
+		if result.Meta.NewestID == "" {
+			result.Meta.NewestID = page.Meta.NewestID
+		}
+		if page.Meta.OldestID != "" {
+			result.Meta.OldestID = page.Meta.OldestID
+		}
+		result.Meta.NextToken = page.Meta.NextToken
+
+		paginationToken = page.Meta.NextToken
+		if paginationToken == "" || len(page.Data) == 0 {
+			break
+		}
+	}
+
+	// The last page can go past maxTotal when it was asked for minPageSize tweets. Its next token would skip
+	// the dropped tweets, so it is cleared and oldest_id points to the last kept tweet instead
+	if len(result.Data) > maxTotal {
+		result.Data = result.Data[:maxTotal]
+		result.Meta.NextToken = ""
+		result.Meta.OldestID = result.Data[maxTotal-1].ID
+	}
+	result.Meta.ResultCount = len(result.Data)
+	return result, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeTweetPages serves pages of tweets numbered from 1, with next tokens while there are more
+func fakeTweetPages(total int, pageSizes *[]int) tweetsPageFetcher {
+	return func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		*pageSizes = append(*pageSizes, pageSize)
+
+		first := 0
+		if paginationToken != "" {
+			fmt.Sscanf(paginationToken, "from-%d", &first)
+		}
+
+		page := &TweetsResponse{}
+		for i := first; i < min(first+pageSize, total); i++ {
+			page.Data = append(page.Data, Tweet{ID: fmt.Sprint(i + 1), AuthorID: "author"})
+		}
+		page.Includes.Users = []User{{ID: "author"}}
+		if first+pageSize < total {
+			page.Meta.NextToken = fmt.Sprintf("from-%d", first+pageSize)
+		}
+		return page, nil
+	}
+}
+
+func TestCollectTweetPages(t *testing.T) {
+	var pageSizes []int
+	result, err := collectTweetPages(250, 1, fakeTweetPages(1000, &pageSizes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Meta.ResultCount != 250 || len(result.Data) != 250 || result.Pages != 3 {
+		t.Errorf("expected 250 tweets in 3 pages, got %d in %d", len(result.Data), result.Pages)
+	}
+	if fmt.Sprint(pageSizes) != "[100 100 50]" {
+		t.Errorf("expected the last page to ask only for the remaining tweets, got %v", pageSizes)
+	}
+	if len(result.Includes.Users) != 1 {
+		t.Errorf("expected authors without duplicates, got %d", len(result.Includes.Users))
+	}
+	if result.Meta.NextToken == "" {
+		t.Errorf("expected the next token to be kept when stopping at the cap")
+	}
+
+	pageSizes = nil
+	result, _ = collectTweetPages(500, 1, fakeTweetPages(120, &pageSizes))
+	if len(result.Data) != 120 || result.Pages != 2 || result.Meta.NextToken != "" {
+		t.Errorf("expected to stop without next token, got %d tweets in %d pages (next: '%s')", len(result.Data), result.Pages, result.Meta.NextToken)
+	}
+
+	// Endpoints with a minimum page size never get asked for less
+	pageSizes = nil
+	collectTweetPages(5, 10, fakeTweetPages(1000, &pageSizes))
+	if fmt.Sprint(pageSizes) != "[10]" {
+		t.Errorf("expected the minimum page size, got %v", pageSizes)
+	}
+
+	// Dropping tweets past the cap clears the next token, which would skip them
+	pageSizes = nil
+	result, _ = collectTweetPages(205, 10, fakeTweetPages(1000, &pageSizes))
+	if len(result.Data) != 205 || result.Meta.NextToken != "" || result.Meta.OldestID != "205" {
+		t.Errorf("expected 205 tweets without next token and oldest id '205', got %d (next: '%s', oldest: '%s')",
+			len(result.Data), result.Meta.NextToken, result.Meta.OldestID)
+	}
+
+	pageSizes = nil
+	collectTweetPages(10000, 1, fakeTweetPages(10000, &pageSizes))
+	if len(pageSizes) != MaxPaginatedTweets/tweetsPageMaxResults {
+		t.Errorf("expected the total to be capped at %d, got %d pages", MaxPaginatedTweets, len(pageSizes))
+	}
+}
+
+func TestCollectTweetPagesRateLimited(t *testing.T) {
+	var pageSizes []int
+	pages := fakeTweetPages(1000, &pageSizes)
+	rateLimited := newAPIError(http.StatusTooManyRequests, "Too Many Requests")
+
+	result, err := collectTweetPages(300, 1, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		if paginationToken == "from-200" {
+			return nil, rateLimited
+		}
+		return pages(pageSize, paginationToken)
+	})
+	if err != nil {
+		t.Fatalf("expected collected tweets to be returned, got: %v", err)
+	}
+	if !result.RateLimited || len(result.Data) != 200 || result.Meta.NextToken != "from-200" {
+		t.Errorf("expected 200 tweets and the failed page token, got %d (rate limited: %v, next: '%s')",
+			len(result.Data), result.RateLimited, result.Meta.NextToken)
+	}
+
+	_, err = collectTweetPages(300, 1, func(pageSize int, paginationToken string) (*TweetsResponse, error) {
+		return nil, rateLimited
+	})
+	if err == nil {
+		t.Errorf("expected an error when the first page fails")
+	}
+}
+
+func TestSearchTweetsInRangeAllFollowsNextToken(t *testing.T) {
+	var startTimes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTimes = append(startTimes, r.URL.Query().Get("start_time"))
+
+		if r.URL.Query().Get("next_token") == "" {
+			w.Write([]byte(`{"data": [{"id": "1"}], "meta": {"result_count": 1, "newest_id": "1", "next_token": "page2"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "2"}], "meta": {"result_count": 1, "oldest_id": "2"}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+
+	tweets, err := client.SearchTweetsInRangeAll(context.Background(), "golang", time.Time{}, time.Time{}, 100, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tweets.Data) != 2 || tweets.Pages != 2 {
+		t.Errorf("expected 2 tweets in 2 pages, got %d in %d", len(tweets.Data), tweets.Pages)
+	}
+	if tweets.Meta.NewestID != "1" || tweets.Meta.OldestID != "2" {
+		t.Errorf("expected newest and oldest IDs across pages, got %+v", tweets.Meta)
+	}
+	if len(startTimes) != 2 || startTimes[0] != startTimes[1] {
+		t.Errorf("expected every page to search the same window, got %v", startTimes)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is how many times a request is tried when no retry policy is set
+	DefaultRetryMaxAttempts = 3
+
+	// DefaultRetryBaseDelay is the wait before the first retry, doubled on every following one
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// maxRetryDelay caps the wait between two attempts
+	maxRetryDelay = 30 * time.Second
+)
+
+// RetryPolicy decides how requests answered with 429 or 5xx are retried.
+// Other errors, like the rest of 4xx or network failures, are returned right away
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is tried, the first one included. 1 disables retries
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry. It doubles on every retry, with jitter
+	BaseDelay time.Duration
+
+	// RetryWrites also retries POST and PUT requests, which may be applied twice when the API
+	// failed after doing them
+	RetryWrites bool
+}
+
+// doWithRetry calls request until it succeeds, fails with an error not worth retrying,
+// the attempts of the retry policy run out or the context is done
+func (c *Client) doWithRetry(ctx context.Context, method string, request func() ([]byte, error)) ([]byte, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+	if !c.canRetry(method) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		body, err := request()
+		if err == nil || attempt >= attempts || !isRetryable(err) {
+			return body, err
+		}
+
+		timer := time.NewTimer(retryDelay(c.retry.BaseDelay, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// canRetry checks whether requests with the given method can be sent again without side effects.
+// Deletes are idempotent, see DeleteTweet and undoError
+func (c *Client) canRetry(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return c.retry.RetryWrites
+}
+
+// isRetryable checks whether an error comes from a rate limit or a server error
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+}
+
+// retryDelay returns the wait before the given retry: the base delay doubled on every retry,
+// capped to maxRetryDelay, of which a random half is kept so concurrent callers do not retry together
+func retryDelay(baseDelay time.Duration, attempt int) time.Duration {
+	if baseDelay <= 0 {
+		return 0
+	}
+
+	delay := baseDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoWithRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		statuses         []int
+		retryWrites      bool
+		expectedAttempts int32
+		expectErr        bool
+	}{
+		{name: "read recovers from a rate limit", method: http.MethodGet, statuses: []int{429, 200}, expectedAttempts: 2},
+		{name: "read recovers from server errors", method: http.MethodGet, statuses: []int{503, 500, 200}, expectedAttempts: 3},
+		{name: "read gives up after max attempts", method: http.MethodGet, statuses: []int{500, 500, 500, 500}, expectedAttempts: 3, expectErr: true},
+		{name: "client errors are not retried", method: http.MethodGet, statuses: []int{400, 200}, expectedAttempts: 1, expectErr: true},
+		{name: "delete is retried", method: http.MethodDelete, statuses: []int{502, 200}, expectedAttempts: 2},
+		{name: "write is not retried by default", method: http.MethodPost, statuses: []int{503, 200}, expectedAttempts: 1, expectErr: true},
+		{name: "write retried when the policy allows it", method: http.MethodPost, statuses: []int{503, 200}, retryWrites: true, expectedAttempts: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				attempt := attempts.Add(1)
+				w.WriteHeader(test.statuses[attempt-1])
+				w.Write([]byte(`{"data": {}}`))
+			})
+			client.retry.RetryWrites = test.retryWrites
+
+			_, err := client.doRequestV2(context.Background(), test.method, "/tweets", nil)
+			if (err != nil) != test.expectErr {
+				t.Errorf("expected error %v, got %v", test.expectErr, err)
+			}
+			if attempts.Load() != test.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", test.expectedAttempts, attempts.Load())
+			}
+		})
+	}
+}
+
+func TestDoWithRetryStopsOnCancel(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.retry = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.doRequestV2(ctx, http.MethodGet, "/tweets", nil); !hasStatusCode(err, http.StatusServiceUnavailable) {
+		t.Errorf("expected the last API error, got %v", err)
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("expected no retries after the context is done, got %d attempts", attempts.Load())
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempt := 1; attempt <= 3; attempt++ {
+		full := 100 * time.Millisecond << (attempt - 1)
+		if delay := retryDelay(100*time.Millisecond, attempt); delay < full/2 || delay > full {
+			t.Errorf("attempt %d: expected a delay between %v and %v, got %v", attempt, full/2, full, delay)
+		}
+	}
+
+	if delay := retryDelay(time.Second, 40); delay > maxRetryDelay {
+		t.Errorf("expected the delay to be capped to %v, got %v", maxRetryDelay, delay)
+	}
+}
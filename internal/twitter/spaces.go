@@ -0,0 +1,113 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	// spaceFields are the Space fields requested from the API
+	spaceFields = "title,state,host_ids,participant_count,scheduled_start,started_at,ended_at,lang"
+)
+
+// spaceStates are the Space states accepted by the search endpoint
+var spaceStates = []string{"all", "live", "scheduled"}
+
+// Space represents a Twitter Space (live audio conversation)
+type Space struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title,omitempty"`
+	State            string    `json:"state"` // live, scheduled or ended
+	HostIDs          []string  `json:"host_ids,omitempty"`
+	ParticipantCount int       `json:"participant_count"`
+	Lang             string    `json:"lang,omitempty"`
+	ScheduledStart   time.Time `json:"scheduled_start,omitzero"`
+	StartedAt        time.Time `json:"started_at,omitzero"`
+	EndedAt          time.Time `json:"ended_at,omitzero"`
+}
+
+// SpacesResponse represents multiple Spaces
+type SpacesResponse struct {
+	Data []Space `json:"data"`
+	Meta struct {
+		ResultCount int `json:"result_count"`
+	} `json:"meta"`
+}
+
+// SearchSpaces searches live and scheduled Spaces by title (v2 API).
+// State is one of 'all', 'live' or 'scheduled', and defaults to 'all'
+func (c *Client) SearchSpaces(ctx context.Context, query string, state string) (*SpacesResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if state == "" {
+		state = "all"
+	}
+	if !slices.Contains(spaceStates, state) {
+		return nil, fmt.Errorf("invalid state '%s': must be one of %v", state, spaceStates)
+	}
+
+	endpoint := fmt.Sprintf("/spaces/search?query=%s&state=%s&space.fields=%s", url.QueryEscape(query), state, spaceFields)
+
+	body, err := c.doRequestV2(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, spacesAccessError(err)
+	}
+
+	var response SpacesResponse
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse spaces response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetSpace gets a Space by its ID (v2 API)
+func (c *Client) GetSpace(ctx context.Context, spaceID string) (*Space, error) {
+	if spaceID == "" {
+		return nil, fmt.Errorf("space ID is required")
+	}
+
+	body, err := c.doRequestV2(ctx, "GET", "/spaces/"+url.PathEscape(spaceID)+"?space.fields="+spaceFields, nil)
+	if err != nil {
+		return nil, spacesAccessError(err)
+	}
+
+	var response struct {
+		Data *Space `json:"data"`
+	}
+	if err := decodeResponse(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse space response: %w", err)
+	}
+	if response.Data == nil {
+		return nil, fmt.Errorf("space '%s' not found", spaceID)
+	}
+
+	return response.Data, nil
+}
+
+// spacesAccessError explains errors caused by API tiers without access to the Spaces endpoints
+func spacesAccessError(err error) error {
+	if hasStatusCode(err, http.StatusForbidden) {
+		return fmt.Errorf("not authorized for Spaces: this account or API tier has no access to them: %w", err)
+	}
+	return err
+}
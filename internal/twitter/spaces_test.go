@@ -0,0 +1,49 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearchSpacesValidation(t *testing.T) {
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+
+	if _, err := client.SearchSpaces(context.Background(), "", ""); err == nil {
+		t.Errorf("expected error for empty query")
+	}
+	if _, err := client.SearchSpaces(context.Background(), "golang", "ended"); err == nil {
+		t.Errorf("expected error for invalid state")
+	}
+	if _, err := client.GetSpace(context.Background(), ""); err == nil {
+		t.Errorf("expected error for empty space ID")
+	}
+}
+
+func TestSpacesAccessError(t *testing.T) {
+	err := spacesAccessError(newAPIError(403, `{"title": "Forbidden"}`))
+	if !hasStatusCode(err, 403) {
+		t.Errorf("expected wrapped API error to keep its status code")
+	}
+	if details := GetErrorDetails(err); details.Code != "forbidden" {
+		t.Errorf("expected 'forbidden' code, got '%s'", details.Code)
+	}
+
+	otherErr := newAPIError(500, "")
+	if spacesAccessError(otherErr) != otherErr {
+		t.Errorf("expected non-403 errors to be returned untouched")
+	}
+}
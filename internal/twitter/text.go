@@ -0,0 +1,167 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTweetLength is the max weighted length of a tweet
+	MaxTweetLength = 280
+
+	// maxTweetMedia is the max number of media attached to a tweet
+	maxTweetMedia = 4
+
+	// tweetURLLength is what every link counts towards the tweet length, as they are shortened with t.co
+	tweetURLLength = 23
+
+	// maxDisplayURLLength is the length links are truncated to when displayed
+	maxDisplayURLLength = 26
+)
+
+var (
+	tweetURLPattern     = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+[^\s<>".,:;!?)\]'"]`)
+	tweetMentionPattern = regexp.MustCompile(`(?:^|[^\w@])@(\w{1,15})\b`)
+	tweetHashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#])#([\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`)
+	tweetCashtagPattern = regexp.MustCompile(`(?:^|\s)\$([A-Za-z]{1,6}(?:[._][A-Za-z]{1,2})?)\b`)
+)
+
+// TweetPreview represents how a tweet would be counted and displayed, without posting it
+type TweetPreview struct {
+	Text           string   `json:"text"`
+	Rendered       string   `json:"rendered"`
+	WeightedLength int      `json:"weighted_length"`
+	MaxLength      int      `json:"max_length"`
+	ExceedsLimit   bool     `json:"exceeds_limit"`
+	URLs           []string `json:"urls"`
+	Mentions       []string `json:"mentions"`
+	Hashtags       []string `json:"hashtags"`
+	Cashtags       []string `json:"cashtags"`
+
+	Media []MediaPreview `json:"media,omitempty"`
+}
+
+// MediaPreview represents a media file checked for upload
+type MediaPreview struct {
+	Path  string `json:"path"`
+	Type  string `json:"type,omitempty"`
+	Size  int    `json:"size,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PreviewTweet counts, parses and renders a tweet text like Twitter does, without any API call.
+// Media files, when given, are checked for upload the same way UploadMedia does
+func PreviewTweet(text string, mediaPaths []string) (*TweetPreview, error) {
+	if len(mediaPaths) > maxTweetMedia {
+		return nil, fmt.Errorf("too many media: max %d per tweet", maxTweetMedia)
+	}
+
+	preview := &TweetPreview{
+		Text:           text,
+		Rendered:       text,
+		WeightedLength: WeightedTweetLength(text),
+		MaxLength:      MaxTweetLength,
+		URLs:           tweetURLPattern.FindAllString(text, -1),
+		Mentions:       submatches(tweetMentionPattern, text),
+		Hashtags:       submatches(tweetHashtagPattern, text),
+		Cashtags:       submatches(tweetCashtagPattern, text),
+	}
+	preview.ExceedsLimit = preview.WeightedLength > MaxTweetLength
+
+	if preview.URLs == nil {
+		preview.URLs = []string{}
+	}
+	preview.Rendered = tweetURLPattern.ReplaceAllStringFunc(text, displayURL)
+
+	for _, mediaPath := range mediaPaths {
+		mediaPreview := MediaPreview{Path: mediaPath}
+
+		data, err := os.ReadFile(mediaPath)
+		if err != nil {
+			mediaPreview.Error = "failed to read media file"
+			preview.Media = append(preview.Media, mediaPreview)
+			continue
+		}
+
+		mediaPreview.Size = len(data)
+		mediaPreview.Type, _, err = detectMediaType(data)
+		if err != nil {
+			mediaPreview.Error = err.Error()
+		}
+		preview.Media = append(preview.Media, mediaPreview)
+	}
+
+	return preview, nil
+}
+
+// ValidateTweetText checks a tweet text is not blank and fits in MaxTweetLength, without any API call
+func ValidateTweetText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("text is empty")
+	}
+
+	if length := WeightedTweetLength(text); length > MaxTweetLength {
+		return fmt.Errorf("text is %d characters long, over the %d limit", length, MaxTweetLength)
+	}
+	return nil
+}
+
+// WeightedTweetLength computes the length of a tweet text the way Twitter does.
+// Links count as 23 characters, Latin-like characters count as 1 and the rest, like CJK or emoji, as 2.
+// Zero-width joiners, variation selectors and skin tone modifiers inside emoji sequences count as 0
+func WeightedTweetLength(text string) int {
+	length := 0
+
+	rest := tweetURLPattern.ReplaceAllStringFunc(text, func(string) string {
+		length += tweetURLLength
+		return ""
+	})
+
+	for _, r := range rest {
+		switch {
+		case r == 0x200d || (r >= 0xfe00 && r <= 0xfe0f) || (r >= 0x1f3fb && r <= 0x1f3ff):
+			continue
+		case r <= 0x10ff || (r >= 0x2000 && r <= 0x200c) || (r >= 0x2010 && r <= 0x201f) || (r >= 0x2032 && r <= 0x2037):
+			length++
+		default:
+			length += 2
+		}
+	}
+
+	return length
+}
+
+// displayURL shortens a link the way Twitter displays it: without scheme and truncated
+func displayURL(link string) string {
+	display := strings.TrimPrefix(strings.TrimPrefix(link, "https://"), "http://")
+	if utf8.RuneCountInString(display) > maxDisplayURLLength {
+		display = string([]rune(display)[:maxDisplayURLLength-1]) + "…"
+	}
+	return display
+}
+
+// submatches returns the first capture group of every match, never nil
+func submatches(pattern *regexp.Regexp, text string) []string {
+	result := []string{}
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
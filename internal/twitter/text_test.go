@@ -0,0 +1,126 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWeightedTweetLength(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"héllo wörld", 11},
+		{"こんにちは", 10},
+		{"👍", 2},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 6},
+		{"read https://example.com/a/very/long/path/that/is/shortened", 28},
+		{strings.Repeat("a", 280), 280},
+	}
+
+	for _, tt := range tests {
+		if result := WeightedTweetLength(tt.text); result != tt.expected {
+			t.Errorf("WeightedTweetLength(%q) = %d, expected %d", tt.text, result, tt.expected)
+		}
+	}
+}
+
+func TestPreviewTweet(t *testing.T) {
+	text := "Hey @achetronic, check https://github.com/achetronic/twitter-mcp/blob/main/README.md #golang #MCP $TSLA"
+
+	preview, err := PreviewTweet(text, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(preview.Mentions, []string{"achetronic"}) {
+		t.Errorf("unexpected mentions: %v", preview.Mentions)
+	}
+	if !slices.Equal(preview.Hashtags, []string{"golang", "MCP"}) {
+		t.Errorf("unexpected hashtags: %v", preview.Hashtags)
+	}
+	if !slices.Equal(preview.Cashtags, []string{"TSLA"}) {
+		t.Errorf("unexpected cashtags: %v", preview.Cashtags)
+	}
+	if len(preview.URLs) != 1 || !strings.HasSuffix(preview.URLs[0], "README.md") {
+		t.Errorf("unexpected urls: %v", preview.URLs)
+	}
+	if !strings.Contains(preview.Rendered, "check github.com/achetronic/twi… #golang") {
+		t.Errorf("expected shortened link in rendering, got '%s'", preview.Rendered)
+	}
+	if preview.ExceedsLimit {
+		t.Errorf("expected tweet within the limit, got length %d", preview.WeightedLength)
+	}
+
+	long, _ := PreviewTweet(strings.Repeat("あ", 141), nil)
+	if !long.ExceedsLimit || long.WeightedLength != 282 {
+		t.Errorf("expected 282 weighted length over the limit, got %d", long.WeightedLength)
+	}
+}
+
+func TestPreviewTweetMedia(t *testing.T) {
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "image.png")
+	textPath := filepath.Join(dir, "notes.txt")
+	os.WriteFile(pngPath, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0o600)
+	os.WriteFile(textPath, []byte("not an image"), 0o600)
+
+	preview, err := PreviewTweet("hello", []string{pngPath, textPath, filepath.Join(dir, "missing.png")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if preview.Media[0].Type != "image/png" || preview.Media[0].Error != "" {
+		t.Errorf("expected valid png, got %+v", preview.Media[0])
+	}
+	if preview.Media[1].Error == "" || preview.Media[2].Error == "" {
+		t.Errorf("expected errors for unsupported and missing media, got %+v", preview.Media[1:])
+	}
+
+	if _, err := PreviewTweet("hello", make([]string, 5)); err == nil {
+		t.Errorf("expected error for too many media")
+	}
+}
+
+func TestValidateTweetText(t *testing.T) {
+	tests := map[string]struct {
+		text    string
+		wantErr bool
+	}{
+		"valid":         {text: "Hello world"},
+		"at the limit":  {text: strings.Repeat("a", MaxTweetLength)},
+		"empty":         {text: "", wantErr: true},
+		"blank":         {text: " \n\t", wantErr: true},
+		"too long":      {text: strings.Repeat("a", MaxTweetLength+1), wantErr: true},
+		"wide too long": {text: strings.Repeat("世", MaxTweetLength/2+1), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTweetText(test.text)
+			if (err != nil) != test.wantErr {
+				t.Errorf("expected error: %v, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
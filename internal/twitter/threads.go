@@ -0,0 +1,73 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GetThreadTweetIDs discovers a thread of the given author from its root tweet: the root and the author's replies
+// in its conversation, oldest first (v2 API). It relies on the recent search, so only replies of the last 7 days are found
+func (c *Client) GetThreadTweetIDs(ctx context.Context, rootTweetID, authorUsername string) ([]string, error) {
+	if rootTweetID == "" {
+		return nil, fmt.Errorf("root tweet ID is required")
+	}
+
+	// A minute of margin, so the start time is still within the window when the request arrives
+	startTime := time.Now().UTC().Add(-recentSearchWindow + time.Minute)
+	query := fmt.Sprintf("conversation_id:%s from:%s", rootTweetID, authorUsername)
+
+	replies, err := c.SearchTweetsInRangeAll(ctx, query, startTime, time.Time{}, MaxPaginatedTweets, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the thread replies: %w", err)
+	}
+
+	ids := []string{rootTweetID}
+	for _, tweet := range replies.Data {
+		if !slices.Contains(ids, tweet.ID) {
+			ids = append(ids, tweet.ID)
+		}
+	}
+
+	// Tweet IDs grow over time, so shorter IDs are older and same length ones compare as strings
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+	return ids, nil
+}
+
+// DeleteThread deletes the tweets of a thread, given oldest first, starting from the last one (v2 API with OAuth 1.0a
+// user context). Failures don't stop the rest, and results follow the deletion order
+func (c *Client) DeleteThread(ctx context.Context, tweetIDs []string) (*BatchSummary, error) {
+	if len(tweetIDs) == 0 {
+		return nil, fmt.Errorf("at least one tweet ID is required")
+	}
+	if len(tweetIDs) > MaxBatchSize {
+		return nil, fmt.Errorf("too many tweets: max %d per call", MaxBatchSize)
+	}
+
+	reversed := slices.Clone(tweetIDs)
+	slices.Reverse(reversed)
+
+	// One at a time, so replies are gone before the tweets they reply to
+	summary := runBatch(reversed, 1, func(tweetID string) error {
+		return c.DeleteTweet(ctx, tweetID)
+	})
+	return &summary, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDeleteThread(t *testing.T) {
+	var mutex sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tweetID := strings.TrimPrefix(r.URL.Path, "/2/tweets/")
+		if tweetID == "2" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"title": "Forbidden", "detail": "not your tweet"}`))
+			return
+		}
+
+		mutex.Lock()
+		deleted = append(deleted, tweetID)
+		mutex.Unlock()
+		w.Write([]byte(`{"data": {"deleted": true}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	summary, err := client.DeleteThread(context.Background(), []string{"1", "2", "3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(deleted, []string{"3", "1"}) {
+		t.Errorf("expected deletions from the last tweet, got %v", deleted)
+	}
+	if summary.Succeeded != 2 || summary.Failed != 1 {
+		t.Errorf("expected 2 deleted and 1 failed, got %+v", summary)
+	}
+	if summary.Results[1].Item != "2" || summary.Results[1].Success {
+		t.Errorf("expected the second result to be the failed tweet, got %+v", summary.Results[1])
+	}
+
+	if _, err := client.DeleteThread(context.Background(), nil); err == nil {
+		t.Errorf("expected error without tweets")
+	}
+}
+
+func TestGetThreadTweetIDs(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(`{"data": [{"id": "1000"}, {"id": "999"}, {"id": "5"}], "meta": {"result_count": 3}}`))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.httpClient = &http.Client{Transport: redirectTransport{target: target}}
+
+	ids, err := client.GetThreadTweetIDs(context.Background(), "5", "me")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "conversation_id:5 from:me" {
+		t.Errorf("unexpected query '%s'", query)
+	}
+	if !slices.Equal(ids, []string{"5", "999", "1000"}) {
+		t.Errorf("expected the root and its replies oldest first, got %v", ids)
+	}
+}
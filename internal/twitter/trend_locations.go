@@ -0,0 +1,201 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// trendLocationsTTL is how long the available trend locations are cached.
+	// Twitter barely changes this list, so there is no point in asking often
+	trendLocationsTTL = 24 * time.Hour
+)
+
+// TrendLocationAliases maps friendly location names, in lowercase, to their WOEIDs.
+// They resolve without calling the API. Other names are looked up in the available trend locations
+var TrendLocationAliases = map[string]int{
+	"worldwide":      1,
+	"world":          1,
+	"global":         1,
+	"usa":            23424977,
+	"us":             23424977,
+	"united states":  23424977,
+	"uk":             23424975,
+	"gb":             23424975,
+	"united kingdom": 23424975,
+	"spain":          23424950,
+	"es":             23424950,
+	"france":         23424819,
+	"germany":        23424829,
+	"mexico":         23424900,
+	"argentina":      23424747,
+	"brazil":         23424768,
+	"canada":         23424775,
+	"india":          23424848,
+	"japan":          23424856,
+	"madrid":         766273,
+	"barcelona":      753692,
+	"london":         44418,
+	"paris":          615702,
+	"new york":       2459115,
+	"nyc":            2459115,
+	"los angeles":    2442047,
+	"tokyo":          1118370,
+}
+
+// TrendLocation represents a location for which Twitter has trending topics
+type TrendLocation struct {
+	Name        string `json:"name"`
+	WOEID       int    `json:"woeid"`
+	Country     string `json:"country"`
+	CountryCode string `json:"countryCode,omitempty"`
+	PlaceType   struct {
+		Code int    `json:"code"`
+		Name string `json:"name"`
+	} `json:"placeType"`
+}
+
+// trendLocationsCache holds the last fetched list of trend locations
+type trendLocationsCache struct {
+	mutex     sync.Mutex
+	locations []TrendLocation
+	expiresAt time.Time
+}
+
+// GetAvailableTrendLocations gets the locations that have trending topics (v1.1 API)
+// The list is cached in memory for a day
+func (c *Client) GetAvailableTrendLocations(ctx context.Context) ([]TrendLocation, error) {
+	c.trendLocations.mutex.Lock()
+	defer c.trendLocations.mutex.Unlock()
+
+	if c.trendLocations.locations != nil && time.Now().Before(c.trendLocations.expiresAt) {
+		return c.trendLocations.locations, nil
+	}
+
+	body, err := c.doRequestV1(ctx, "GET", "/trends/available.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []TrendLocation
+	if err := decodeResponse(body, &locations); err != nil {
+		return nil, fmt.Errorf("failed to parse trend locations response: %w", err)
+	}
+
+	c.trendLocations.locations = locations
+	c.trendLocations.expiresAt = time.Now().Add(trendLocationsTTL)
+
+	return locations, nil
+}
+
+// ResolveTrendLocation finds the WOEID of a trend location by its name.
+// Names in TrendLocationAliases resolve right away, the rest against the available trend locations
+func (c *Client) ResolveTrendLocation(ctx context.Context, name string) (int, error) {
+	if woeid, found := TrendLocationAliases[strings.ToLower(strings.TrimSpace(name))]; found {
+		return woeid, nil
+	}
+
+	locations, err := c.GetAvailableTrendLocations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	location, err := matchTrendLocation(locations, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return location.WOEID, nil
+}
+
+// GetClosestTrendLocations gets the locations with trending topics closest to the given coordinates (v1.1 API)
+func (c *Client) GetClosestTrendLocations(ctx context.Context, lat, long float64) ([]TrendLocation, error) {
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("latitude must be between -90 and 90, got %g", lat)
+	}
+	if long < -180 || long > 180 {
+		return nil, fmt.Errorf("longitude must be between -180 and 180, got %g", long)
+	}
+
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("long", strconv.FormatFloat(long, 'f', -1, 64))
+
+	body, err := c.doRequestV1(ctx, "GET", "/trends/closest.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []TrendLocation
+	if err := decodeResponse(body, &locations); err != nil {
+		return nil, fmt.Errorf("failed to parse closest trend locations response: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("no trend location found near %g,%g", lat, long)
+	}
+
+	return locations, nil
+}
+
+// matchTrendLocation looks for a location by name, case-insensitively.
+// Exact matches on the name or the country win. Otherwise, the name must be contained in
+// exactly one location, so ambiguous names are rejected instead of guessed
+func matchTrendLocation(locations []TrendLocation, name string) (*TrendLocation, error) {
+	needle := strings.ToLower(strings.TrimSpace(name))
+	if needle == "" {
+		return nil, fmt.Errorf("location name is required")
+	}
+
+	for i := range locations {
+		if strings.ToLower(locations[i].Name) == needle {
+			return &locations[i], nil
+		}
+	}
+
+	// Countries also appear as locations on their own, so 'spain' resolves to the country
+	for i := range locations {
+		if locations[i].PlaceType.Name == "Country" && strings.ToLower(locations[i].Country) == needle {
+			return &locations[i], nil
+		}
+	}
+
+	var candidates []*TrendLocation
+	for i := range locations {
+		if strings.Contains(strings.ToLower(locations[i].Name), needle) {
+			candidates = append(candidates, &locations[i])
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no trend location matches '%s', use list_trend_locations to see the available ones", name)
+	case 1:
+		return candidates[0], nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		names = append(names, fmt.Sprintf("%s (%s)", candidate.Name, candidate.Country))
+	}
+	return nil, fmt.Errorf("location name '%s' is ambiguous, candidates: %s", name, strings.Join(names, ", "))
+}
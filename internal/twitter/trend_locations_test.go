@@ -0,0 +1,138 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchTrendLocation(t *testing.T) {
+	locations := []TrendLocation{
+		{Name: "Worldwide", WOEID: 1},
+		{Name: "Spain", WOEID: 23424950, Country: "Spain"},
+		{Name: "Madrid", WOEID: 766273, Country: "Spain"},
+		{Name: "New York", WOEID: 2459115, Country: "United States"},
+		{Name: "New Haven", WOEID: 2458410, Country: "United States"},
+		{Name: "United States", WOEID: 23424977, Country: "United States"},
+	}
+	locations[1].PlaceType.Name = "Country"
+	locations[5].PlaceType.Name = "Country"
+
+	tests := []struct {
+		name          string
+		expectedWOEID int
+		expectedError string
+	}{
+		{name: "madrid", expectedWOEID: 766273},
+		{name: "  WORLDWIDE ", expectedWOEID: 1},
+		{name: "york", expectedWOEID: 2459115},
+		{name: "new", expectedError: "ambiguous"},
+		{name: "atlantis", expectedError: "no trend location matches"},
+		{name: "", expectedError: "required"},
+	}
+
+	for _, tt := range tests {
+		location, err := matchTrendLocation(locations, tt.name)
+		if tt.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("matchTrendLocation(%q): expected error containing '%s', got %v", tt.name, tt.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("matchTrendLocation(%q): unexpected error: %v", tt.name, err)
+			continue
+		}
+		if location.WOEID != tt.expectedWOEID {
+			t.Errorf("matchTrendLocation(%q) = %d, expected %d", tt.name, location.WOEID, tt.expectedWOEID)
+		}
+	}
+}
+
+func TestGetAvailableTrendLocationsUsesCache(t *testing.T) {
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.trendLocations.locations = []TrendLocation{{Name: "Valencia", WOEID: 776688}}
+	client.trendLocations.expiresAt = time.Now().Add(time.Hour)
+
+	woeid, err := client.ResolveTrendLocation(context.Background(), "valencia")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if woeid != 776688 {
+		t.Errorf("expected WOEID 776688, got %d", woeid)
+	}
+}
+
+func TestResolveTrendLocationAliases(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`[{"name": "Valencia", "woeid": 776688, "country": "Spain", "placeType": {"code": 7, "name": "Town"}}]`))
+	})
+
+	tests := []struct {
+		name          string
+		expectedWOEID int
+	}{
+		{"worldwide", 1},
+		{" USA ", 23424977},
+		{"UK", 23424975},
+		{"Madrid", 766273},
+		{"nyc", 2459115},
+	}
+
+	for _, tt := range tests {
+		woeid, err := client.ResolveTrendLocation(context.Background(), tt.name)
+		if err != nil {
+			t.Fatalf("ResolveTrendLocation(%q): unexpected error: %v", tt.name, err)
+		}
+		if woeid != tt.expectedWOEID {
+			t.Errorf("ResolveTrendLocation(%q) = %d, expected %d", tt.name, woeid, tt.expectedWOEID)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected aliases to resolve without calling the API, got %d calls", calls)
+	}
+
+	// Unknown names fall back to the available locations
+	woeid, err := client.ResolveTrendLocation(context.Background(), "valencia")
+	if err != nil || woeid != 776688 || calls != 1 {
+		t.Errorf("expected a lookup resolving to 776688, got %d (%v) after %d calls", woeid, err, calls)
+	}
+}
+
+func TestGetClosestTrendLocationsValidation(t *testing.T) {
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+
+	tests := []struct {
+		lat  float64
+		long float64
+	}{
+		{lat: 91, long: 0},
+		{lat: -90.5, long: 0},
+		{lat: 0, long: 180.1},
+		{lat: 0, long: -181},
+	}
+
+	for _, tt := range tests {
+		if _, err := client.GetClosestTrendLocations(context.Background(), tt.lat, tt.long); err == nil {
+			t.Errorf("GetClosestTrendLocations(%g, %g): expected validation error", tt.lat, tt.long)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTrendsCacheTTL is how long trends are cached per location when not configured.
+	// X refreshes them about every 5 minutes
+	DefaultTrendsCacheTTL = 5 * time.Minute
+)
+
+// TrendsSnapshot represents the trends of a location as they were when fetched
+type TrendsSnapshot struct {
+	WOEID     int
+	Trends    []Trend
+	FetchedAt time.Time
+}
+
+// trendsCache holds the last fetched trends per WOEID
+type trendsCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[int]TrendsSnapshot
+}
+
+// GetTrends gets trending topics for a location (v1.1 API), see GetTrendsSnapshot
+// WOEID: 1 = Worldwide, 23424950 = Spain, 766273 = Madrid
+func (c *Client) GetTrends(ctx context.Context, woeid int) ([]Trend, error) {
+	snapshot, err := c.GetTrendsSnapshot(ctx, woeid)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Trends, nil
+}
+
+// GetTrendsSnapshot gets trending topics for a location (v1.1 API), along with when they were fetched.
+// Trends are cached per WOEID for the TTL set with WithTrendsCacheTTL (default: 5m)
+func (c *Client) GetTrendsSnapshot(ctx context.Context, woeid int) (*TrendsSnapshot, error) {
+	if woeid <= 0 {
+		woeid = 1 // Worldwide
+	}
+
+	c.trends.mutex.Lock()
+	defer c.trends.mutex.Unlock()
+
+	if cached, found := c.trends.entries[woeid]; found && time.Since(cached.FetchedAt) < c.trends.ttl {
+		return &cached, nil
+	}
+
+	trends, err := c.fetchTrends(ctx, woeid)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := TrendsSnapshot{WOEID: woeid, Trends: trends, FetchedAt: time.Now()}
+	if c.trends.ttl > 0 {
+		if c.trends.entries == nil {
+			c.trends.entries = make(map[int]TrendsSnapshot)
+		}
+		c.trends.entries[woeid] = snapshot
+	}
+
+	return &snapshot, nil
+}
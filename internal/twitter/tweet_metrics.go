@@ -0,0 +1,78 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+)
+
+// TweetMetrics represents the metrics of a single tweet. Private ones are only set for tweets
+// of the authenticated user, and PrivateMetricsUnavailable tells why they are missing otherwise
+type TweetMetrics struct {
+	TweetID   string `json:"tweet_id"`
+	AuthorID  string `json:"author_id,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Owned     bool   `json:"owned"`
+
+	PublicMetrics    *PublicMetrics    `json:"public_metrics,omitempty"`
+	NonPublicMetrics *NonPublicMetrics `json:"non_public_metrics,omitempty"`
+	OrganicMetrics   *OrganicMetrics   `json:"organic_metrics,omitempty"`
+
+	PrivateMetricsUnavailable string `json:"private_metrics_unavailable,omitempty"`
+}
+
+// GetTweetMetrics gets every metric available for a tweet (v2 API with user context).
+// Asking private metrics of a tweet of someone else fails the whole request, so they are only asked
+// for own tweets, and failures there (e.g. tweets older than 30 days) keep the public ones
+func (c *Client) GetTweetMetrics(ctx context.Context, tweetID string) (*TweetMetrics, error) {
+	tweet, err := c.getTweetByID(ctx, tweetID, "created_at,author_id,public_metrics")
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &TweetMetrics{
+		TweetID:       tweet.ID,
+		AuthorID:      tweet.AuthorID,
+		CreatedAt:     tweet.CreatedAt,
+		PublicMetrics: tweet.PublicMetrics,
+	}
+
+	me, err := c.GetMe(ctx)
+	if err != nil {
+		metrics.PrivateMetricsUnavailable = fmt.Sprintf("could not check the tweet author: %s", GetErrorDetails(err).Message)
+		return metrics, nil
+	}
+
+	if tweet.AuthorID != me.ID {
+		metrics.PrivateMetricsUnavailable = "only available for your own tweets"
+		return metrics, nil
+	}
+	metrics.Owned = true
+
+	private, err := c.getTweetByID(ctx, tweetID, "non_public_metrics,organic_metrics")
+	if err != nil {
+		metrics.PrivateMetricsUnavailable = fmt.Sprintf("the API did not return them (only tweets from the last 30 days have them): %s", GetErrorDetails(err).Message)
+		return metrics, nil
+	}
+	if private.NonPublicMetrics == nil && private.OrganicMetrics == nil {
+		metrics.PrivateMetricsUnavailable = "the API did not return them (only tweets from the last 30 days have them)"
+		return metrics, nil
+	}
+
+	metrics.NonPublicMetrics = private.NonPublicMetrics
+	metrics.OrganicMetrics = private.OrganicMetrics
+	return metrics, nil
+}
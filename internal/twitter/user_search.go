@@ -0,0 +1,119 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// userSearchPageSize is the most users the v1.1 users search returns per page
+	userSearchPageSize = 20
+
+	// MaxUserSearchResults caps SearchUsers, as every page is a request against a small rate limit
+	MaxUserSearchResults = 100
+)
+
+// v1User is the subset of a v1.1 user object returned by the users search
+type v1User struct {
+	IDStr           string `json:"id_str"`
+	Name            string `json:"name"`
+	ScreenName      string `json:"screen_name"`
+	Description     string `json:"description"`
+	ProfileImageURL string `json:"profile_image_url_https"`
+	CreatedAt       string `json:"created_at"`
+	FollowersCount  int    `json:"followers_count"`
+	FriendsCount    int    `json:"friends_count"`
+	StatusesCount   int    `json:"statuses_count"`
+	ListedCount     int    `json:"listed_count"`
+	Verified        bool   `json:"verified"`
+	Protected       bool   `json:"protected"`
+}
+
+// profile converts a v1.1 user into the v2 shaped UserProfile used everywhere else
+func (u v1User) profile() UserProfile {
+	return UserProfile{
+		ID:              u.IDStr,
+		Name:            u.Name,
+		Username:        u.ScreenName,
+		Description:     u.Description,
+		ProfileImageURL: u.ProfileImageURL,
+		CreatedAt:       u.CreatedAt,
+		PublicMetrics: &UserMetrics{
+			FollowersCount: u.FollowersCount,
+			FollowingCount: u.FriendsCount,
+			TweetCount:     u.StatusesCount,
+			ListedCount:    u.ListedCount,
+		},
+		Verified:  u.Verified,
+		Protected: u.Protected,
+	}
+}
+
+// SearchUsers searches accounts by name or keyword, following pages up to maxResults (v1.1 API with OAuth 1.0a).
+// This is best-effort: the endpoint is legacy and not available on every account tier
+func (c *Client) SearchUsers(ctx context.Context, query string, maxResults int) (*UsersResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if maxResults <= 0 {
+		maxResults = userSearchPageSize
+	}
+	if maxResults > MaxUserSearchResults {
+		maxResults = MaxUserSearchResults
+	}
+
+	result := UsersResponse{Data: []UserProfile{}}
+	seen := map[string]bool{}
+
+	for page := 1; len(result.Data) < maxResults; page++ {
+		endpoint := fmt.Sprintf("/users/search.json?q=%s&page=%d&count=%d&include_entities=false",
+			url.QueryEscape(query), page, userSearchPageSize)
+
+		body, err := c.doRequestV1(ctx, "GET", endpoint, nil)
+		if hasStatusCode(err, http.StatusForbidden, http.StatusNotFound, http.StatusGone) {
+			return nil, fmt.Errorf("searching users is not supported for this account or API tier: %w", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var users []v1User
+		if err := decodeResponse(body, &users); err != nil {
+			return nil, fmt.Errorf("failed to parse users search response: %w", err)
+		}
+
+		// Past the last page, the endpoint repeats it instead of returning nothing
+		added := 0
+		for _, user := range users {
+			if seen[user.IDStr] || len(result.Data) >= maxResults {
+				continue
+			}
+			seen[user.IDStr] = true
+			result.Data = append(result.Data, user.profile())
+			added++
+		}
+
+		if added == 0 || len(users) < userSearchPageSize {
+			break
+		}
+	}
+
+	result.Meta.ResultCount = len(result.Data)
+	return &result, nil
+}
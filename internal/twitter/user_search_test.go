@@ -0,0 +1,80 @@
+// Copyright 2024 Alby Hernández
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package twitter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestSearchUsersPaginates(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		// Two full pages, then the endpoint repeats the last one
+		if page > 2 {
+			page = 2
+		}
+		w.Write([]byte("["))
+		for i := range userSearchPageSize {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			id := (page-1)*userSearchPageSize + i
+			fmt.Fprintf(w, `{"id_str": "%d", "screen_name": "user%d", "followers_count": %d, "protected": true}`, id, id, id)
+		}
+		w.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+
+	client := NewClient("key", "secret", "token", "tokenSecret", "bearer")
+	client.oauth1Client = &http.Client{Transport: redirectTransport{target: target}}
+
+	users, err := client.SearchUsers(context.Background(), "golang", 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users.Data) != 30 || users.Meta.ResultCount != 30 {
+		t.Fatalf("expected 30 users, got %d", len(users.Data))
+	}
+	if users.Data[25].Username != "user25" || users.Data[25].PublicMetrics.FollowersCount != 25 || !users.Data[25].Protected {
+		t.Errorf("unexpected user: %+v", users.Data[25])
+	}
+
+	pages = nil
+	users, err = client.SearchUsers(context.Background(), "golang", MaxUserSearchResults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users.Data) != 2*userSearchPageSize {
+		t.Errorf("expected repeated pages to be dropped, got %d users", len(users.Data))
+	}
+	if len(pages) != 3 {
+		t.Errorf("expected to stop after the repeated page, got pages %v", pages)
+	}
+
+	if _, err := client.SearchUsers(context.Background(), "", 10); err == nil {
+		t.Errorf("expected error for empty query")
+	}
+}